@@ -1,53 +1,29 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
-	"strings"
 	"unicode"
+
+	"github.com/efulmo/advent-of-code-2023/util"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Usage: %s <input-file-path>\n", os.Args[0])
-		return
-	}
+	defer util.ReportAllocs()
 
-	inputeFilePath := os.Args[1]
-	fmt.Printf("Reading input from file <%s>\n", inputeFilePath)
-
-	data, err := os.ReadFile(inputeFilePath)
-	if err != nil {
-		fmt.Printf("Error reading file <%s>: %s\n", inputeFilePath, err.Error())
-		os.Exit(1)
-	}
-	fmt.Printf("Read %d bytes\n", len(data))
-
-	lines := strings.Split(string(data), "\n")
-	fmt.Printf("Read %d lines\n", len(lines))
+	lines, err := util.ReadInputFile()
+	util.PanicOnError(err)
 
 	var sum uint
 	for lineIdx, line := range lines {
-		leftIdx, rightIdx := -1, -1
-
-		for charIdx, r := range line {
-			if unicode.IsDigit(r) {
-				rightIdx = charIdx
-
-				if leftIdx == -1 {
-					leftIdx = charIdx
-				}
-			}
-		}
-
-		if leftIdx == -1 || rightIdx == -1 {
-			fmt.Printf("Line %d. No numbers found\n", lineIdx)
+		leftDigit, rightDigit, err := parseDigits(line)
+		if err != nil {
+			fmt.Printf("Line %d. %s\n", lineIdx, err.Error())
 			os.Exit(1)
 		}
 
-		leftDigit := string(line[leftIdx])
-		rightDigit := string(line[rightIdx])
 		fmt.Printf("%d. %s. Detected %s, %s\n", lineIdx, line, leftDigit, rightDigit)
 
 		lineValueStr := leftDigit + rightDigit
@@ -62,3 +38,34 @@ func main() {
 
 	fmt.Println("Total sum:", sum)
 }
+
+func parseDigits(line string) (string, string, error) {
+	leftIdx, rightIdx := -1, -1
+
+	for charIdx, r := range line {
+		if unicode.IsDigit(r) {
+			rightIdx = charIdx
+
+			if leftIdx == -1 {
+				leftIdx = charIdx
+			}
+		}
+	}
+
+	if leftIdx == -1 || rightIdx == -1 {
+		return "", "", errors.New("No numbers found")
+	}
+
+	// only ASCII digits are a part of calibration values
+	leftDigit := string(line[leftIdx])
+	rightDigit := string(line[rightIdx])
+	if !isASCIIDigit(leftDigit) || !isASCIIDigit(rightDigit) {
+		return "", "", errors.New("Non-ASCII digit found")
+	}
+
+	return leftDigit, rightDigit, nil
+}
+
+func isASCIIDigit(s string) bool {
+	return len(s) == 1 && s[0] >= '0' && s[0] <= '9'
+}
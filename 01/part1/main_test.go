@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseDigits(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"sample.txt"}, func(t *testing.T, lineIdx int, line string) {
+		parseDigits(line)
+	})
+}
@@ -1,135 +1,378 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
-	"unicode"
+	"sync"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/strmatch"
 )
 
+// Number is a spelling of a digit
 type Number struct {
 	digit uint8
 	chars string
 }
 
-var numbers = []Number{
-	{1, "one"},
-	{2, "two"},
-	{3, "three"},
-	{4, "four"},
-	{5, "five"},
-	{6, "six"},
-	{7, "seven"},
-	{8, "eight"},
-	{9, "nine"},
+// vocabularies are built-in spellings of digits. Digits themselves are always recognized.
+var vocabularies = map[string][]Number{
+	"english": {
+		{1, "one"},
+		{2, "two"},
+		{3, "three"},
+		{4, "four"},
+		{5, "five"},
+		{6, "six"},
+		{7, "seven"},
+		{8, "eight"},
+		{9, "nine"},
+	},
+	"german": {
+		{1, "eins"},
+		{2, "zwei"},
+		{3, "drei"},
+		{4, "vier"},
+		{5, "fünf"},
+		{6, "sechs"},
+		{7, "sieben"},
+		{8, "acht"},
+		{9, "neun"},
+	},
+	"roman": {
+		{1, "I"},
+		{2, "II"},
+		{3, "III"},
+		{4, "IV"},
+		{5, "V"},
+		{6, "VI"},
+		{7, "VII"},
+		{8, "VIII"},
+		{9, "IX"},
+	},
+	"none": nil,
+}
+
+// DigitMatch is a digit spelled with chars found at the offset of a line
+type DigitMatch struct {
+	digit  uint8
+	chars  string
+	offset int
+}
+
+// maxLineLength limits lines in the streaming mode, so a file without line breaks can't take all
+// the memory
+const maxLineLength = 1 << 20
+
+// Decoder finds digits in calibration lines in a single pass over a line whatever the number of
+// spellings is. It's safe for concurrent use.
+type Decoder struct {
+	matcher        *strmatch.Matcher
+	digitByPattern []uint8
 }
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Usage: %s <input-file-path>\n", os.Args[0])
-		return
+	defer util.ReportAllocs()
+
+	vocabulary := flag.String("vocabulary", "english", "built-in spellings of digits: "+
+		strings.Join(util.MapKeysToSortedSlice(vocabularies), ", "))
+	wordsFile := flag.String("words-file", "",
+		"file with more spellings of digits, a word=digit pair per line")
+	words := flag.String("words", "", "more spellings of digits as comma separated word=digit pairs")
+	verbose := flag.Bool("v", false, "print the first and the last digit of every line with offsets")
+	stream := flag.Bool("stream", false, fmt.Sprintf("read the input chunk by chunk and decode "+
+		"chunks in parallel instead of loading it whole; lines aren't printed and must be at most "+
+		"%d bytes long", maxLineLength))
+	workers := flag.Int("workers", 0, "number of goroutines decoding chunks in the streaming mode. "+
+		"0 means the number of CPUs")
+	chunkLines := flag.Int("chunk-lines", 10_000, "number of lines in a chunk in the streaming mode")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *stream && *verbose {
+		fmt.Fprintln(os.Stderr, "Lines aren't printed in the streaming mode, so -v can't be used")
+		os.Exit(2)
+	}
+	if *chunkLines <= 0 {
+		fmt.Fprintln(os.Stderr, "Chunk must have at least 1 line")
+		os.Exit(2)
 	}
 
-	inputeFilePath := os.Args[1]
-	fmt.Printf("Reading input from file <%s>\n", inputeFilePath)
+	numbers, err := loadNumbers(*vocabulary, *wordsFile, *words)
+	util.PanicOnError(err)
+	decoder, err := newDecoder(numbers)
+	util.PanicOnError(err)
 
-	data, err := os.ReadFile(inputeFilePath)
-	if err != nil {
-		fmt.Printf("Error reading file <%s>: %s\n", inputeFilePath, err.Error())
-		os.Exit(1)
+	if *stream {
+		sum, err := streamSum(decoder, flag.Arg(0), *workers, *chunkLines)
+		util.PanicOnError(err)
+		fmt.Println("Total sum:", sum)
+		return
 	}
-	fmt.Printf("Read %d bytes\n", len(data))
 
-	lines := strings.Split(string(data), "\n")
-	fmt.Printf("Read %d lines\n", len(lines))
+	fileLines, err := util.ReadFileLines(flag.Arg(0))
+	util.PanicOnError(err)
+	lines := scannedLines(fileLines)
 
 	var sum uint
 	for lineIdx, line := range lines {
-		var leftDigit, rightDigit string
+		first, last, err := decoder.parseDigits(line)
+		if err != nil {
+			fmt.Printf("Line %d. %s: %s\n", lineIdx, err.Error(), line)
+			os.Exit(1)
+		}
 
-		lineLen := len(line)
-		for charIdx := 0; charIdx < lineLen; charIdx++ {
-			r := rune(line[charIdx])
-			if unicode.IsDigit(r) {
-				rightDigit = string(r)
+		lineValue := uint(first.digit)*10 + uint(last.digit)
+		fmt.Printf("%d. %s. Detected %d, %d => %d\n", lineIdx, line, first.digit, last.digit,
+			lineValue)
+		if *verbose {
+			fmt.Printf("\tfirst %q at offset %d, last %q at offset %d\n", first.chars, first.offset,
+				last.chars, last.offset)
+		}
 
-				if leftDigit == "" {
-					leftDigit = rightDigit
-				}
+		sum += lineValue
+	}
 
-				continue
-			}
+	fmt.Println("Total sum:", sum)
+}
+
+// streamSum sums calibration values of the file without loading it whole
+func streamSum(decoder *Decoder, path string, workers, chunkLines int) (uint, error) {
+	fmt.Printf("Streaming input from file <%s>\n", path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("Error reading file <%s>: %s", path, err.Error())
+	}
+	defer file.Close()
+
+	return decoder.sumStream(file, workers, chunkLines)
+}
+
+// sumStream reads lines chunk by chunk and sums calibration values of chunks on the workers. A
+// chunk is read only once a worker is free, so at most workers+1 chunks are in memory. The error
+// is the one of the first invalid line whatever chunk is decoded first.
+func (d *Decoder) sumStream(r io.Reader, workers, chunkLines int) (uint, error) {
+	var mu sync.Mutex
+	var sum uint
+	var firstErr error
+	firstErrLineIdx := -1
 
-			charDigit := parseCharDigit(line, charIdx)
-			if charDigit != nil {
-				rightDigit = strconv.Itoa(int(charDigit.digit))
+	pool := util.NewPool(workers)
+	submit := func(lines []string, firstLineIdx int) {
+		pool.Go(func() {
+			chunkSum, errLineIdx, err := d.sumLines(lines, firstLineIdx)
 
-				if leftDigit == "" {
-					leftDigit = rightDigit
-				}
+			mu.Lock()
+			defer mu.Unlock()
+			sum += chunkSum
+			if err != nil && (firstErr == nil || errLineIdx < firstErrLineIdx) {
+				firstErr, firstErrLineIdx = err, errLineIdx
 			}
+		})
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+	chunk := make([]string, 0, chunkLines)
+	chunkFirstLineIdx := 0
+	lineIdx := 0
+	for ; scanner.Scan(); lineIdx++ {
+		chunk = append(chunk, scanner.Text())
+		if len(chunk) == chunkLines {
+			submit(chunk, chunkFirstLineIdx)
+			chunk = make([]string, 0, chunkLines)
+			chunkFirstLineIdx = lineIdx + 1
 		}
+	}
+	if len(chunk) > 0 {
+		submit(chunk, chunkFirstLineIdx)
+	}
+	pool.Wait()
 
-		if leftDigit == "" || rightDigit == "" {
-			fmt.Printf("Line %d. No numbers found: %s\n", lineIdx, line)
-			os.Exit(1)
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return 0, fmt.Errorf("Line %d is longer than %d bytes, the limit of the streaming mode",
+				lineIdx, maxLineLength)
 		}
+		return 0, errors.Join(errors.New("Failed to read the input"), err)
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return sum, nil
+}
+
+// scannedLines makes lines of a file split at line breaks the same as bufio.Scanner of the
+// streaming mode returns: without a trailing \r and without the empty line after the last break
+func scannedLines(lines []string) []string {
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
 
-		lineValueStr := leftDigit + rightDigit
-		lineValue, err := strconv.Atoi(lineValueStr)
+	scanned := make([]string, 0, len(lines))
+	for _, line := range lines {
+		scanned = append(scanned, strings.TrimSuffix(line, "\r"))
+	}
+	return scanned
+}
+
+// sumLines sums calibration values of the lines. If a line is invalid, it returns its index.
+func (d *Decoder) sumLines(lines []string, firstLineIdx int) (uint, int, error) {
+	var sum uint
+	for i, line := range lines {
+		first, last, err := d.parseDigits(line)
 		if err != nil {
-			fmt.Printf("Line %d. Unable to parse line value %s\n", lineIdx, lineValueStr)
-			os.Exit(1)
+			lineIdx := firstLineIdx + i
+			return 0, lineIdx, fmt.Errorf("Line %d. %s: %s", lineIdx, err.Error(), line)
+		}
+		sum += uint(first.digit)*10 + uint(last.digit)
+	}
+	return sum, 0, nil
+}
+
+// loadNumbers returns spellings of the built-in vocabulary followed by ones from the file and
+// from the comma separated list
+func loadNumbers(vocabulary, wordsFile, words string) ([]Number, error) {
+	builtInNumbers, found := vocabularies[vocabulary]
+	if !found {
+		return nil, fmt.Errorf("Unknown vocabulary %s", vocabulary)
+	}
+	numbers := slices.Clone(builtInNumbers)
+
+	if wordsFile != "" {
+		data, err := os.ReadFile(wordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading words file <%s>: %w", wordsFile, err)
 		}
 
-		fmt.Printf("%d. %s. Detected %s, %s => %d\n", lineIdx, line, leftDigit, rightDigit, lineValue)
+		for lineIdx, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
 
-		sum += uint(lineValue)
+			number, err := parseNumber(line)
+			if err != nil {
+				return nil, fmt.Errorf("Words file <%s>, line %d: %w", wordsFile, lineIdx+1, err)
+			}
+			numbers = append(numbers, number)
+		}
 	}
 
-	fmt.Println("Total sum:", sum)
+	if words != "" {
+		for _, pair := range strings.Split(words, ",") {
+			number, err := parseNumber(strings.TrimSpace(pair))
+			if err != nil {
+				return nil, err
+			}
+			numbers = append(numbers, number)
+		}
+	}
+
+	return numbers, nil
 }
 
-func parseCharDigit(line string, idx int) *Number {
-	lineLen := len(line)
-	for _, num := range numbers {
-		if idx+len(num.chars) > lineLen {
-			continue
+// parseNumber parses a word=digit pair
+func parseNumber(pair string) (Number, error) {
+	chars, digitStr, found := strings.Cut(pair, "=")
+	if !found || len(chars) == 0 {
+		return Number{}, fmt.Errorf("Spelling <%s> isn't a word=digit pair", pair)
+	}
+
+	digit, err := strconv.ParseUint(digitStr, 10, 8)
+	if err != nil || digit > 9 {
+		return Number{}, fmt.Errorf("Spelling <%s> has no digit from 0 to 9", pair)
+	}
+
+	return Number{uint8(digit), chars}, nil
+}
+
+// newDecoder returns a decoder of digits and the spellings. It fails if a spelling means
+// different digits.
+func newDecoder(numbers []Number) (*Decoder, error) {
+	var patterns []string
+	var digits []uint8
+	digitBySpelling := make(map[string]uint8)
+
+	add := func(chars string, digit uint8) error {
+		if prevDigit, found := digitBySpelling[chars]; found {
+			if prevDigit != digit {
+				return fmt.Errorf("Spelling %s means both %d and %d", chars, prevDigit, digit)
+			}
+			return nil
 		}
 
-		if stringIsAtIdx(line, num.chars, idx) {
-			return &num
+		digitBySpelling[chars] = digit
+		patterns = append(patterns, chars)
+		digits = append(digits, digit)
+		return nil
+	}
+
+	for digit := uint8(0); digit <= 9; digit++ {
+		add(strconv.Itoa(int(digit)), digit)
+	}
+	for _, num := range numbers {
+		if err := add(num.chars, num.digit); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return &Decoder{strmatch.NewMatcher(patterns...), digits}, nil
 }
 
-func stringIsAtIdx(input, sample string, idx int) bool {
-	if idx < 0 {
-		fmt.Printf("stringIsAtIdx: input=%s, sample=%s, idx=%d. idx < 0\n", input, sample, idx)
-		os.Exit(1)
+// parseDigits returns the first and the last digit of the line. Spellings may overlap like in
+// "twone", which has both 2 and 1, but a spelling within a longer one like I in VIII isn't a digit
+// on its own.
+func (d *Decoder) parseDigits(line string) (DigitMatch, DigitMatch, error) {
+	matches := outermostMatches(d.matcher.FindAll(line))
+	if len(matches) == 0 {
+		return DigitMatch{}, DigitMatch{}, errors.New("No numbers found")
 	}
 
-	inputLen := len(input)
-	sampleLen := len(sample)
+	return d.toDigitMatch(line, matches[0]), d.toDigitMatch(line, matches[len(matches)-1]), nil
+}
 
-	// idx is out of input length
-	if idx >= inputLen {
-		return false
+func (d *Decoder) toDigitMatch(line string, match strmatch.Match) DigitMatch {
+	return DigitMatch{
+		digit:  d.digitByPattern[match.Pattern],
+		chars:  line[match.Start:match.End],
+		offset: match.Start,
 	}
+}
 
-	// sample is too long to match
-	if idx+sampleLen > inputLen {
-		return false
-	}
+// outermostMatches drops matches lying within other matches and orders the rest by their starts
+func outermostMatches(matches []strmatch.Match) []strmatch.Match {
+	sorted := slices.Clone(matches)
+	slices.SortFunc(sorted, func(m1, m2 strmatch.Match) int {
+		if m1.Start != m2.Start {
+			return cmp.Compare(m1.Start, m2.Start)
+		}
+		return cmp.Compare(m2.End, m1.End)
+	})
 
-	for i := 0; i < sampleLen; i++ {
-		if input[idx+i] != sample[i] {
-			return false
+	// a match starting later is within a previous one unless it ends later than all of them
+	var outermost []strmatch.Match
+	maxEnd := -1
+	for _, match := range sorted {
+		if match.End > maxEnd {
+			outermost = append(outermost, match)
+			maxEnd = match.End
 		}
 	}
-
-	return true
+	return outermost
 }
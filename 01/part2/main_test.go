@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func newTestDecoder(tb testing.TB, vocabulary, words string) *Decoder {
+	numbers, err := loadNumbers(vocabulary, "", words)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	decoder, err := newDecoder(numbers)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return decoder
+}
+
+func FuzzParseDigits(f *testing.F) {
+	decoder := newTestDecoder(f, "english", "")
+
+	testutil.FuzzEachLine(f, []string{"sample.txt", "debug*.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			decoder.parseDigits(line)
+		})
+}
+
+func TestParseDigits(t *testing.T) {
+	inputs := []struct {
+		vocabulary, words string
+		line              string
+		first, last       DigitMatch
+	}{
+		{"english", "", "twone", DigitMatch{2, "two", 0}, DigitMatch{1, "one", 2}},
+		{"english", "", "eightwo", DigitMatch{8, "eight", 0}, DigitMatch{2, "two", 4}},
+		{"english", "", "7pqrstsixteen", DigitMatch{7, "7", 0}, DigitMatch{6, "six", 6}},
+		{"english", "", "zoneight234", DigitMatch{1, "one", 1}, DigitMatch{4, "4", 10}},
+		{"english", "", "0abc", DigitMatch{0, "0", 0}, DigitMatch{0, "0", 0}},
+		{"german", "", "xfünfzweins", DigitMatch{5, "fünf", 1}, DigitMatch{1, "eins", 8}},
+		{"roman", "", "xVIIIyIVz", DigitMatch{8, "VIII", 1}, DigitMatch{4, "IV", 6}},
+		{"roman", "", "IIIX", DigitMatch{3, "III", 0}, DigitMatch{9, "IX", 2}},
+		{"none", "", "one2three", DigitMatch{2, "2", 3}, DigitMatch{2, "2", 3}},
+		{"none", "uno=1,dos=2", "unodos", DigitMatch{1, "uno", 0}, DigitMatch{2, "dos", 3}},
+	}
+
+	for _, input := range inputs {
+		decoder := newTestDecoder(t, input.vocabulary, input.words)
+		first, last, err := decoder.parseDigits(input.line)
+		if err != nil {
+			t.Errorf("%s: %s", input.line, err.Error())
+			continue
+		}
+		if first != input.first || last != input.last {
+			t.Errorf("%s: wanted %v %v, got %v %v", input.line, input.first, input.last, first, last)
+		}
+	}
+}
+
+func TestLoadNumbersFromFile(t *testing.T) {
+	wordsFile := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordsFile, []byte("uno=1\n\n dos=2 \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	numbers, err := loadNumbers("none", wordsFile, "tres=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Number{{1, "uno"}, {2, "dos"}, {3, "tres"}}
+	if len(numbers) != len(expected) || numbers[0] != expected[0] || numbers[1] != expected[1] ||
+		numbers[2] != expected[2] {
+		t.Errorf("Numbers %v. Expected %v", numbers, expected)
+	}
+}
+
+func TestInvalidSpellings(t *testing.T) {
+	for _, words := range []string{"uno", "=1", "uno=10", "uno=x"} {
+		if _, err := loadNumbers("english", "", words); err == nil {
+			t.Errorf("Spelling %s is accepted", words)
+		}
+	}
+
+	if _, err := loadNumbers("klingon", "", ""); err == nil {
+		t.Error("Unknown vocabulary is accepted")
+	}
+
+	numbers, err := loadNumbers("english", "", "one=1,one=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newDecoder(numbers); err == nil {
+		t.Error("Spelling meaning different digits is accepted")
+	}
+}
+
+func TestSumStreamMatchesSequentialSum(t *testing.T) {
+	decoder := newTestDecoder(t, "english", "")
+	spellings := []string{"x", "y", "1", "7", "one", "two", "eight", "nine", "twone", "eightwo"}
+
+	rnd := rand.New(rand.NewSource(1))
+	lines := make([]string, 1000)
+	for i := range lines {
+		var sb strings.Builder
+		sb.WriteString("5")
+		for j := rnd.Intn(6); j > 0; j-- {
+			sb.WriteString(spellings[rnd.Intn(len(spellings))])
+		}
+		lines[i] = sb.String()
+	}
+
+	expected, _, err := decoder.sumLines(lines, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join(lines, "\n")
+	for _, chunkLines := range []int{1, 7, 1000, 5000} {
+		for _, workers := range []int{1, 4} {
+			t.Run(fmt.Sprintf("%d lines by %d workers", chunkLines, workers), func(t *testing.T) {
+				sum, err := decoder.sumStream(strings.NewReader(input), workers, chunkLines)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sum != expected {
+					t.Errorf("Sum %d. Expected %d", sum, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestSequentialAndStreamingLinesMatch(t *testing.T) {
+	decoder := newTestDecoder(t, "english", "")
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"LF", "1abc2\npqr3stu8vwx\ntreb7uchet"},
+		{"trailing LF", "1abc2\npqr3stu8vwx\ntreb7uchet\n"},
+		{"CRLF", "1abc2\r\npqr3stu8vwx\r\ntreb7uchet"},
+		{"trailing CRLF", "1abc2\r\npqr3stu8vwx\r\ntreb7uchet\r\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines := scannedLines(strings.Split(test.input, "\n"))
+			if len(lines) != 3 || lines[2] != "treb7uchet" {
+				t.Fatalf("Unexpected lines %q", lines)
+			}
+
+			sequentialSum, _, err := decoder.sumLines(lines, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			streamingSum, err := decoder.sumStream(strings.NewReader(test.input), 2, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sequentialSum != 127 || streamingSum != 127 {
+				t.Errorf("Sequential sum %d, streaming sum %d. Expected 127", sequentialSum,
+					streamingSum)
+			}
+		})
+	}
+}
+
+func TestSumStreamReportsFirstInvalidLine(t *testing.T) {
+	decoder := newTestDecoder(t, "english", "")
+	lines := []string{"1", "2", "3", "x", "5", "6", "y", "8"}
+
+	_, err := decoder.sumStream(strings.NewReader(strings.Join(lines, "\n")), 4, 2)
+	if err == nil || err.Error() != "Line 3. No numbers found: x" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSumStreamReportsTooLongLine(t *testing.T) {
+	decoder := newTestDecoder(t, "english", "")
+	input := "1\n2\n" + strings.Repeat("3", maxLineLength+1) + "\n4"
+
+	_, err := decoder.sumStream(strings.NewReader(input), 2, 1)
+	expected := fmt.Sprintf("Line 2 is longer than %d bytes, the limit of the streaming mode",
+		maxLineLength)
+	if err == nil || err.Error() != expected {
+		t.Errorf("Unexpected error: %v. Expected %q", err, expected)
+	}
+}
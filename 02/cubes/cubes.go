@@ -0,0 +1,191 @@
+// Package cubes models games of day 2, where handfuls of coloured cubes are drawn from a bag, so
+// both parts and ad hoc queries share the parser and the bag arithmetic. Colours are arbitrary
+// words.
+package cubes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// Cubes are numbers of cubes by colour. Colours absent from the map have no cubes.
+type Cubes map[string]uint
+
+// ParseCubes parses comma separated colour=count pairs like red=12,green=13,blue=14
+func ParseCubes(s string) (Cubes, error) {
+	cubes := make(Cubes)
+	if len(strings.TrimSpace(s)) == 0 {
+		return cubes, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		colour, countStr, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || len(colour) == 0 {
+			return nil, fmt.Errorf("Cubes <%s> aren't a colour=count pair", pair)
+		}
+		if _, found := cubes[colour]; found {
+			return nil, fmt.Errorf("Duplicate cube colour %s", colour)
+		}
+
+		count, err := util.ParseUint(countStr)
+		if err != nil {
+			return nil, fmt.Errorf("Cubes <%s>: %w", pair, err)
+		}
+		cubes[colour] = count
+	}
+	return cubes, nil
+}
+
+// String formats cubes the way ParseCubes parses them with colours sorted
+func (c Cubes) String() string {
+	pairs := make([]string, 0, len(c))
+	for _, colour := range util.MapKeysToSortedSlice(c) {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", colour, c[colour]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Contains tells if there are at least as many cubes of every colour as in other
+func (c Cubes) Contains(other Cubes) bool {
+	for colour, count := range other {
+		if count > c[colour] {
+			return false
+		}
+	}
+	return true
+}
+
+// Power returns the product of cube numbers of all colours present
+func (c Cubes) Power() uint {
+	power := uint(1)
+	for _, count := range c {
+		power *= count
+	}
+	return power
+}
+
+// Union returns the smallest set of cubes containing both sets
+func (c Cubes) Union(other Cubes) Cubes {
+	union := make(Cubes, max(len(c), len(other)))
+	for colour, count := range c {
+		union[colour] = count
+	}
+	for colour, count := range other {
+		union[colour] = max(union[colour], count)
+	}
+	return union
+}
+
+type Game struct {
+	ID     uint
+	Rounds []Cubes
+}
+
+// PossibleWith tells if every round of the game could be drawn from the bag
+func (g Game) PossibleWith(bag Cubes) bool {
+	for _, round := range g.Rounds {
+		if !bag.Contains(round) {
+			return false
+		}
+	}
+	return true
+}
+
+// MinimalBag returns the smallest bag the game is possible with
+func (g Game) MinimalBag() Cubes {
+	bag := make(Cubes)
+	for _, round := range g.Rounds {
+		bag = bag.Union(round)
+	}
+	return bag
+}
+
+// PossibleGames returns games possible with the bag
+func PossibleGames(games []Game, bag Cubes) []Game {
+	var possible []Game
+	for _, game := range games {
+		if game.PossibleWith(bag) {
+			possible = append(possible, game)
+		}
+	}
+	return possible
+}
+
+// MinimalBagForAll returns the smallest bag all the games are possible with
+func MinimalBagForAll(games []Game) Cubes {
+	bag := make(Cubes)
+	for _, game := range games {
+		bag = bag.Union(game.MinimalBag())
+	}
+	return bag
+}
+
+// ParseGames parses a game per line
+func ParseGames(lines []string) ([]Game, error) {
+	games := make([]Game, 0, len(lines))
+	for lineIdx, line := range lines {
+		game, err := ParseGame(line)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// ParseGame parses a line like "Game 1: 3 blue, 4 red; 1 red, 2 green"
+func ParseGame(line string) (Game, error) {
+	lineParts := strings.Split(line, ":")
+	if len(lineParts) != 2 {
+		return Game{}, fmt.Errorf("Unexpected number of colon-delimited parts: %d", len(lineParts))
+	}
+
+	gameParts := strings.Split(lineParts[0], " ")
+	if len(gameParts) != 2 {
+		return Game{}, fmt.Errorf("Unexpected game title <%s>", lineParts[0])
+	}
+	gameIdStr := gameParts[1]
+	gameId, err := strconv.ParseUint(gameIdStr, 10, 64)
+	if err != nil {
+		return Game{}, fmt.Errorf("Cannot parse game ID from <%s>: %s", gameIdStr, err.Error())
+	}
+
+	var rounds []Cubes
+	roundStrs := strings.Split(strings.TrimSpace(lineParts[1]), ";")
+	for roundIdx, roundStr := range roundStrs {
+		round := make(Cubes)
+
+		cubeSets := strings.Split(roundStr, ", ")
+		for cubeSetIdx, cubeSet := range cubeSets {
+			setParts := strings.Split(strings.TrimSpace(cubeSet), " ")
+			if len(setParts) != 2 {
+				return Game{}, fmt.Errorf("Round %d, cubeset %d: Unexpected cubeset <%s>", roundIdx+1,
+					cubeSetIdx+1, cubeSet)
+			}
+
+			cubeCountStr := setParts[0]
+			cubeCount, err := strconv.ParseUint(cubeCountStr, 10, 64)
+			if err != nil {
+				return Game{}, fmt.Errorf("Round %d, cubeset %d: Cannot parse cube count from <%s>: %s",
+					roundIdx+1, cubeSetIdx+1, cubeCountStr, err.Error())
+			}
+
+			cubeColour := setParts[1]
+			if _, found := round[cubeColour]; found {
+				return Game{}, fmt.Errorf("Round %d, cubeset %d: Duplicate cube colour %s", roundIdx+1,
+					cubeSetIdx+1, cubeColour)
+			}
+			round[cubeColour] = uint(cubeCount)
+		}
+
+		rounds = append(rounds, round)
+	}
+
+	return Game{
+		ID:     uint(gameId),
+		Rounds: rounds,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package cubes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseGame(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			ParseGame(line)
+		})
+}
+
+func TestParseCubes(t *testing.T) {
+	bag, err := ParseCubes("red=12, green=13,blue=14,ultraviolet=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := bag.String(); s != "blue=14,green=13,red=12,ultraviolet=0" {
+		t.Errorf("Unexpected bag %s", s)
+	}
+
+	for _, s := range []string{"red", "=1", "red=-1", "red=1,red=2"} {
+		if _, err := ParseCubes(s); err == nil {
+			t.Errorf("Cubes %s are accepted", s)
+		}
+	}
+}
+
+func TestParseGamesReportsPosition(t *testing.T) {
+	_, err := ParseGames([]string{"Game 1: 3 blue", "Game 2: 3 blue; 1 red, 3 bleu x"})
+	if err == nil || !strings.HasPrefix(err.Error(), "Line 2: Round 2, cubeset 2: ") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	games, err := ParseGames(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag, err := ParseCubes("red=12,green=13,blue=14")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var possibleIDs []uint
+	for _, game := range PossibleGames(games, bag) {
+		possibleIDs = append(possibleIDs, game.ID)
+	}
+	if len(possibleIDs) != 3 || possibleIDs[0] != 1 || possibleIDs[1] != 2 || possibleIDs[2] != 5 {
+		t.Errorf("Unexpected possible games %v", possibleIDs)
+	}
+
+	if minimal := games[0].MinimalBag(); minimal.String() != "blue=6,green=2,red=4" ||
+		minimal.Power() != 48 {
+		t.Errorf("Unexpected minimal bag of game 1: %s", minimal)
+	}
+
+	minimal := MinimalBagForAll([]Game{games[0], games[2]})
+	if minimal.String() != "blue=6,green=13,red=20" {
+		t.Errorf("Unexpected minimal bag of games 1 and 3: %s", minimal)
+	}
+	for _, game := range []Game{games[0], games[2]} {
+		if !game.PossibleWith(minimal) {
+			t.Errorf("Game %d isn't possible with its minimal bag", game.ID)
+		}
+	}
+}
+
+func TestUnknownColourMakesGameImpossible(t *testing.T) {
+	game, err := ParseGame("Game 7: 1 red, 1 teal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.PossibleWith(Cubes{"red": 5}) {
+		t.Error("Game with cubes absent from the bag is possible")
+	}
+}
@@ -1,68 +1,75 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/02/cubes"
+	"github.com/efulmo/advent-of-code-2023/util"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Usage: %s <input-file-path>\n", os.Args[0])
-		return
-	}
+	defer util.ReportAllocs()
 
-	inputeFilePath := os.Args[1]
-	fmt.Printf("Reading input from file <%s>\n", inputeFilePath)
-
-	data, err := os.ReadFile(inputeFilePath)
-	if err != nil {
-		fmt.Printf("Error reading file <%s>: %s\n", inputeFilePath, err.Error())
-		os.Exit(1)
+	gameIDsStr := flag.String("games", "", "comma separated IDs of games to find the smallest bag "+
+		"for instead of summing powers of the smallest bags of all games")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
-	fmt.Printf("Read %d bytes\n", len(data))
 
-	lines := strings.Split(string(data), "\n")
-	fmt.Printf("Read %d lines\n", len(lines))
+	lines, err := util.ReadFileLines(flag.Arg(0))
+	util.PanicOnError(err)
 
-	var gamePowerSum uint
-	for lineIdx, line := range lines {
-		lineParts := strings.Split(line, ":")
+	games, err := cubes.ParseGames(lines)
+	util.PanicOnError(err)
 
-		optimalCubeCount := map[string]uint{}
+	if len(*gameIDsStr) > 0 {
+		selectedGames, err := selectGames(games, *gameIDsStr)
+		util.PanicOnError(err)
 
-		rounds := strings.Split(strings.TrimSpace(lineParts[1]), ";")
-		for roundIdx, round := range rounds {
-			cubeSets := strings.Split(round, ", ")
-			for cubeSetIdx, cubeSet := range cubeSets {
-				setParts := strings.Split(strings.TrimSpace(cubeSet), " ")
-				cubeCountStr := setParts[0]
-				cubeCount, err := strconv.Atoi(cubeCountStr)
-				if err != nil {
-					fmt.Printf("Line %d, round %d, cubeset %d: Cannot parse cube count from <%s>: %s\n",
-						lineIdx, roundIdx, cubeSetIdx, cubeCountStr, err.Error())
-					os.Exit(1)
-				}
+		bag := cubes.MinimalBagForAll(selectedGames)
+		fmt.Printf("Smallest bag for games %s: %s. Power: %d\n", *gameIDsStr, bag, bag.Power())
+		return
+	}
 
-				cubeColour := setParts[1]
+	var gamePowerSum uint
+	for lineIdx, game := range games {
+		bag := game.MinimalBag()
+		fmt.Printf("%d. %s\nOptimal count: %v. Game power: %d\n",
+			lineIdx, lines[lineIdx], bag, bag.Power())
+		gamePowerSum += bag.Power()
+	}
 
-				lowestCubeCount, ok := optimalCubeCount[cubeColour]
-				if !ok || lowestCubeCount < uint(cubeCount) {
-					optimalCubeCount[cubeColour] = uint(cubeCount)
-				}
-			}
-		}
+	fmt.Println("Sum of possible games:", gamePowerSum)
+}
 
-		gamePower := 1
-		for _, cubeCount := range optimalCubeCount {
-			gamePower *= int(cubeCount)
-		}
+// selectGames returns games with the comma separated IDs
+func selectGames(games []cubes.Game, gameIDsStr string) ([]cubes.Game, error) {
+	gameIDs, err := util.ParseUints(strings.Split(gameIDsStr, ","))
+	if err != nil {
+		return nil, err
+	}
 
-		fmt.Printf("%d. %s\nOptimal count: %v. Game power: %d\n",
-			lineIdx, line, optimalCubeCount, gamePower)
-		gamePowerSum += uint(gamePower)
+	gameByID := make(map[uint]cubes.Game, len(games))
+	for _, game := range games {
+		gameByID[game.ID] = game
 	}
 
-	fmt.Println("Sum of possible games:", gamePowerSum)
+	selectedGames := make([]cubes.Game, 0, len(gameIDs))
+	for _, gameID := range gameIDs {
+		game, found := gameByID[gameID]
+		if !found {
+			return nil, fmt.Errorf("Game %d isn't found", gameID)
+		}
+		selectedGames = append(selectedGames, game)
+	}
+	return selectedGames, nil
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/02/cubes"
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func TestSelectGames(t *testing.T) {
+	games, err := cubes.ParseGames(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected, err := selectGames(games, "4,2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].ID != 4 || selected[1].ID != 2 {
+		t.Errorf("Unexpected games %v", selected)
+	}
+
+	if _, err := selectGames(games, "2,6"); err == nil {
+		t.Error("Unknown game is selected")
+	}
+}
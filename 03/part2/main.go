@@ -1,183 +1,88 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"unicode"
+	"os"
+	"strings"
 
+	"github.com/efulmo/advent-of-code-2023/03/schematic"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
-type PartID struct {
-	lineIdx  uint
-	startIdx uint // including
-	endIdx   uint // excluding
-	number   uint
-}
-
-type Point struct {
-	rowIdx int
-	colIdx int
+var combiners = map[string]schematic.Combiner{
+	"product": schematic.Product,
+	"sum":     schematic.Sum,
 }
 
 func main() {
-	lines, err := util.ReadInputFile()
-	util.PanicOnError(err)
-
-	gearConnections := make(map[Point][]PartID)
-	for lineIdx, line := range lines {
-		partIDs := parsePartIDs(line, uint(lineIdx))
-
-		for _, partID := range partIDs {
-			gearPoints := getGearPoints(lines, partID)
-
-			if len(gearPoints) > 0 {
-				fmt.Printf("%d. Part %d has %d gears around\n", lineIdx+1, partID.number, len(gearPoints))
-			}
-
-			for _, point := range gearPoints {
-				connections := gearConnections[point]
-				connections = append(connections, partID)
-
-				gearConnections[point] = connections
-			}
-		}
-	}
-
-	fmt.Printf("%d gears detected around part IDs\n", len(gearConnections))
-
-	var gearRatioSum uint
-	for gearPoint, partIDs := range gearConnections {
-		partsConnected := len(partIDs)
-		if partsConnected == 2 {
-			fmt.Printf("Gear %d:%d has %d and %d parts connected\n",
-				gearPoint.rowIdx, gearPoint.colIdx,
-				partIDs[0].number, partIDs[1].number)
-
-			gearRatio := partIDs[0].number * partIDs[1].number
-			gearRatioSum += gearRatio
-		} else {
-			fmt.Printf("Gear %d:%d has %d connections\n", gearPoint.rowIdx, gearPoint.colIdx, partsConnected)
-		}
+	defer util.ReportAllocs()
+
+	symbols := flag.String("symbols", "*", "chars of gear symbols")
+	neighbours := flag.Int("neighbours", 2, "number of part numbers a gear is adjacent to")
+	combinerName := flag.String("combine", "product", "how part numbers of a gear make its ratio: "+
+		strings.Join(util.MapKeysToSortedSlice(combiners), ", "))
+	annotate := flag.Bool("annotate", false, "print the schematic with part numbers of gears in "+
+		"green and the rest in red")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
-
-	fmt.Println("Gear ratio sum:", gearRatioSum)
-}
-
-const NilIdx = -1
-
-func parsePartIDs(line string, lineIdx uint) []PartID {
-	var partIDs []PartID
-
-	startRegionIdx := NilIdx
-	for charIdx, r := range line {
-		if unicode.IsDigit(r) {
-			if startRegionIdx == NilIdx {
-				startRegionIdx = charIdx
-			}
-		} else {
-			if startRegionIdx != NilIdx {
-				partIDs = append(partIDs, PartID{
-					lineIdx:  lineIdx,
-					startIdx: uint(startRegionIdx),
-					endIdx:   uint(charIdx),
-					number:   parsePartIDNumber(line, uint(startRegionIdx), uint(charIdx)),
-				})
-
-				// fmt.Printf("Parsed region %d:%d\n", startRegionIdx, charIdx)
-
-				startRegionIdx = NilIdx
-			}
-		}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
-
-	if startRegionIdx != NilIdx {
-		partIDs = append(partIDs, PartID{
-			lineIdx:  lineIdx,
-			startIdx: uint(startRegionIdx),
-			endIdx:   uint(len(line)),
-			number:   parsePartIDNumber(line, uint(startRegionIdx), uint(len(line))),
-		})
+	combiner, found := combiners[*combinerName]
+	if !found {
+		fmt.Fprintf(os.Stderr, "Unknown combiner %s\n", *combinerName)
+		os.Exit(2)
 	}
 
-	return partIDs
-}
-
-func parsePartIDNumber(line string, startIdx, endIdx uint) uint {
-	partIDStr := line[startIdx:endIdx]
+	lines, err := util.ReadFileLines(flag.Arg(0))
+	util.PanicOnError(err)
 
-	// potential int truncation
-	if len(partIDStr) > 6 {
-		panic(fmt.Errorf("Potentionally too big part ID for int: %s", partIDStr))
-	}
+	s, err := schematic.Parse(lines)
+	util.PanicOnError(err)
 
-	return util.ParseUintOrPanic(partIDStr)
-}
+	class := schematic.OneOf(*symbols)
+	var candidateCount int
+	for _, gear := range s.Around(class) {
+		if len(gear.Numbers) == 0 {
+			continue
+		}
 
-func partIDsToNumbers(partIDs []PartID) []uint {
-	numbers := make([]uint, 0, len(partIDs))
-	for _, partID := range partIDs {
-		numbers = append(numbers, partID.number)
+		candidateCount++
+		if len(gear.Numbers) != *neighbours {
+			fmt.Printf("Gear %d:%d has %d connections\n", gear.Row+1, gear.Col+1,
+				len(gear.Numbers))
+		}
 	}
+	fmt.Printf("%d gears detected around part IDs\n", candidateCount)
 
-	return numbers
-}
-
-const GearRune = '*'
-
-func getGearPoints(lines []string, partID PartID) []Point {
-	pointsAround := make([]Point, 0, 12)
-	lineIdx := int(partID.lineIdx)
-	startIdx := int(partID.startIdx)
-	endIdx := int(partID.endIdx)
-
-	// left & right
-	pointsAround = append(pointsAround, Point{lineIdx, startIdx - 1})
-	pointsAround = append(pointsAround, Point{lineIdx, endIdx})
-
-	// top & bottom
-	partIDLen := endIdx - startIdx
-	for i := 0; i < partIDLen; i++ {
-		pointsAround = append(pointsAround, Point{lineIdx - 1, startIdx + i})
-		pointsAround = append(pointsAround, Point{lineIdx + 1, startIdx + i})
+	var gearRatioSum uint
+	var counted []schematic.Number
+	for _, gear := range s.Gears(class, *neighbours) {
+		ratio := gear.Combine(combiner)
+		fmt.Printf("Gear %d:%d has %v parts connected. Ratio: %d\n", gear.Row+1, gear.Col+1,
+			toValues(gear.Numbers), ratio)
+
+		gearRatioSum += ratio
+		counted = append(counted, gear.Numbers...)
 	}
 
-	// top diagonals
-	pointsAround = append(pointsAround, Point{lineIdx - 1, startIdx - 1})
-	pointsAround = append(pointsAround, Point{lineIdx - 1, endIdx})
-
-	// bottom diagonals
-	pointsAround = append(pointsAround, Point{lineIdx + 1, startIdx - 1})
-	pointsAround = append(pointsAround, Point{lineIdx + 1, endIdx})
-
-	var gearPoints []Point
-	for _, point := range pointsAround {
-		r := getRuneAtOrDot(lines, point.rowIdx, point.colIdx)
-		if r == GearRune {
-			gearPoints = append(gearPoints, point)
-		}
+	if *annotate {
+		fmt.Println(s.Annotate(counted, schematic.ANSIMarks))
 	}
 
-	return gearPoints
+	fmt.Println("Gear ratio sum:", gearRatioSum)
 }
 
-const Dot = '.'
-
-func getRuneAtOrDot(lines []string, rowIdx, colIdx int) rune {
-	// negative indexes
-	if rowIdx < 0 || colIdx < 0 {
-		return Dot
-	}
-
-	// too big rowIdx
-	if rowIdx >= len(lines) {
-		return Dot
-	}
-
-	// too big colIdx
-	line := lines[rowIdx]
-	if colIdx >= len(line) {
-		return Dot
+func toValues(numbers []schematic.Number) []uint {
+	values := make([]uint, 0, len(numbers))
+	for _, number := range numbers {
+		values = append(values, number.Value)
 	}
 
-	return rune(line[colIdx])
+	return values
 }
@@ -0,0 +1,281 @@
+// Package schematic indexes numbers and symbols of day 3 engine schematics, so questions about
+// numbers adjacent to symbols of any kind are answered without rescanning the lines.
+package schematic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const Dot = '.'
+
+// Number is a run of digits in a row. Start is inclusive and End is exclusive.
+type Number struct {
+	Row        int
+	Start, End int
+	Value      uint
+}
+
+type Symbol struct {
+	Row, Col int
+	Char     byte
+}
+
+// Class tells if a symbol char belongs to it
+type Class func(char byte) bool
+
+// AnySymbol is the class of all chars but dots and digits
+func AnySymbol(char byte) bool {
+	return char != Dot && !isDigit(char)
+}
+
+// OneOf returns the class of the chars
+func OneOf(chars string) Class {
+	return func(char byte) bool {
+		return strings.IndexByte(chars, char) != -1
+	}
+}
+
+// Gear is a symbol with numbers adjacent to it
+type Gear struct {
+	Symbol
+	Numbers []Number
+}
+
+// Combiner reduces values of numbers adjacent to a gear to a single value like a gear ratio
+type Combiner func(values []uint) uint
+
+func Product(values []uint) uint {
+	product := uint(1)
+	for _, value := range values {
+		product *= value
+	}
+	return product
+}
+
+func Sum(values []uint) uint {
+	var sum uint
+	for _, value := range values {
+		sum += value
+	}
+	return sum
+}
+
+func (g Gear) Combine(combiner Combiner) uint {
+	values := make([]uint, 0, len(g.Numbers))
+	for _, number := range g.Numbers {
+		values = append(values, number.Value)
+	}
+	return combiner(values)
+}
+
+type Schematic struct {
+	lines   []string
+	numbers []Number
+	symbols []Symbol
+	// adjacency is kept both ways by indexes of numbers and symbols
+	numberIdxsBySymbol [][]int
+	symbolIdxsByNumber [][]int
+}
+
+type point struct {
+	row, col int
+}
+
+// Parse indexes the schematic. Numbers and symbols are ordered row by row.
+func Parse(lines []string) (*Schematic, error) {
+	s := &Schematic{lines: lines}
+
+	symbolIdxByPoint := make(map[point]int)
+	for rowIdx, line := range lines {
+		numbers, err := parseNumbers(line, rowIdx)
+		if err != nil {
+			return nil, err
+		}
+		s.numbers = append(s.numbers, numbers...)
+
+		for colIdx := 0; colIdx < len(line); colIdx++ {
+			if AnySymbol(line[colIdx]) {
+				symbolIdxByPoint[point{rowIdx, colIdx}] = len(s.symbols)
+				s.symbols = append(s.symbols, Symbol{rowIdx, colIdx, line[colIdx]})
+			}
+		}
+	}
+
+	s.numberIdxsBySymbol = make([][]int, len(s.symbols))
+	s.symbolIdxsByNumber = make([][]int, len(s.numbers))
+	for numberIdx, number := range s.numbers {
+		for _, p := range pointsAround(number) {
+			if symbolIdx, found := symbolIdxByPoint[p]; found {
+				s.symbolIdxsByNumber[numberIdx] = append(s.symbolIdxsByNumber[numberIdx], symbolIdx)
+				s.numberIdxsBySymbol[symbolIdx] = append(s.numberIdxsBySymbol[symbolIdx], numberIdx)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Schematic) Numbers() []Number {
+	return s.numbers
+}
+
+func (s *Schematic) Symbols() []Symbol {
+	return s.symbols
+}
+
+// AdjacentNumbers returns numbers adjacent to at least one symbol of the class
+func (s *Schematic) AdjacentNumbers(class Class) []Number {
+	var adjacent []Number
+	for numberIdx, number := range s.numbers {
+		for _, symbolIdx := range s.symbolIdxsByNumber[numberIdx] {
+			if class(s.symbols[symbolIdx].Char) {
+				adjacent = append(adjacent, number)
+				break
+			}
+		}
+	}
+	return adjacent
+}
+
+// Around returns all symbols of the class with numbers adjacent to them, if any
+func (s *Schematic) Around(class Class) []Gear {
+	var gears []Gear
+	for symbolIdx, symbol := range s.symbols {
+		if !class(symbol.Char) {
+			continue
+		}
+
+		gear := Gear{Symbol: symbol}
+		for _, numberIdx := range s.numberIdxsBySymbol[symbolIdx] {
+			gear.Numbers = append(gear.Numbers, s.numbers[numberIdx])
+		}
+		gears = append(gears, gear)
+	}
+	return gears
+}
+
+// Gears returns symbols of the class with exactly neighbours numbers adjacent to them
+func (s *Schematic) Gears(class Class, neighbours int) []Gear {
+	var gears []Gear
+	for _, gear := range s.Around(class) {
+		if len(gear.Numbers) == neighbours {
+			gears = append(gears, gear)
+		}
+	}
+	return gears
+}
+
+// Marks wrap numbers in an annotated schematic
+type Marks struct {
+	CountedStart, CountedEnd string
+	IgnoredStart, IgnoredEnd string
+}
+
+// ANSIMarks paint counted numbers green and ignored ones red keeping the layout of the schematic
+var ANSIMarks = Marks{"\x1b[32m", "\x1b[0m", "\x1b[31m", "\x1b[0m"}
+
+// BracketMarks wrap counted numbers in [] and ignored ones in (), so they are visible without
+// colours
+var BracketMarks = Marks{"[", "]", "(", ")"}
+
+// Annotate returns the schematic with the counted numbers and the rest of them marked
+func (s *Schematic) Annotate(counted []Number, marks Marks) string {
+	countedSet := util.NewSet(counted...)
+
+	var sb strings.Builder
+	numberIdx := 0
+	for rowIdx, line := range s.lines {
+		if rowIdx > 0 {
+			sb.WriteByte('\n')
+		}
+
+		colIdx := 0
+		for ; numberIdx < len(s.numbers) && s.numbers[numberIdx].Row == rowIdx; numberIdx++ {
+			number := s.numbers[numberIdx]
+			start, end := marks.IgnoredStart, marks.IgnoredEnd
+			if countedSet.Contains(number) {
+				start, end = marks.CountedStart, marks.CountedEnd
+			}
+
+			sb.WriteString(line[colIdx:number.Start])
+			sb.WriteString(start)
+			sb.WriteString(line[number.Start:number.End])
+			sb.WriteString(end)
+			colIdx = number.End
+		}
+		sb.WriteString(line[colIdx:])
+	}
+	return sb.String()
+}
+
+// pointsAround returns points of the ring around the number. Some of them may be outside of the
+// schematic; no symbol is found there.
+func pointsAround(number Number) []point {
+	points := make([]point, 0, 2*(number.End-number.Start)+6)
+	for col := number.Start - 1; col <= number.End; col++ {
+		points = append(points, point{number.Row - 1, col}, point{number.Row + 1, col})
+	}
+	return append(points, point{number.Row, number.Start - 1}, point{number.Row, number.End})
+}
+
+const nilIdx = -1
+
+func parseNumbers(line string, rowIdx int) ([]Number, error) {
+	var numbers []Number
+
+	startRegionIdx := nilIdx
+	for charIdx := 0; charIdx < len(line); charIdx++ {
+		if isDigit(line[charIdx]) {
+			if startRegionIdx == nilIdx {
+				startRegionIdx = charIdx
+			}
+		} else {
+			if startRegionIdx != nilIdx {
+				number, err := parseNumber(line, rowIdx, startRegionIdx, charIdx)
+				if err != nil {
+					return nil, err
+				}
+				numbers = append(numbers, number)
+
+				startRegionIdx = nilIdx
+			}
+		}
+	}
+
+	if startRegionIdx != nilIdx {
+		number, err := parseNumber(line, rowIdx, startRegionIdx, len(line))
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func parseNumber(line string, rowIdx, startIdx, endIdx int) (Number, error) {
+	value, err := parseNumberValue(line[startIdx:endIdx])
+	if err != nil {
+		return Number{}, errors.Join(fmt.Errorf("Line %d: Invalid part ID at %d", rowIdx+1,
+			startIdx+1), err)
+	}
+
+	return Number{rowIdx, startIdx, endIdx, value}, nil
+}
+
+func parseNumberValue(numberStr string) (uint, error) {
+	// potential int truncation
+	if len(numberStr) > 6 {
+		return 0, fmt.Errorf("Potentionally too big part ID for int: %s", numberStr)
+	}
+
+	return util.ParseUint(numberStr)
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
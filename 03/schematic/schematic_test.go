@@ -0,0 +1,106 @@
+package schematic
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParse(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt", "../part1/debug*.txt"},
+		func(t *testing.T, lines []string) {
+			Parse(lines)
+		})
+}
+
+func parseSample(t *testing.T) *Schematic {
+	t.Helper()
+
+	s, err := Parse(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func sumValues(numbers []Number) uint {
+	var sum uint
+	for _, number := range numbers {
+		sum += number.Value
+	}
+	return sum
+}
+
+func TestAdjacentNumbers(t *testing.T) {
+	s := parseSample(t)
+
+	tests := []struct {
+		name  string
+		class Class
+		sum   uint
+	}{
+		{"any", AnySymbol, 4361},
+		{"stars", OneOf("*"), 467 + 35 + 617 + 755 + 598},
+		{"dollars and hashes", OneOf("$#"), 633 + 664},
+		{"none", OneOf("@"), 0},
+	}
+	for _, test := range tests {
+		if sum := sumValues(s.AdjacentNumbers(test.class)); sum != test.sum {
+			t.Errorf("%s: Expected sum %d, got %d", test.name, test.sum, sum)
+		}
+	}
+}
+
+func TestGears(t *testing.T) {
+	s := parseSample(t)
+
+	gears := s.Gears(OneOf("*"), 2)
+	if len(gears) != 2 {
+		t.Fatalf("Expected 2 gears, got %v", gears)
+	}
+	if gears[0].Row != 1 || gears[0].Col != 3 || gears[1].Row != 8 || gears[1].Col != 5 {
+		t.Errorf("Unexpected gears %v", gears)
+	}
+
+	var productSum, sumSum uint
+	for _, gear := range gears {
+		productSum += gear.Combine(Product)
+		sumSum += gear.Combine(Sum)
+	}
+	if productSum != 467835 {
+		t.Errorf("Expected gear ratio sum 467835, got %d", productSum)
+	}
+	if sumSum != 467+35+755+598 {
+		t.Errorf("Unexpected sum of gear sums %d", sumSum)
+	}
+
+	lonely := s.Gears(OneOf("*"), 1)
+	if len(lonely) != 1 || lonely[0].Numbers[0].Value != 617 {
+		t.Errorf("Unexpected gears with a single number %v", lonely)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	s := parseSample(t)
+
+	testutil.AssertGolden(t, "testdata/annotated.golden",
+		s.Annotate(s.AdjacentNumbers(AnySymbol), BracketMarks))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, lines := range [][]string{{"...", ".1234567."}, {"12345678"}} {
+		if _, err := Parse(lines); err == nil {
+			t.Errorf("Schematic %q is accepted", lines)
+		}
+	}
+}
+
+func TestNonASCIIDigitsAreSymbols(t *testing.T) {
+	s, err := Parse([]string{"12٣4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := sumValues(s.AdjacentNumbers(AnySymbol)); len(s.Numbers()) != 2 || sum != 12+4 {
+		t.Errorf("Numbers %v. Expected 12 and 4 next to a symbol", s.Numbers())
+	}
+}
@@ -0,0 +1,189 @@
+// Package cards models scratchcards of day 4, so both parts share the parser, the validation of a
+// card table and the rules of what a card is worth.
+package cards
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+type Card struct {
+	ID      uint
+	Winning []uint
+	Numbers []uint
+}
+
+// Matches returns the count of the card numbers which are winning
+func (c Card) Matches() uint {
+	winning := util.NewSet(c.Winning...)
+
+	var matches uint
+	for _, number := range c.Numbers {
+		if winning.Contains(number) {
+			matches++
+		}
+	}
+	return matches
+}
+
+// String formats the card the way the puzzle input does
+func (c Card) String() string {
+	return fmt.Sprintf("Card %d: %s | %s", c.ID, formatNumbers(c.Winning), formatNumbers(c.Numbers))
+}
+
+// Rule turns the count of matches of a card into its points or into the count of next cards it
+// wins copies of
+type Rule func(matches uint) uint
+
+// Rules are the built-in rules by name
+var Rules = map[string]Rule{
+	"double": Double,
+	"linear": Linear,
+	"triangular": func(matches uint) uint {
+		return matches * (matches + 1) / 2
+	},
+}
+
+// Double is worth 1 for the first match and doubles for every next one
+func Double(matches uint) uint {
+	if matches == 0 {
+		return 0
+	}
+	return 1 << (matches - 1)
+}
+
+// Linear is worth 1 for every match
+func Linear(matches uint) uint {
+	return matches
+}
+
+// Score sums points of the cards
+func Score(cards []Card, rule Rule) uint {
+	var points uint
+	for _, card := range cards {
+		points += rule(card.Matches())
+	}
+	return points
+}
+
+// Cascade returns the count of instances of every card when a card wins copies of as many next
+// cards as the rule gives for its matches. Copies past the end of the table aren't won. It takes
+// linear time whatever the rule is: a card adds its instances to a range of next cards, so only
+// the changes at the range ends are recorded and summed up on the way.
+func Cascade(cards []Card, rule Rule) []uint {
+	instances := make([]uint, len(cards))
+	changes := make([]uint, len(cards)+1)
+
+	var copies uint
+	for cardIdx, card := range cards {
+		// a change may wrap around below zero, but copies never do as every range is added
+		// before it ends
+		copies += changes[cardIdx]
+		instances[cardIdx] = copies + 1 // +1 for the original card
+
+		wins := min(rule(card.Matches()), uint(len(cards)-cardIdx-1))
+		changes[cardIdx+1] += instances[cardIdx]
+		changes[uint(cardIdx)+1+wins] -= instances[cardIdx]
+	}
+	return instances
+}
+
+// ParseCards parses a card per line. Cards must be numbered consecutively and have as many
+// winning numbers and numbers as the first card.
+func ParseCards(lines []string) ([]Card, error) {
+	cards := make([]Card, 0, len(lines))
+	for lineIdx, line := range lines {
+		card, err := ParseCard(line)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
+
+		if lineIdx > 0 {
+			first := cards[0]
+			if expectedID := first.ID + uint(lineIdx); card.ID != expectedID {
+				return nil, fmt.Errorf("Line %d: Card %d is expected, got card %d", lineIdx+1,
+					expectedID, card.ID)
+			}
+			if len(card.Winning) != len(first.Winning) || len(card.Numbers) != len(first.Numbers) {
+				return nil, fmt.Errorf("Line %d: Card %d has %d winning numbers and %d numbers, "+
+					"but card %d has %d and %d", lineIdx+1, card.ID, len(card.Winning),
+					len(card.Numbers), first.ID, len(first.Winning), len(first.Numbers))
+			}
+		}
+
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// ParseCard parses a line like "Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53"
+func ParseCard(line string) (Card, error) {
+	lineParts := strings.Split(line, ":")
+	if len(lineParts) != 2 {
+		return Card{}, fmt.Errorf("Unexpected number of colon-delimited parts: %d", len(lineParts))
+	}
+
+	cardIdStr := strings.TrimSpace(strings.TrimPrefix(lineParts[0], "Card"))
+	cardId, err := strconv.ParseUint(cardIdStr, 10, 0)
+	if err != nil {
+		return Card{}, errors.Join(fmt.Errorf("Failed to parse card ID <%s>", cardIdStr), err)
+	}
+
+	numbers := strings.Split(strings.TrimSpace(lineParts[1]), " | ")
+	if len(numbers) != 2 {
+		return Card{}, fmt.Errorf("Card %d: Unexpected number of number lists: %d", cardId,
+			len(numbers))
+	}
+
+	winning, err := parseNumbers(numbers[0])
+	if err != nil {
+		return Card{}, fmt.Errorf("Card %d, winning numbers: %w", cardId, err)
+	}
+
+	cardNumbers, err := parseNumbers(numbers[1])
+	if err != nil {
+		return Card{}, fmt.Errorf("Card %d, numbers: %w", cardId, err)
+	}
+
+	return Card{
+		ID:      uint(cardId),
+		Winning: winning,
+		Numbers: cardNumbers,
+	}, nil
+}
+
+// parseNumbers parses space separated numbers, which must be distinct
+func parseNumbers(s string) ([]uint, error) {
+	var numbers []uint
+	seen := util.NewSet[uint]()
+	for _, num := range strings.Split(s, " ") {
+		numTrimmed := strings.TrimSpace(num)
+		if len(numTrimmed) == 0 {
+			continue
+		}
+
+		number, err := util.ParseUint(numTrimmed)
+		if err != nil {
+			return nil, err
+		}
+		if seen.Contains(number) {
+			return nil, fmt.Errorf("Duplicate number %d", number)
+		}
+		seen.Add(number)
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func formatNumbers(numbers []uint) string {
+	strs := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		strs = append(strs, fmt.Sprintf("%2d", number))
+	}
+	return strings.Join(strs, " ")
+}
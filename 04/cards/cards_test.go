@@ -0,0 +1,106 @@
+package cards
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseCard(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			ParseCard(line)
+		})
+}
+
+func parseSample(t *testing.T) []Card {
+	t.Helper()
+
+	cards, err := ParseCards(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestScore(t *testing.T) {
+	cards := parseSample(t)
+
+	tests := []struct {
+		rule   string
+		points uint
+	}{
+		{"double", 13},
+		{"linear", 4 + 2 + 2 + 1},
+		{"triangular", 10 + 3 + 3 + 1},
+	}
+	for _, test := range tests {
+		if points := Score(cards, Rules[test.rule]); points != test.points {
+			t.Errorf("%s: Expected %d points, got %d", test.rule, test.points, points)
+		}
+	}
+}
+
+// naiveCascade processes every won copy one by one
+func naiveCascade(cards []Card, rule Rule) []uint {
+	instances := make([]uint, len(cards))
+	queue := make([]int, 0, len(cards))
+	for cardIdx := range cards {
+		queue = append(queue, cardIdx)
+	}
+	for len(queue) > 0 {
+		cardIdx := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		instances[cardIdx]++
+		wins := rule(cards[cardIdx].Matches())
+		for i := cardIdx + 1; i < len(cards) && uint(i-cardIdx) <= wins; i++ {
+			queue = append(queue, i)
+		}
+	}
+	return instances
+}
+
+func TestCascade(t *testing.T) {
+	cards := parseSample(t)
+
+	instances := Cascade(cards, Linear)
+	if expected := []uint{1, 2, 4, 8, 14, 1}; !slices.Equal(instances, expected) {
+		t.Errorf("Expected instances %v, got %v", expected, instances)
+	}
+
+	for name, rule := range Rules {
+		if expected, actual := naiveCascade(cards, rule), Cascade(cards, rule); !slices.Equal(
+			expected, actual) {
+			t.Errorf("%s: Expected instances %v, got %v", name, expected, actual)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{"duplicate winning number", []string{"Card 1: 41 48 41 | 83 86  6"},
+			"Line 1: Card 1, winning numbers: Duplicate number 41"},
+		{"duplicate number", []string{"Card 1: 41 48 83 | 83 86 83"},
+			"Line 1: Card 1, numbers: Duplicate number 83"},
+		{"fewer winning numbers", []string{"Card 1: 41 48 83 | 83 86  6", "Card 2: 13 32 | 61 30 68"},
+			"Line 2: Card 2 has 2 winning numbers and 3 numbers, but card 1 has 3 and 3"},
+		{"more numbers", []string{"Card 1: 41 48 83 | 83 86  6", "Card 2: 13 32 20 | 61 30 68 1"},
+			"Line 2: Card 2 has 3 winning numbers and 4 numbers, but card 1 has 3 and 3"},
+		{"skipped card", []string{"Card 1: 41 | 83", "Card 3: 13 | 61"},
+			"Line 2: Card 2 is expected, got card 3"},
+	}
+	for _, test := range tests {
+		_, err := ParseCards(test.lines)
+		if err == nil {
+			t.Errorf("%s: Cards are accepted", test.name)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: Expected error <%s>, got <%s>", test.name, test.err, err.Error())
+		}
+	}
+}
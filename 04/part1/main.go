@@ -1,59 +1,49 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math"
+	"os"
 	"strings"
 
+	"github.com/efulmo/advent-of-code-2023/04/cards"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
 func main() {
-	lines, err := util.ReadInputFile()
-	util.PanicOnError(err)
-
-	var pointsTotal uint
-	for lineIdx, line := range lines {
-		lineParts := strings.Split(line, ":")
-		numbersStrTrimmed := strings.TrimSpace(lineParts[1])
-		numbers := strings.Split(numbersStrTrimmed, " | ")
-		luckyNumbers := strings.Split(numbers[0], " ")
-
-		luckyNumbersMap := make(map[string]bool)
-		for _, num := range luckyNumbers {
-			numTrimmed := strings.TrimSpace(num)
-			if len(numTrimmed) == 0 {
-				continue
-			}
-
-			luckyNumbersMap[numTrimmed] = true
-		}
+	defer util.ReportAllocs()
 
-		var guessedNumbersCount uint
-		cardNumbers := strings.Split(numbers[1], " ")
-		for _, num := range cardNumbers {
-			numTrimmed := strings.TrimSpace(num)
-			if len(numTrimmed) == 0 {
-				continue
-			}
-
-			_, exists := luckyNumbersMap[numTrimmed]
-			if exists {
-				guessedNumbersCount++
-			}
-		}
+	scoring := flag.String("scoring", "double", "rule turning matches of a card into its points: "+
+		strings.Join(util.MapKeysToSortedSlice(cards.Rules), ", "))
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	rule, found := cards.Rules[*scoring]
+	if !found {
+		fmt.Fprintf(os.Stderr, "Unknown scoring rule %s\n", *scoring)
+		os.Exit(2)
+	}
 
-		if guessedNumbersCount > 0 {
-			cardValue := uint(math.Pow(2, float64(guessedNumbersCount-1)))
+	lines, err := util.ReadFileLines(flag.Arg(0))
+	util.PanicOnError(err)
 
-			fmt.Printf("%d.%v. Lucky found: %d. Card value: %d\n", lineIdx+1,
-				numbersStrTrimmed, guessedNumbersCount, cardValue)
+	table, err := cards.ParseCards(lines)
+	util.PanicOnError(err)
 
-			pointsTotal += uint(cardValue)
+	for _, card := range table {
+		matches := card.Matches()
+		if matches > 0 {
+			fmt.Printf("%v. Lucky found: %d. Card value: %d\n", card, matches, rule(matches))
 		} else {
-			fmt.Printf("%d.%v. No numbers guessed\n", lineIdx+1, numbersStrTrimmed)
+			fmt.Printf("%v. No numbers guessed\n", card)
 		}
 	}
 
-	fmt.Println("Points total:", pointsTotal)
+	fmt.Println("Points total:", cards.Score(table, rule))
 }
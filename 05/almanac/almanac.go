@@ -0,0 +1,141 @@
+// Package almanac models almanacs of day 5, where seeds are mapped to locations through a chain of
+// rule sets, so both parts share the parser and mappings composed over the whole seed space.
+package almanac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+type Rule struct {
+	DestStart   uint
+	SourceStart uint
+	Length      uint
+}
+
+// contains tells if the value is among the sources of the rule. A rule running past MaxUint ends
+// there.
+func (r Rule) contains(value uint) bool {
+	return value >= r.SourceStart && value-r.SourceStart < r.Length
+}
+
+// offset is added to a source to get its destination. It wraps around like uint arithmetic does.
+func (r Rule) offset() uint {
+	return r.DestStart - r.SourceStart
+}
+
+type RuleSet struct {
+	Label string
+	Rules []Rule
+}
+
+// offsetAt returns the offset of the first rule containing the value. Values not covered by any
+// rule map to themselves.
+func (rs RuleSet) offsetAt(value uint) uint {
+	for _, r := range rs.Rules {
+		if r.contains(value) {
+			return r.offset()
+		}
+	}
+
+	return 0
+}
+
+func (rs RuleSet) Apply(value uint) uint {
+	return value + rs.offsetAt(value)
+}
+
+// Parse parses seeds at line 1 and rule sets starting at line 3. Rules aren't checked against each
+// other; see Check.
+func Parse(lines []string) ([]uint, []RuleSet, error) {
+	seedsStr, found := strings.CutPrefix(lines[0], "seeds: ")
+	if !found {
+		return nil, nil, errors.New("Seeds aren't found at line 1")
+	}
+	seeds, err := util.ParseUints(strings.Fields(seedsStr))
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("Invalid seeds at line 1"), err)
+	}
+	if len(seeds) == 0 {
+		return nil, nil, errors.New("No seeds found at line 1")
+	}
+
+	if len(lines) > 1 && len(lines[1]) != 0 {
+		return nil, nil, errors.New("Line 2 isn't empty")
+	}
+
+	var ruleSets []RuleSet
+	linesLen := uint(len(lines))
+	for lineIdx := uint(2); lineIdx < linesLen; {
+		ruleSet, nextRuleSetLineIdx, err := readRuleSet(lines, lineIdx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ruleSets = append(ruleSets, ruleSet)
+		lineIdx = nextRuleSetLineIdx
+	}
+
+	return seeds, ruleSets, nil
+}
+
+// SeedRanges reads seeds as start and length pairs. Empty ranges are skipped.
+func SeedRanges(seeds []uint) ([]Range, error) {
+	if len(seeds)%2 != 0 {
+		return nil, fmt.Errorf("Seeds at line 1 don't form ranges: %d numbers", len(seeds))
+	}
+
+	var seedRanges []Range
+	for i := 0; i < len(seeds); i += 2 {
+		if seeds[i+1] > 0 {
+			seedRanges = append(seedRanges, NewRange(seeds[i], seeds[i+1]))
+		}
+	}
+	return seedRanges, nil
+}
+
+func readRuleSet(lines []string, ruleSetStartIdx uint) (RuleSet, uint, error) {
+	var rules []Rule
+	var ruleSetLabel string
+
+	linesLen := uint(len(lines))
+	lineIdx := uint(ruleSetStartIdx)
+	for ; lineIdx < linesLen; lineIdx++ {
+		line := lines[lineIdx]
+
+		// end of rule set; break
+		if len(line) == 0 {
+			break
+		}
+
+		// rule set label; skip
+		if !unicode.IsDigit(rune(line[0])) {
+			ruleSetLabel = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		ruleNums := strings.Fields(line)
+		ruleNumsLen := len(ruleNums)
+		if ruleNumsLen != 3 {
+			return RuleSet{}, 0, fmt.Errorf("Invalid length of rule nums at line %d: %d", lineIdx+1,
+				ruleNumsLen)
+		}
+
+		nums, err := util.ParseUints(ruleNums)
+		if err != nil {
+			return RuleSet{}, 0, errors.Join(fmt.Errorf("Invalid rule at line %d", lineIdx+1), err)
+		}
+
+		rules = append(rules, Rule{
+			DestStart:   nums[0],
+			SourceStart: nums[1],
+			Length:      nums[2],
+		})
+	}
+
+	return RuleSet{ruleSetLabel, rules}, lineIdx + 1, nil
+}
@@ -0,0 +1,51 @@
+package almanac
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParse(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		Parse(lines)
+	})
+}
+
+func parseSample(t *testing.T) ([]uint, []RuleSet) {
+	t.Helper()
+
+	seeds, ruleSets, err := Parse(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seeds, ruleSets
+}
+
+func TestApply(t *testing.T) {
+	seeds, ruleSets := parseSample(t)
+
+	expected := []uint{82, 43, 86, 35}
+	for seedIdx, seed := range seeds {
+		for _, rs := range ruleSets {
+			seed = rs.Apply(seed)
+		}
+		if seed != expected[seedIdx] {
+			t.Errorf("Seed %d: Expected location %d, got %d", seeds[seedIdx], expected[seedIdx], seed)
+		}
+	}
+}
+
+func TestSeedRanges(t *testing.T) {
+	seedRanges, err := SeedRanges([]uint{79, 14, 55, 0, 10, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seedRanges) != 2 || seedRanges[0] != (Range{79, 92}) || seedRanges[1] != (Range{10, 10}) {
+		t.Errorf("Unexpected seed ranges %v", seedRanges)
+	}
+
+	if _, err := SeedRanges([]uint{79, 14, 55}); err == nil {
+		t.Error("Odd number of seeds is accepted")
+	}
+}
@@ -0,0 +1,212 @@
+package almanac
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// Range is a non-empty range of values. End is inclusive, so a range may reach MaxUint.
+type Range struct {
+	Start, End uint
+}
+
+// NewRange returns the range of the length starting at the start. The length must be positive.
+func NewRange(start, length uint) Range {
+	return Range{start, start + length - 1}
+}
+
+// ParseRange parses a range like 46-55
+func ParseRange(s string) (Range, error) {
+	startStr, endStr, found := strings.Cut(s, "-")
+	if !found {
+		return Range{}, fmt.Errorf("Range <%s> isn't a start-end pair", s)
+	}
+
+	nums, err := util.ParseUints([]string{startStr, endStr})
+	if err != nil {
+		return Range{}, fmt.Errorf("Range <%s>: %w", s, err)
+	}
+	if nums[0] > nums[1] {
+		return Range{}, fmt.Errorf("Range <%s> ends before it starts", s)
+	}
+
+	return Range{nums[0], nums[1]}, nil
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// Segment is a range of sources mapped to destinations by adding the offset
+type Segment struct {
+	Source Range
+	Offset uint
+}
+
+func (s Segment) Dest() Range {
+	return Range{s.Source.Start + s.Offset, s.Source.End + s.Offset}
+}
+
+// String formats the segment with the offset as a signed number
+func (s Segment) String() string {
+	return fmt.Sprintf("%v -> %v (%+d)", s.Source, s.Dest(), int(s.Offset))
+}
+
+// piece maps values from its start up to the start of the next piece by adding the offset
+type piece struct {
+	start  uint
+	offset uint
+}
+
+// Mapping is a piecewise linear function over all uint values. Its pieces are sorted, the first
+// one starts at 0 and no piece wraps around MaxUint, so the destinations of a piece form a range.
+type Mapping struct {
+	pieces []piece
+}
+
+func Identity() Mapping {
+	return Mapping{[]piece{{0, 0}}}
+}
+
+// FromRuleSet returns the mapping doing what the rule set does
+func FromRuleSet(rs RuleSet) Mapping {
+	var breakpoints []uint
+	for _, r := range rs.Rules {
+		if r.Length == 0 {
+			continue
+		}
+
+		breakpoints = append(breakpoints, r.SourceStart)
+		if end := r.SourceStart + r.Length; end > r.SourceStart {
+			breakpoints = append(breakpoints, end)
+		}
+	}
+
+	return newMapping(breakpoints, rs.offsetAt)
+}
+
+// Chain returns the mapping applying the rule sets one after another
+func Chain(ruleSets []RuleSet) Mapping {
+	m := Identity()
+	for _, rs := range ruleSets {
+		m = m.Then(FromRuleSet(rs))
+	}
+	return m
+}
+
+// Then returns the mapping applying m and then next
+func (m Mapping) Then(next Mapping) Mapping {
+	var breakpoints []uint
+	for i, p := range m.pieces {
+		breakpoints = append(breakpoints, p.start)
+
+		// a piece of next starting within the destinations of the piece splits it
+		dest := m.segment(i).Dest()
+		for _, nextPiece := range next.pieces {
+			if nextPiece.start > dest.Start && nextPiece.start <= dest.End {
+				breakpoints = append(breakpoints, nextPiece.start-p.offset)
+			}
+		}
+	}
+
+	return newMapping(breakpoints, func(value uint) uint {
+		offset := m.offsetAt(value)
+		return offset + next.offsetAt(value+offset)
+	})
+}
+
+func (m Mapping) Apply(value uint) uint {
+	return value + m.offsetAt(value)
+}
+
+// Min returns the smallest destination of the values of the range
+func (m Mapping) Min(r Range) uint {
+	var minDest uint = math.MaxUint
+	for i := m.pieceIdx(r.Start); i < len(m.pieces) && m.pieces[i].start <= r.End; i++ {
+		p := m.pieces[i]
+		minDest = min(minDest, max(p.start, r.Start)+p.offset)
+	}
+	return minDest
+}
+
+// Preimage returns sorted disjoint ranges of values mapped into the range
+func (m Mapping) Preimage(r Range) []Range {
+	var preimage []Range
+	for _, segment := range m.Segments() {
+		dest := segment.Dest()
+		start, end := max(dest.Start, r.Start), min(dest.End, r.End)
+		if start > end {
+			continue
+		}
+
+		source := Range{start - segment.Offset, end - segment.Offset}
+		if last := len(preimage) - 1; last >= 0 && preimage[last].End+1 == source.Start {
+			preimage[last].End = source.End
+		} else {
+			preimage = append(preimage, source)
+		}
+	}
+	return preimage
+}
+
+// Segments returns the pieces of the mapping as ranges of sources
+func (m Mapping) Segments() []Segment {
+	segments := make([]Segment, 0, len(m.pieces))
+	for i := range m.pieces {
+		segments = append(segments, m.segment(i))
+	}
+	return segments
+}
+
+func (m Mapping) segment(i int) Segment {
+	end := uint(math.MaxUint)
+	if i+1 < len(m.pieces) {
+		end = m.pieces[i+1].start - 1
+	}
+	return Segment{Range{m.pieces[i].start, end}, m.pieces[i].offset}
+}
+
+// pieceIdx returns the index of the piece containing the value
+func (m Mapping) pieceIdx(value uint) int {
+	return sort.Search(len(m.pieces), func(i int) bool {
+		return m.pieces[i].start > value
+	}) - 1
+}
+
+func (m Mapping) offsetAt(value uint) uint {
+	return m.pieces[m.pieceIdx(value)].offset
+}
+
+// newMapping returns the mapping with pieces starting at the breakpoints and at 0. The offset of
+// a piece is the one at its start, so the offset must not change between breakpoints.
+func newMapping(breakpoints []uint, offsetAt func(value uint) uint) Mapping {
+	breakpoints = append(breakpoints, 0)
+	slices.Sort(breakpoints)
+	breakpoints = slices.Compact(breakpoints)
+
+	var pieces []piece
+	for _, start := range breakpoints {
+		offset := offsetAt(start)
+		if len(pieces) == 0 || pieces[len(pieces)-1].offset != offset {
+			pieces = append(pieces, piece{start, offset})
+		}
+	}
+
+	// a piece is split where its destinations wrap around MaxUint, which happens at -offset
+	m := Mapping{make([]piece, 0, len(pieces))}
+	for i, p := range pieces {
+		m.pieces = append(m.pieces, p)
+
+		wrapStart := -p.offset
+		if p.offset != 0 && wrapStart > p.start && (i+1 == len(pieces) ||
+			wrapStart < pieces[i+1].start) {
+			m.pieces = append(m.pieces, piece{wrapStart, p.offset})
+		}
+	}
+	return m
+}
@@ -0,0 +1,94 @@
+package almanac
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	seeds, ruleSets := parseSample(t)
+	m := Chain(ruleSets)
+
+	for seed := uint(0); seed < 120; seed++ {
+		expected := seed
+		for _, rs := range ruleSets {
+			expected = rs.Apply(expected)
+		}
+		if location := m.Apply(seed); location != expected {
+			t.Errorf("Seed %d: Expected location %d, got %d", seed, expected, location)
+		}
+	}
+
+	seedRanges, err := SeedRanges(seeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var minLocation uint = math.MaxUint
+	for _, seedRange := range seedRanges {
+		minLocation = min(minLocation, m.Min(seedRange))
+	}
+	if minLocation != 46 {
+		t.Errorf("Expected min location 46, got %d", minLocation)
+	}
+}
+
+func TestPreimage(t *testing.T) {
+	_, ruleSets := parseSample(t)
+	m := Chain(ruleSets)
+
+	locations := Range{46, 60}
+	preimage := m.Preimage(locations)
+
+	var expected []Range
+	for seed := uint(0); seed < 120; seed++ {
+		location := m.Apply(seed)
+		if location < locations.Start || location > locations.End {
+			continue
+		}
+
+		if last := len(expected) - 1; last >= 0 && expected[last].End+1 == seed {
+			expected[last].End = seed
+		} else {
+			expected = append(expected, Range{seed, seed})
+		}
+	}
+	if !slices.Equal(preimage, expected) {
+		t.Errorf("Expected preimage %v, got %v", expected, preimage)
+	}
+}
+
+func TestWrapAround(t *testing.T) {
+	// destinations of the rule run past MaxUint and wrap around to 0
+	rs := RuleSet{"wrap", []Rule{{math.MaxUint - 1, 10, 5}}}
+	m := FromRuleSet(rs).Then(FromRuleSet(RuleSet{"shift", []Rule{{100, 0, 3}}}))
+
+	for _, seed := range []uint{9, 10, 11, 12, 13, 14, 15} {
+		expected := rs.Apply(seed)
+		if expected < 3 {
+			expected += 100
+		}
+		if location := m.Apply(seed); location != expected {
+			t.Errorf("Seed %d: Expected location %d, got %d", seed, expected, location)
+		}
+	}
+
+	if min := m.Min(Range{10, 14}); min != 100 {
+		t.Errorf("Unexpected min location %d", min)
+	}
+	expected := []Range{{0, 2}, {12, 14}, {100, 102}}
+	if preimage := m.Preimage(Range{100, 102}); !slices.Equal(preimage, expected) {
+		t.Errorf("Unexpected preimage %v", preimage)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	if r, err := ParseRange("46-55"); err != nil || r != (Range{46, 55}) {
+		t.Errorf("Unexpected range %v, error %v", r, err)
+	}
+	for _, s := range []string{"46", "55-46", "a-b"} {
+		if _, err := ParseRange(s); err == nil {
+			t.Errorf("Range %s is accepted", s)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package almanac
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Overlap is a range shared by sources or destinations of two rules of a rule set. Sources of the
+// second rule within it are shadowed by the first one.
+type Overlap struct {
+	RuleSet        string
+	Rule1Idx       int
+	Rule2Idx       int
+	InDestinations bool
+	Range          Range
+}
+
+func (o Overlap) String() string {
+	side := "sources"
+	if o.InDestinations {
+		side = "destinations"
+	}
+	return fmt.Sprintf("%s: rules %d and %d overlap in %s %v", o.RuleSet, o.Rule1Idx+1,
+		o.Rule2Idx+1, side, o.Range)
+}
+
+// Gap is a range below the last source of a rule set not covered by any rule, so its values are
+// mapped to themselves
+type Gap struct {
+	RuleSet string
+	Range   Range
+}
+
+func (g Gap) String() string {
+	return fmt.Sprintf("%s: values %v aren't covered by any rule and map to themselves", g.RuleSet,
+		g.Range)
+}
+
+// Collision is a range of destinations of a rule also produced by values mapped to themselves, so
+// different values end up at the same place
+type Collision struct {
+	RuleSet string
+	RuleIdx int
+	Range   Range
+}
+
+func (c Collision) String() string {
+	return fmt.Sprintf("%s: destinations %v of rule %d collide with values mapped to themselves",
+		c.RuleSet, c.Range, c.RuleIdx+1)
+}
+
+type Report struct {
+	Overlaps   []Overlap
+	Gaps       []Gap
+	Collisions []Collision
+}
+
+// Consistent tells if every value is mapped to a distinct one by a single rule or by itself. Gaps
+// don't break it.
+func (r Report) Consistent() bool {
+	return len(r.Overlaps) == 0 && len(r.Collisions) == 0
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d overlaps, %d gaps, %d collisions", len(r.Overlaps), len(r.Gaps),
+		len(r.Collisions))
+	for _, overlap := range r.Overlaps {
+		fmt.Fprintf(&sb, "\n%v", overlap)
+	}
+	for _, gap := range r.Gaps {
+		fmt.Fprintf(&sb, "\n%v", gap)
+	}
+	for _, collision := range r.Collisions {
+		fmt.Fprintf(&sb, "\n%v", collision)
+	}
+	return sb.String()
+}
+
+// Check reports issues of all the rule sets
+func Check(ruleSets []RuleSet) Report {
+	var report Report
+	for _, rs := range ruleSets {
+		rsReport := rs.Check()
+		report.Overlaps = append(report.Overlaps, rsReport.Overlaps...)
+		report.Gaps = append(report.Gaps, rsReport.Gaps...)
+		report.Collisions = append(report.Collisions, rsReport.Collisions...)
+	}
+	return report
+}
+
+// Check reports overlapping rules, gaps between rules and rules with destinations colliding with
+// values mapped to themselves. Rules of zero length are ignored.
+func (rs RuleSet) Check() Report {
+	var report Report
+
+	for rule1Idx, rule1 := range rs.Rules {
+		if rule1.Length == 0 {
+			continue
+		}
+
+		for rule2Idx := rule1Idx + 1; rule2Idx < len(rs.Rules); rule2Idx++ {
+			rule2 := rs.Rules[rule2Idx]
+			if rule2.Length == 0 {
+				continue
+			}
+
+			if overlap, found := intersect(rule1.sourceRange(), rule2.sourceRange()); found {
+				report.Overlaps = append(report.Overlaps,
+					Overlap{rs.Label, rule1Idx, rule2Idx, false, overlap})
+			}
+			for _, dest1 := range rule1.destRanges() {
+				for _, dest2 := range rule2.destRanges() {
+					if overlap, found := intersect(dest1, dest2); found {
+						report.Overlaps = append(report.Overlaps,
+							Overlap{rs.Label, rule1Idx, rule2Idx, true, overlap})
+					}
+				}
+			}
+		}
+	}
+
+	identityRanges := rs.identityRanges()
+	for _, identityRange := range identityRanges {
+		if identityRange.End != math.MaxUint {
+			report.Gaps = append(report.Gaps, Gap{rs.Label, identityRange})
+		}
+	}
+
+	for ruleIdx, rule := range rs.Rules {
+		if rule.Length == 0 {
+			continue
+		}
+
+		for _, dest := range rule.destRanges() {
+			for _, identityRange := range identityRanges {
+				if collision, found := intersect(dest, identityRange); found {
+					report.Collisions = append(report.Collisions,
+						Collision{rs.Label, ruleIdx, collision})
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+// identityRanges returns sorted ranges not covered by sources of any rule. The last one runs up
+// to MaxUint unless a rule does.
+func (rs RuleSet) identityRanges() []Range {
+	var sources []Range
+	for _, rule := range rs.Rules {
+		if rule.Length > 0 {
+			sources = append(sources, rule.sourceRange())
+		}
+	}
+	slices.SortFunc(sources, func(r1, r2 Range) int {
+		return cmp.Compare(r1.Start, r2.Start)
+	})
+
+	var identityRanges []Range
+	var next uint // the smallest value not covered by sources seen so far
+	for _, source := range sources {
+		if source.Start > next {
+			identityRanges = append(identityRanges, Range{next, source.Start - 1})
+		}
+		if source.End == math.MaxUint {
+			return identityRanges
+		}
+		next = max(next, source.End+1)
+	}
+	return append(identityRanges, Range{next, math.MaxUint})
+}
+
+// sourceRange returns sources of the rule, which must have a positive length
+func (r Rule) sourceRange() Range {
+	end := r.SourceStart + r.Length - 1
+	if end < r.SourceStart {
+		end = math.MaxUint
+	}
+	return Range{r.SourceStart, end}
+}
+
+// destRanges returns destinations of the rule, which must have a positive length. They are split
+// in two if they wrap around MaxUint.
+func (r Rule) destRanges() []Range {
+	source := r.sourceRange()
+	start, end := r.DestStart, source.End+r.offset()
+	if end < start {
+		return []Range{{start, math.MaxUint}, {0, end}}
+	}
+	return []Range{{start, end}}
+}
+
+func intersect(r1, r2 Range) (Range, bool) {
+	start, end := max(r1.Start, r2.Start), min(r1.End, r2.End)
+	return Range{start, end}, start <= end
+}
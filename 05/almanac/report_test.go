@@ -0,0 +1,79 @@
+package almanac
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCheckSample(t *testing.T) {
+	_, ruleSets := parseSample(t)
+
+	report := Check(ruleSets)
+	if !report.Consistent() {
+		t.Errorf("Sample almanac isn't consistent: %v", report)
+	}
+
+	expected := []Gap{
+		{"seed-to-soil map", Range{0, 49}},
+		{"water-to-light map", Range{0, 17}},
+		{"light-to-temperature map", Range{0, 44}},
+		{"humidity-to-location map", Range{0, 55}},
+	}
+	if !slices.Equal(report.Gaps, expected) {
+		t.Errorf("Expected gaps %v, got %v", expected, report.Gaps)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rs := RuleSet{"broken", []Rule{
+		{100, 10, 10}, // 10-19 -> 100-109
+		{105, 15, 10}, // 15-24 -> 105-114
+		{0, 40, 5},    // 40-44 -> 0-4
+		{50, 60, 0},   // ignored
+	}}
+	report := rs.Check()
+
+	expectedOverlaps := []Overlap{
+		{"broken", 0, 1, false, Range{15, 19}},
+		{"broken", 0, 1, true, Range{105, 109}},
+	}
+	if !slices.Equal(report.Overlaps, expectedOverlaps) {
+		t.Errorf("Expected overlaps %v, got %v", expectedOverlaps, report.Overlaps)
+	}
+
+	expectedGaps := []Gap{{"broken", Range{0, 9}}, {"broken", Range{25, 39}}}
+	if !slices.Equal(report.Gaps, expectedGaps) {
+		t.Errorf("Expected gaps %v, got %v", expectedGaps, report.Gaps)
+	}
+
+	expectedCollisions := []Collision{
+		{"broken", 0, Range{100, 109}},
+		{"broken", 1, Range{105, 114}},
+		{"broken", 2, Range{0, 4}},
+	}
+	if !slices.Equal(report.Collisions, expectedCollisions) {
+		t.Errorf("Expected collisions %v, got %v", expectedCollisions, report.Collisions)
+	}
+
+	if report.Consistent() {
+		t.Error("Broken rule set is consistent")
+	}
+}
+
+func TestCheckWrapAround(t *testing.T) {
+	// sources run up to MaxUint, so there are no values mapped to themselves above them
+	rs := RuleSet{"wrap", []Rule{{0, 10, math.MaxUint - 9}, {math.MaxUint - 9, 0, 10}}}
+	if report := rs.Check(); !report.Consistent() || len(report.Gaps) > 0 {
+		t.Errorf("Unexpected report %v", report)
+	}
+
+	rs = RuleSet{"wrap", []Rule{{math.MaxUint - 1, 10, 5}}}
+	expected := []Collision{
+		{"wrap", 0, Range{math.MaxUint - 1, math.MaxUint}},
+		{"wrap", 0, Range{0, 2}},
+	}
+	if report := rs.Check(); !slices.Equal(report.Collisions, expected) {
+		t.Errorf("Expected collisions %v, got %v", expected, report.Collisions)
+	}
+}
@@ -1,165 +1,80 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math"
-	"slices"
-	"strings"
-	"unicode"
+	"os"
 
+	"github.com/efulmo/advent-of-code-2023/05/almanac"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
-type Rule struct {
-	destStart   uint
-	sourceStart uint
-	length      uint
-}
-
-type RuleSet struct {
-	label string
-	rules []Rule
-}
-
-type SeedRange struct {
-	start  uint
-	length uint
-}
-
-func (rs RuleSet) validate() {
-	rulesLen := len(rs.rules)
-	for rule1Idx, rule1 := range rs.rules {
-		rule1SourceEnd := rule1.sourceStart + rule1.length
-		rule1DestEnd := rule1.destStart + rule1.length
-
-		for rule2Idx := rule1Idx + 1; rule2Idx < rulesLen; rule2Idx++ {
-			rule2 := rs.rules[rule2Idx]
-			rule2SourceEnd := rule2.sourceStart + rule2.length
-			rule2DestEnd := rule2.destStart + rule2.length
-
-			if (rule2.sourceStart >= rule1.sourceStart && rule2.sourceStart < rule1SourceEnd) ||
-				(rule2SourceEnd > rule1.sourceStart && rule2SourceEnd <= rule1SourceEnd) {
-				panic(fmt.Errorf("Rule %d%v conflicts with rule %d%v in ruleset %s in source ranges",
-					rule2Idx, rule2,
-					rule1Idx, rule1, rs.label))
-			}
-
-			if (rule2.destStart >= rule1.destStart && rule2.destStart < rule1DestEnd) ||
-				(rule2DestEnd > rule1.destStart && rule2DestEnd <= rule1DestEnd) {
-				panic(fmt.Errorf("Rule %d%v conflicts with rule %d%v in ruleset %s in dest ranges",
-					rule2Idx, rule2,
-					rule1Idx, rule1, rs.label))
-			}
-		}
+func main() {
+	defer util.ReportAllocs()
+
+	table := flag.Bool("table", false, "print the mapping of seeds to locations composed of all "+
+		"the rule sets")
+	locationsStr := flag.String("locations", "", "range of locations like 46-55 to find seed "+
+		"ranges producing them instead of the min location")
+	check := flag.Bool("check", false, "print overlapping rules, gaps between rules and rules "+
+		"colliding with values mapped to themselves instead of the min location")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
-}
-
-func (rs RuleSet) apply(seed uint) uint {
-	for _, r := range rs.rules {
-		sourceEnd := r.sourceStart + r.length
-		if seed >= r.sourceStart && seed < sourceEnd {
-			destShift := r.destStart - r.sourceStart
-			return seed + destShift
-		}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	return seed
-}
-
-func main() {
-	lines, err := util.ReadInputFile()
+	lines, err := util.ReadFileLines(flag.Arg(0))
 	util.PanicOnError(err)
 
-	seedsStr := strings.TrimPrefix(lines[0], "seeds: ")
-	seedsStrSlice := strings.Fields(seedsStr)
+	seeds, ruleSets, err := almanac.Parse(lines)
+	util.PanicOnError(err)
 
-	var seedRanges []SeedRange
-	seedStrSliceLen := uint(len(seedsStrSlice))
-	for i := uint(0); i < seedStrSliceLen; i += 2 {
-		seedRanges = append(seedRanges, SeedRange{
-			start:  util.ParseUintOrPanic(seedsStrSlice[i]),
-			length: util.ParseUintOrPanic(seedsStrSlice[i+1]),
-		})
+	if *check {
+		report := almanac.Check(ruleSets)
+		fmt.Println(report)
+		if !report.Consistent() {
+			os.Exit(1)
+		}
+		return
 	}
 
-	var ruleSets []RuleSet
-	linesLen := uint(len(lines))
-	for lineIdx := uint(2); lineIdx < linesLen; {
-		ruleSet, nextRuleSetLineIdx := readRuleSet(lines, lineIdx)
-
-		fmt.Printf("Parsed rule set at line %d: %v\n", lineIdx+1, ruleSet)
+	seedRanges, err := almanac.SeedRanges(seeds)
+	util.PanicOnError(err)
 
-		ruleSets = append(ruleSets, ruleSet)
-		lineIdx = nextRuleSetLineIdx
+	for ruleSetIdx, ruleSet := range ruleSets {
+		fmt.Printf("Parsed rule set %d: %v\n", ruleSetIdx+1, ruleSet)
 	}
 
 	fmt.Println("Seed ranges:", seedRanges)
 
-	var chans []chan uint
-	for seedRangeIdx, seedRange := range seedRanges {
-		fmt.Printf("Processing seed range %d/%d of size %d\n", seedRangeIdx+1, len(seedRanges),
-			seedRange.length)
-
-		ch := make(chan uint)
-		chans = append(chans, ch)
-
-		go func(sr SeedRange) {
-			var minSeed uint = math.MaxUint
-			for i := uint(0); i < sr.length; i++ {
-				seed := sr.start + i
-				for _, ruleSet := range ruleSets {
-					seed = uint(ruleSet.apply(uint(seed)))
-				}
-
-				minSeed = min(minSeed, seed)
-			}
-			ch <- minSeed
-		}(seedRange)
-	}
-
-	var finalSeeds []uint
-	for _, ch := range chans {
-		finalSeeds = append(finalSeeds, <-ch)
-	}
-
-	fmt.Println("Min seed:", slices.Min(finalSeeds))
-}
-
-func readRuleSet(lines []string, ruleSetStartIdx uint) (RuleSet, uint) {
-	var rules []Rule
-	var ruleSetLabel string
-
-	linesLen := uint(len(lines))
-	lineIdx := uint(ruleSetStartIdx)
-	for ; lineIdx < linesLen; lineIdx++ {
-		line := lines[lineIdx]
-
-		// end of rule set; break
-		if len(line) == 0 {
-			break
-		}
-
-		// rule set label; skip
-		if !unicode.IsDigit(rune(line[0])) {
-			ruleSetLabel = strings.TrimSuffix(line, ":")
-			continue
+	seedToLocation := almanac.Chain(ruleSets)
+	segments := seedToLocation.Segments()
+	fmt.Printf("Composed mapping of seeds to locations has %d segments\n", len(segments))
+	if *table {
+		for _, segment := range segments {
+			fmt.Println(segment)
 		}
+	}
 
-		ruleNums := strings.Fields(line)
-		ruleNumsLen := len(ruleNums)
-		if ruleNumsLen != 3 {
-			panic(fmt.Errorf("Invalid length of rule nums at line %d: %d", lineIdx+1, ruleNumsLen))
-		}
+	if len(*locationsStr) > 0 {
+		locations, err := almanac.ParseRange(*locationsStr)
+		util.PanicOnError(err)
 
-		rules = append(rules, Rule{
-			destStart:   util.ParseUintOrPanic(ruleNums[0]),
-			sourceStart: util.ParseUintOrPanic(ruleNums[1]),
-			length:      util.ParseUintOrPanic(ruleNums[2]),
-		})
+		fmt.Printf("Seeds producing locations %v: %v\n", locations,
+			seedToLocation.Preimage(locations))
+		return
 	}
 
-	ruleSet := RuleSet{ruleSetLabel, rules}
-	ruleSet.validate()
+	var minLocation uint = math.MaxUint
+	for _, seedRange := range seedRanges {
+		minLocation = min(minLocation, seedToLocation.Min(seedRange))
+	}
 
-	return ruleSet, lineIdx + 1
+	fmt.Println("Min seed:", minLocation)
 }
@@ -3,62 +3,38 @@ package main
 import (
 	"errors"
 	"fmt"
-	"math"
 	"strings"
 
+	"github.com/efulmo/advent-of-code-2023/06/race"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	times := util.StringsToUints(strings.Fields(lines[0])[1:])
+	times, distances, err := parseRaces(lines)
+	util.PanicOnError(err)
 	fmt.Println("Time:", times)
-	distances := util.StringsToUints(strings.Fields(lines[1])[1:])
 	fmt.Println("Distances:", distances)
 
 	timesLen := uint(len(times))
-	if len(times) != len(distances) {
-		panic(fmt.Errorf("Number of times %d is different to number of distances - %d", timesLen,
-			len(distances)))
-	}
 
 	winningWaysCountProd := uint(1)
 	for i := uint(0); i < timesLen; i++ {
 		time, distance := times[i], distances[i]
 
-		boatSpeed, err := calculateBoatSpeed(time, distance)
-		if err != nil {
-			panic(errors.Join(fmt.Errorf("Failed to calculate boat speed for time %d, distance %d",
-				time, distance)))
-		}
-		isGameSpeedInt := math.Round(boatSpeed) == boatSpeed
-
-		var minChargingTimeToBeat uint
-		if isGameSpeedInt {
-			minChargingTimeToBeat = uint(boatSpeed) + 1
-		} else {
-			minChargingTimeToBeat = uint(math.Ceil(boatSpeed))
-		}
-
-		longestDistanceTimeF := float64(time) / 2
-		longestDistanceTime := uint(longestDistanceTimeF)
-		isLongestDistanceTimeInt := math.Round(longestDistanceTimeF) == longestDistanceTimeF
-
-		if minChargingTimeToBeat >= longestDistanceTime {
-			fmt.Printf("Unable to win in game %d. Mininal charging time to win is %d, while longest "+
-				"disance may be covered after charging time %d", i+1, minChargingTimeToBeat,
-				longestDistanceTime)
+		first, last, ok := race.SolveUint(time, distance)
+		if !ok {
+			fmt.Printf("Unable to win in game %d. Distance %d can't be beaten in %d ms\n", i+1,
+				distance, time)
 			continue
 		}
 
-		var winningWays uint
-		if isLongestDistanceTimeInt {
-			winningWays = (longestDistanceTime-minChargingTimeToBeat)*2 + 1
-		} else {
-			winningWays = (longestDistanceTime - minChargingTimeToBeat + 1) * 2
-		}
+		winningWays := last - first + 1
+		fmt.Printf("Holding for %d to %d ms wins game %d\n", first, last, i+1)
 		fmt.Printf("Winning ways for game %d is %d\n", i+1, winningWays)
 
 		winningWaysCountProd *= winningWays
@@ -67,26 +43,33 @@ func main() {
 	fmt.Println("Winning ways prod:", winningWaysCountProd)
 }
 
-func calculateBoatSpeed(time, distance uint) (float64, error) {
-	timeF, distanceF := float64(time), float64(distance)
+func parseRaces(lines []string) ([]uint, []uint, error) {
+	if len(lines) < 2 {
+		return nil, nil, fmt.Errorf("Unexpected number of lines: %d", len(lines))
+	}
 
-	d := timeF*timeF - 4*distanceF
-	if d < 0 {
-		return 0.0, fmt.Errorf("Negative discriminant %f", d)
+	timesStr, found := strings.CutPrefix(lines[0], "Time:")
+	if !found {
+		return nil, nil, errors.New("Times aren't found at line 1")
+	}
+	times, err := util.ParseUints(strings.Fields(timesStr))
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("Invalid times at line 1"), err)
 	}
-	sqrtD := math.Sqrt(d)
-	speed1 := (-timeF + sqrtD) / -2
-	speed2 := (-timeF - sqrtD) / -2
 
-	if speed1 < 0 && speed2 < 0 {
-		return 0.0, errors.New("Both roots are negative")
+	distancesStr, found := strings.CutPrefix(lines[1], "Distance:")
+	if !found {
+		return nil, nil, errors.New("Distances aren't found at line 2")
+	}
+	distances, err := util.ParseUints(strings.Fields(distancesStr))
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("Invalid distances at line 2"), err)
 	}
 
-	if speed1 < 0 {
-		return speed2, nil
-	} else if speed2 < 0 {
-		return speed1, nil
+	if len(times) != len(distances) {
+		return nil, nil, fmt.Errorf("Number of times %d is different to number of distances - %d",
+			len(times), len(distances))
 	}
 
-	return min(speed1, speed2), nil
+	return times, distances, nil
 }
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseRaces(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		parseRaces(lines)
+	})
+}
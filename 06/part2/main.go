@@ -3,76 +3,66 @@ package main
 import (
 	"errors"
 	"fmt"
-	"math"
+	"math/big"
 	"strings"
 
+	"github.com/efulmo/advent-of-code-2023/06/race"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	time := util.ParseUintOrPanic(strings.ReplaceAll(strings.TrimPrefix(lines[0], "Time: "), " ", ""))
+	time, distance, err := parseRace(lines)
+	util.PanicOnError(err)
 	fmt.Println("Time:", time)
-	distance := util.ParseUintOrPanic(
-		strings.ReplaceAll(strings.TrimPrefix(lines[1], "Distance: "), " ", ""))
 	fmt.Println("Distance:", distance)
 
-	boatSpeed, err := calculateBoatSpeed(time, distance)
-	if err != nil {
-		panic(errors.Join(fmt.Errorf("Failed to calculate boat speed for time %d, distance %d",
-			time, distance)))
-	}
-	isGameSpeedInt := math.Round(boatSpeed) == boatSpeed
-
-	var minChargingTimeToBeat uint
-	if isGameSpeedInt {
-		minChargingTimeToBeat = uint(boatSpeed) + 1
-	} else {
-		minChargingTimeToBeat = uint(math.Ceil(boatSpeed))
+	first, last, ok := race.Solve(time, distance)
+	if !ok {
+		panic(fmt.Errorf("Unable to win in the game. Distance %d can't be beaten in %d ms",
+			distance, time))
 	}
 
-	longestDistanceTimeF := float64(time) / 2
-	longestDistanceTime := uint(longestDistanceTimeF)
-	isLongestDistanceTimeInt := math.Round(longestDistanceTimeF) == longestDistanceTimeF
+	fmt.Printf("Holding for %d to %d ms wins the game\n", first, last)
+	fmt.Println("Winning ways for game is", race.Ways(first, last))
+}
 
-	if minChargingTimeToBeat >= longestDistanceTime {
-		panic(fmt.Errorf("Unable to win in the game. Mininal charging time to win is %d, while "+
-			"longest disance may be covered after charging time %d", minChargingTimeToBeat,
-			longestDistanceTime))
+// parseRace reads numbers with spaces ignored. They may be too big for uint.
+func parseRace(lines []string) (*big.Int, *big.Int, error) {
+	if len(lines) < 2 {
+		return nil, nil, fmt.Errorf("Unexpected number of lines: %d", len(lines))
 	}
 
-	var winningWays uint
-	if isLongestDistanceTimeInt {
-		winningWays = (longestDistanceTime-minChargingTimeToBeat)*2 + 1
-	} else {
-		winningWays = (longestDistanceTime - minChargingTimeToBeat + 1) * 2
+	timeStr, found := strings.CutPrefix(lines[0], "Time:")
+	if !found {
+		return nil, nil, errors.New("Time isn't found at line 1")
 	}
-
-	fmt.Println("Winning ways for game is", winningWays)
-}
-
-func calculateBoatSpeed(time, distance uint) (float64, error) {
-	timeF, distanceF := float64(time), float64(distance)
-
-	d := timeF*timeF - 4*distanceF
-	if d < 0 {
-		return 0.0, fmt.Errorf("Negative discriminant %f", d)
+	time, err := parseBigUint(strings.ReplaceAll(timeStr, " ", ""))
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("Invalid time at line 1"), err)
 	}
-	sqrtD := math.Sqrt(d)
-	speed1 := (-timeF + sqrtD) / -2
-	speed2 := (-timeF - sqrtD) / -2
 
-	if speed1 < 0 && speed2 < 0 {
-		return 0.0, errors.New("Both roots are negative")
+	distanceStr, found := strings.CutPrefix(lines[1], "Distance:")
+	if !found {
+		return nil, nil, errors.New("Distance isn't found at line 2")
 	}
+	distance, err := parseBigUint(strings.ReplaceAll(distanceStr, " ", ""))
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("Invalid distance at line 2"), err)
+	}
+
+	return time, distance, nil
+}
 
-	if speed1 < 0 {
-		return speed2, nil
-	} else if speed2 < 0 {
-		return speed1, nil
+func parseBigUint(s string) (*big.Int, error) {
+	if len(s) == 0 || strings.Trim(s, "0123456789") != "" {
+		return nil, fmt.Errorf("<%s> isn't an unsigned number", s)
 	}
 
-	return min(speed1, speed2), nil
+	n, _ := new(big.Int).SetString(s, 10)
+	return n, nil
 }
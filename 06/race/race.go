@@ -0,0 +1,105 @@
+// Package race finds hold times beating boat race records of day 6 exactly. Holding the button for
+// h ms of a t ms race covers h*(t-h) mm, so winning hold times lie between the roots of
+// h*(t-h) = record. They are found with an integer square root, so no float rounding shifts them
+// however large the race is.
+package race
+
+import (
+	"math"
+	"math/big"
+)
+
+// SolveUint returns the first and the last hold time beating the record. ok is false if the
+// record can't be beaten. Races too big for uint64 arithmetic are solved with math/big.
+func SolveUint(time, record uint) (first, last uint, ok bool) {
+	// time*time and 4*record fit in uint64
+	if uint64(time) < 1<<32 && uint64(record) < 1<<62 {
+		first, last, ok := solveUint64(uint64(time), uint64(record))
+		return uint(first), uint(last), ok
+	}
+
+	firstBig, lastBig, ok := Solve(new(big.Int).SetUint64(uint64(time)),
+		new(big.Int).SetUint64(uint64(record)))
+	if !ok {
+		return 0, 0, false
+	}
+	// hold times don't exceed the time, so they fit in uint
+	return uint(firstBig.Uint64()), uint(lastBig.Uint64()), true
+}
+
+func solveUint64(time, record uint64) (uint64, uint64, bool) {
+	beats := func(hold uint64) bool {
+		return hold*(time-hold) > record
+	}
+
+	if 4*record > time*time {
+		return 0, 0, false
+	}
+	// the first root is (time - sqrt(time^2 - 4*record)) / 2, so the first hold time is next to it
+	first := (time - isqrt(time*time-4*record)) / 2
+	for first <= time/2 && !beats(first) {
+		first++
+	}
+	for first > 0 && beats(first-1) {
+		first--
+	}
+
+	if first > time/2 {
+		return 0, 0, false
+	}
+	// h*(t-h) is symmetric around t/2
+	return first, time - first, true
+}
+
+// Solve is SolveUint for races of any size. time and record aren't modified.
+func Solve(time, record *big.Int) (first, last *big.Int, ok bool) {
+	one := big.NewInt(1)
+	halfTime := new(big.Int).Rsh(time, 1)
+	distance := new(big.Int)
+	beats := func(hold *big.Int) bool {
+		distance.Sub(time, hold)
+		distance.Mul(distance, hold)
+		return distance.Cmp(record) > 0
+	}
+
+	discriminant := new(big.Int).Mul(time, time)
+	discriminant.Sub(discriminant, new(big.Int).Lsh(record, 2))
+	if discriminant.Sign() < 0 {
+		return nil, nil, false
+	}
+
+	first = new(big.Int).Sqrt(discriminant)
+	first.Sub(time, first)
+	first.Rsh(first, 1)
+	for first.Cmp(halfTime) <= 0 && !beats(first) {
+		first.Add(first, one)
+	}
+	prev := new(big.Int)
+	for first.Sign() > 0 && beats(prev.Sub(first, one)) {
+		first.Set(prev)
+	}
+
+	if first.Cmp(halfTime) > 0 {
+		return nil, nil, false
+	}
+	return first, new(big.Int).Sub(time, first), true
+}
+
+// Ways returns the count of hold times from the first to the last one
+func Ways(first, last *big.Int) *big.Int {
+	ways := new(big.Int).Sub(last, first)
+	return ways.Add(ways, big.NewInt(1))
+}
+
+// isqrt returns the largest root whose square doesn't exceed n
+func isqrt(n uint64) uint64 {
+	// the float root is off by at most a few units for big n
+	root := uint64(math.Sqrt(float64(n)))
+	for root > 0 && (root > math.MaxUint32 || root*root > n) {
+		root--
+	}
+	for root < math.MaxUint32 && (root+1)*(root+1) <= n {
+		root++
+	}
+	return root
+}
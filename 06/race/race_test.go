@@ -0,0 +1,113 @@
+package race
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"testing"
+)
+
+func TestSolveUint(t *testing.T) {
+	tests := []struct {
+		time, record uint
+		first, last  uint
+		ok           bool
+	}{
+		{7, 9, 2, 5, true},
+		{15, 40, 4, 11, true},
+		// the record is reached exactly by holding for 10 and 20 ms
+		{30, 200, 11, 19, true},
+		{71530, 940200, 14, 71516, true},
+		// the record is reached exactly at the top only
+		{4, 4, 0, 0, false},
+		{4, 3, 2, 2, true},
+		{5, 6, 0, 0, false},
+		{5, 5, 2, 3, true},
+		{5, 0, 1, 4, true},
+		{0, 0, 0, 0, false},
+		{1, 0, 0, 0, false},
+		{2, 0, 1, 1, true},
+		{10, 1000, 0, 0, false},
+		{math.MaxUint64, math.MaxUint64, 2, math.MaxUint64 - 2, true},
+	}
+	for _, test := range tests {
+		first, last, ok := SolveUint(test.time, test.record)
+		if ok != test.ok || (ok && (first != test.first || last != test.last)) {
+			t.Errorf("Time %d, record %d: Expected %d-%d (%t), got %d-%d (%t)", test.time,
+				test.record, test.first, test.last, test.ok, first, last, ok)
+		}
+	}
+}
+
+func TestSolveUintBruteForce(t *testing.T) {
+	for time := uint(0); time <= 60; time++ {
+		for record := uint(0); record <= time*time/4+1; record++ {
+			expectedFirst, expectedLast, expectedOk := uint(0), uint(0), false
+			for hold := uint(0); hold <= time; hold++ {
+				if hold*(time-hold) > record {
+					if !expectedOk {
+						expectedFirst, expectedOk = hold, true
+					}
+					expectedLast = hold
+				}
+			}
+
+			first, last, ok := SolveUint(time, record)
+			if ok != expectedOk || (ok && (first != expectedFirst || last != expectedLast)) {
+				t.Fatalf("Time %d, record %d: Expected %d-%d (%t), got %d-%d (%t)", time, record,
+					expectedFirst, expectedLast, expectedOk, first, last, ok)
+			}
+		}
+	}
+}
+
+// TestExactRecords checks races where floats can't tell a reached record from a beaten one
+func TestExactRecords(t *testing.T) {
+	for _, time := range []uint{1<<26 + 3, 1<<31 + 11, 1<<32 - 1, 1 << 32, 1<<40 + 7, 1<<63 + 5} {
+		for _, hold := range []uint{1, 12345, time / 3, time/2 - 1} {
+			if hi, _ := bits.Mul64(uint64(hold), uint64(time-hold)); hi != 0 {
+				continue
+			}
+			record := hold * (time - hold)
+
+			first, last, ok := SolveUint(time, record)
+			if !ok || first != hold+1 || last != time-hold-1 {
+				t.Errorf("Time %d, record %d: Expected %d-%d, got %d-%d (%t)", time, record,
+					hold+1, time-hold-1, first, last, ok)
+			}
+
+			first, last, ok = SolveUint(time, record-1)
+			if !ok || first != hold || last != time-hold {
+				t.Errorf("Time %d, record %d: Expected %d-%d, got %d-%d (%t)", time, record-1,
+					hold, time-hold, first, last, ok)
+			}
+		}
+	}
+}
+
+func TestSolveBig(t *testing.T) {
+	time, _ := new(big.Int).SetString("1000000000000000000000000000007", 10)
+	hold, _ := new(big.Int).SetString("123456789012345678901234", 10)
+	record := new(big.Int).Sub(time, hold)
+	record.Mul(record, hold)
+
+	first, last, ok := Solve(time, record)
+	expectedFirst := new(big.Int).Add(hold, big.NewInt(1))
+	expectedLast := new(big.Int).Sub(time, expectedFirst)
+	if !ok || first.Cmp(expectedFirst) != 0 || last.Cmp(expectedLast) != 0 {
+		t.Errorf("Expected %d-%d, got %d-%d (%t)", expectedFirst, expectedLast, first, last, ok)
+	}
+
+	expectedWays := new(big.Int).Sub(time, new(big.Int).Lsh(hold, 1))
+	expectedWays.Sub(expectedWays, big.NewInt(1))
+	if ways := Ways(first, last); ways.Cmp(expectedWays) != 0 {
+		t.Errorf("Expected %d ways, got %d", expectedWays, ways)
+	}
+
+	// the top of the parabola is 1/4 of time^2
+	top := new(big.Int).Mul(time, time)
+	top.Rsh(top, 2)
+	if _, _, ok := Solve(time, top); ok {
+		t.Error("Record at the top is beaten")
+	}
+}
@@ -0,0 +1,282 @@
+// Package camel evaluates hands of Camel Cards of day 7. Card order, wild cards, hand size and the
+// way ties are broken are configured by Rules, so variants of the game share the evaluator.
+package camel
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// TieBreak is the order in which cards of hands with the same combination are compared
+type TieBreak int
+
+const (
+	// LeftToRight compares cards from the first to the last one
+	LeftToRight TieBreak = iota
+	// RightToLeft compares cards from the last to the first one
+	RightToLeft
+	// StrongestFirst compares cards sorted from the strongest to the weakest one like poker does
+	StrongestFirst
+)
+
+var tieBreakNames = map[TieBreak]string{
+	LeftToRight:    "left-to-right",
+	RightToLeft:    "right-to-left",
+	StrongestFirst: "strongest-first",
+}
+
+func (tb TieBreak) String() string {
+	return tieBreakNames[tb]
+}
+
+// Set parses the tie break by name, so it can be a flag
+func (tb *TieBreak) Set(name string) error {
+	for tieBreak, tieBreakName := range tieBreakNames {
+		if tieBreakName == name {
+			*tb = tieBreak
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown tie break %s", name)
+}
+
+type Rules struct {
+	// CardOrder lists cards from the strongest to the weakest one
+	CardOrder string
+	// Wild cards join the biggest group of other cards of a hand. They are as strong as their
+	// place in CardOrder when ties are broken.
+	Wild     string
+	HandSize int
+	TieBreak TieBreak
+}
+
+// Standard are the rules of part 1
+var Standard = Rules{"AKQJT98765432", "", 5, LeftToRight}
+
+// Jokers are the rules of part 2, where J is the weakest card but is wild
+var Jokers = Rules{"AKQT98765432J", "J", 5, LeftToRight}
+
+// AddFlags lets the rules be overridden by flags. Current values of the rules are the defaults.
+func (r *Rules) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&r.CardOrder, "order", r.CardOrder, "cards from the strongest to the weakest one")
+	fs.StringVar(&r.Wild, "wild", r.Wild, "wild cards joining the biggest group of other cards")
+	fs.IntVar(&r.HandSize, "hand-size", r.HandSize, "number of cards in a hand")
+
+	var tieBreakNamesList []string
+	for tieBreak := LeftToRight; tieBreak <= StrongestFirst; tieBreak++ {
+		tieBreakNamesList = append(tieBreakNamesList, tieBreak.String())
+	}
+	fs.Var(&r.TieBreak, "tie-break", "order of comparing cards of hands with the same "+
+		"combination: "+strings.Join(tieBreakNamesList, ", "))
+}
+
+// Combination is sizes of groups of the same cards of a hand from the biggest one. Hands of the
+// same size are compared by it lexicographically, so [5] beats [4 1], which beats [3 2].
+type Combination []int
+
+// combinationNames are names of combinations by sizes of groups of at least 2 cards
+var combinationNames = map[string]string{
+	"":      "High card",
+	"2":     "One pair",
+	"2+2":   "Two pair",
+	"2+2+2": "Three pairs",
+	"3":     "Three of a kind",
+	"3+2":   "Full house",
+	"3+3":   "Two triples",
+	"4":     "Four of a kind",
+	"4+2":   "Four and a pair",
+	"5":     "Five of a kind",
+}
+
+func (c Combination) String() string {
+	var groupSizes []string
+	for _, size := range c {
+		if size > 1 {
+			groupSizes = append(groupSizes, strconv.Itoa(size))
+		}
+	}
+
+	key := strings.Join(groupSizes, "+")
+	if name, found := combinationNames[key]; found {
+		return name
+	}
+	if len(groupSizes) == 1 {
+		return fmt.Sprintf("%s of a kind", key)
+	}
+	return key
+}
+
+type Hand struct {
+	Cards       string
+	Bid         uint
+	Combination Combination
+	WildCount   int
+	// WildAs is the card wild cards of the hand join. It's 0 if there are no wild cards or the
+	// hand has nothing but them.
+	WildAs byte
+	// strengths are strengths of cards in the order of the tie break. A stronger card has a
+	// bigger strength.
+	strengths []int
+}
+
+func (h Hand) String() string {
+	return fmt.Sprintf("%s %d %s", h.Cards, h.Bid, h.Combination)
+}
+
+// Evaluator evaluates and compares hands by the rules
+type Evaluator struct {
+	rules    Rules
+	strength map[byte]int
+	wild     util.Set[byte]
+}
+
+func NewEvaluator(rules Rules) (*Evaluator, error) {
+	if rules.HandSize <= 0 {
+		return nil, fmt.Errorf("Hand size %d isn't positive", rules.HandSize)
+	}
+	if _, found := tieBreakNames[rules.TieBreak]; !found {
+		return nil, fmt.Errorf("Unknown tie break %d", rules.TieBreak)
+	}
+
+	e := &Evaluator{rules, make(map[byte]int, len(rules.CardOrder)), util.NewSet[byte]()}
+	for idx, card := range []byte(rules.CardOrder) {
+		if card >= 0x80 || card <= ' ' {
+			return nil, fmt.Errorf("Card %q isn't a printable ASCII char", card)
+		}
+		if _, found := e.strength[card]; found {
+			return nil, fmt.Errorf("Card %c is listed twice in the card order", card)
+		}
+		e.strength[card] = len(rules.CardOrder) - idx
+	}
+	for _, card := range []byte(rules.Wild) {
+		if _, found := e.strength[card]; !found {
+			return nil, fmt.Errorf("Wild card %c isn't in the card order", card)
+		}
+		e.wild.Add(card)
+	}
+
+	return e, nil
+}
+
+func (e *Evaluator) Rules() Rules {
+	return e.rules
+}
+
+// ParseHand parses a line like "32T3K 765" and evaluates the hand
+func (e *Evaluator) ParseHand(line string) (Hand, error) {
+	parts := strings.Fields(line)
+	if len(parts) != 2 {
+		return Hand{}, fmt.Errorf("Unexpected number of fields: %d", len(parts))
+	}
+
+	bid, err := util.ParseUint(parts[1])
+	if err != nil {
+		return Hand{}, err
+	}
+
+	return e.Evaluate(parts[0], bid)
+}
+
+// ParseHands parses a hand per line
+func (e *Evaluator) ParseHands(lines []string) ([]Hand, error) {
+	hands := make([]Hand, 0, len(lines))
+	for lineIdx, line := range lines {
+		hand, err := e.ParseHand(line)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
+		hands = append(hands, hand)
+	}
+	return hands, nil
+}
+
+func (e *Evaluator) Evaluate(cards string, bid uint) (Hand, error) {
+	if len(cards) != e.rules.HandSize {
+		return Hand{}, fmt.Errorf("Invalid number of cards: %d", len(cards))
+	}
+
+	countByCard := make(map[byte]int)
+	var wildCount int
+	for _, card := range []byte(cards) {
+		if _, found := e.strength[card]; !found {
+			return Hand{}, fmt.Errorf("Invalid card found: %c", card)
+		}
+
+		if e.wild.Contains(card) {
+			wildCount++
+		} else {
+			countByCard[card]++
+		}
+	}
+
+	// wild cards make the best combination by joining the biggest group. The strongest card is
+	// taken among groups of the same size, so the choice doesn't depend on map order.
+	var wildAs byte
+	for card, count := range countByCard {
+		if wildAs == 0 || count > countByCard[wildAs] ||
+			(count == countByCard[wildAs] && e.strength[card] > e.strength[wildAs]) {
+			wildAs = card
+		}
+	}
+	if wildCount > 0 && wildAs != 0 {
+		countByCard[wildAs] += wildCount
+	} else {
+		wildAs = 0
+	}
+
+	var combination Combination
+	for _, count := range countByCard {
+		combination = append(combination, count)
+	}
+	if len(countByCard) == 0 {
+		// nothing but wild cards
+		combination = append(combination, wildCount)
+	}
+	slices.SortFunc(combination, func(size1, size2 int) int {
+		return cmp.Compare(size2, size1)
+	})
+
+	strengths := make([]int, 0, len(cards))
+	for _, card := range []byte(cards) {
+		strengths = append(strengths, e.strength[card])
+	}
+	switch e.rules.TieBreak {
+	case RightToLeft:
+		slices.Reverse(strengths)
+	case StrongestFirst:
+		slices.SortFunc(strengths, func(s1, s2 int) int {
+			return cmp.Compare(s2, s1)
+		})
+	}
+
+	return Hand{cards, bid, combination, wildCount, wildAs, strengths}, nil
+}
+
+// Compare returns a negative number if h1 is weaker than h2, a positive one if it's stronger and 0
+// if they are equal
+func Compare(h1, h2 Hand) int {
+	if c := slices.Compare(h1.Combination, h2.Combination); c != 0 {
+		return c
+	}
+	return slices.Compare(h1.strengths, h2.strengths)
+}
+
+// Rank sorts hands from the weakest to the strongest one, so a hand ranks its index + 1
+func Rank(hands []Hand) {
+	slices.SortStableFunc(hands, Compare)
+}
+
+// Winnings sums bids of the ranked hands multiplied by their ranks
+func Winnings(ranked []Hand) uint {
+	var winnings uint
+	for idx, hand := range ranked {
+		winnings += uint(idx+1) * hand.Bid
+	}
+	return winnings
+}
@@ -0,0 +1,141 @@
+package camel
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func newTestEvaluator(t testing.TB, rules Rules) *Evaluator {
+	t.Helper()
+
+	e, err := NewEvaluator(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func FuzzParseHand(f *testing.F) {
+	standard, jokers := newTestEvaluator(f, Standard), newTestEvaluator(f, Jokers)
+
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			standard.ParseHand(line)
+			jokers.ParseHand(line)
+		})
+}
+
+func TestWinnings(t *testing.T) {
+	lines := testutil.ReadSampleLines(t, "../sample.txt")
+
+	tests := []struct {
+		name     string
+		rules    Rules
+		winnings uint
+	}{
+		{"standard", Standard, 6440},
+		{"jokers", Jokers, 5905},
+	}
+	for _, test := range tests {
+		hands, err := newTestEvaluator(t, test.rules).ParseHands(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Rank(hands)
+		if winnings := Winnings(hands); winnings != test.winnings {
+			t.Errorf("%s: Expected winnings %d, got %d", test.name, test.winnings, winnings)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	twosWild := Rules{"AKQJT98765432", "2", 5, LeftToRight}
+	sixCards := Rules{"AKQJT98765432", "", 6, LeftToRight}
+	sevenCards := Rules{"AKQJT98765432", "", 7, LeftToRight}
+
+	tests := []struct {
+		rules       Rules
+		cards       string
+		combination string
+		wildAs      byte
+	}{
+		{Standard, "32T3K", "One pair", 0},
+		{Standard, "KTJJT", "Two pair", 0},
+		{Jokers, "KTJJT", "Four of a kind", 'T'},
+		{Jokers, "QQQJA", "Four of a kind", 'Q'},
+		// groups of the same size are joined by the strongest card
+		{Jokers, "KKQQJ", "Full house", 'K'},
+		{Jokers, "JJJJJ", "Five of a kind", 0},
+		{Jokers, "2345J", "One pair", '5'},
+		{twosWild, "2345J", "One pair", 'J'},
+		{twosWild, "22A2A", "Five of a kind", 'A'},
+		{sixCards, "AAAKKK", "Two triples", 0},
+		{sixCards, "AAAAAA", "6 of a kind", 0},
+		{sixCards, "AAKKQQ", "Three pairs", 0},
+		{sixCards, "AAAKKQ", "Full house", 0},
+		{sevenCards, "AAAKKQQ", "3+2+2", 0},
+	}
+	for _, test := range tests {
+		hand, err := newTestEvaluator(t, test.rules).Evaluate(test.cards, 1)
+		if err != nil {
+			t.Errorf("%s: %s", test.cards, err.Error())
+			continue
+		}
+		if combination := hand.Combination.String(); combination != test.combination {
+			t.Errorf("%s: Expected %s, got %s", test.cards, test.combination, combination)
+		}
+		if hand.WildAs != test.wildAs {
+			t.Errorf("%s: Expected wild cards as %q, got %q", test.cards, test.wildAs, hand.WildAs)
+		}
+	}
+}
+
+func TestTieBreak(t *testing.T) {
+	tests := []struct {
+		tieBreak TieBreak
+		ranked   []string
+	}{
+		{LeftToRight, []string{"2AKQJ", "A2KQJ", "AKQJ3"}},
+		{RightToLeft, []string{"AKQJ3", "A2KQJ", "2AKQJ"}},
+		// the first two hands have the same cards, so they keep their order
+		{StrongestFirst, []string{"A2KQJ", "2AKQJ", "AKQJ3"}},
+	}
+	for _, test := range tests {
+		e := newTestEvaluator(t, Rules{Standard.CardOrder, "", 5, test.tieBreak})
+
+		var hands []Hand
+		for _, cards := range []string{"AKQJ3", "A2KQJ", "2AKQJ"} {
+			hand, err := e.Evaluate(cards, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hands = append(hands, hand)
+		}
+		Rank(hands)
+
+		var ranked []string
+		for _, hand := range hands {
+			ranked = append(ranked, hand.Cards)
+		}
+		if !slices.Equal(ranked, test.ranked) {
+			t.Errorf("%v: Expected %v, got %v", test.tieBreak, test.ranked, ranked)
+		}
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	for _, rules := range []Rules{
+		{"AKQ", "", 0, LeftToRight},
+		{"AKQA", "", 5, LeftToRight},
+		{"AKQ", "J", 5, LeftToRight},
+		{"AK Q", "", 5, LeftToRight},
+		{"AKQ", "", 5, TieBreak(7)},
+	} {
+		if _, err := NewEvaluator(rules); err == nil {
+			t.Errorf("Rules %v are accepted", rules)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package camel
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is a format of reports
+type Format int
+
+const (
+	// Table aligns columns for reading in a terminal
+	Table Format = iota
+	CSV
+)
+
+var formatNames = map[Format]string{
+	Table: "table",
+	CSV:   "csv",
+}
+
+func (f Format) String() string {
+	return formatNames[f]
+}
+
+// Set parses the format by name, so it can be a flag
+func (f *Format) Set(name string) error {
+	for format, formatName := range formatNames {
+		if formatName == name {
+			*f = format
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown report format %s", name)
+}
+
+// histogramBarWidth is the width of the bar of the most common combination in a table
+const histogramBarWidth = 40
+
+// WildAssignment describes what wild cards of the hand became, if it has any
+func (h Hand) WildAssignment() string {
+	if h.WildCount == 0 {
+		return ""
+	}
+	if h.WildAs == 0 {
+		return fmt.Sprintf("%d alone", h.WildCount)
+	}
+	return fmt.Sprintf("%d as %c", h.WildCount, h.WildAs)
+}
+
+// WriteHands reports the ranked hands from the weakest one with their combinations, wild cards,
+// ranks and winnings
+func WriteHands(w io.Writer, ranked []Hand, format Format) error {
+	header := []string{"rank", "hand", "bid", "combination", "wild", "winnings"}
+	if format == CSV {
+		header = []string{"rank", "hand", "bid", "combination", "wild_count", "wild_as",
+			"winnings"}
+	}
+
+	rows := make([][]string, 0, len(ranked))
+	for idx, hand := range ranked {
+		rank := idx + 1
+		row := []string{strconv.Itoa(rank), hand.Cards, strconv.FormatUint(uint64(hand.Bid), 10),
+			hand.Combination.String()}
+		if format == CSV {
+			var wildAs string
+			if hand.WildAs != 0 {
+				wildAs = string(hand.WildAs)
+			}
+			row = append(row, strconv.Itoa(hand.WildCount), wildAs)
+		} else {
+			row = append(row, hand.WildAssignment())
+		}
+		row = append(row, strconv.FormatUint(uint64(uint(rank)*hand.Bid), 10))
+
+		rows = append(rows, row)
+	}
+
+	return writeRows(w, header, rows, format)
+}
+
+// CombinationCount is the number of hands with the combination
+type CombinationCount struct {
+	Combination Combination
+	Hands       int
+}
+
+// Histogram counts hands by combinations from the strongest combination
+func Histogram(hands []Hand) []CombinationCount {
+	var histogram []CombinationCount
+	for _, hand := range hands {
+		idx := slices.IndexFunc(histogram, func(cc CombinationCount) bool {
+			return slices.Equal(cc.Combination, hand.Combination)
+		})
+		if idx == -1 {
+			histogram = append(histogram, CombinationCount{hand.Combination, 0})
+			idx = len(histogram) - 1
+		}
+		histogram[idx].Hands++
+	}
+
+	slices.SortFunc(histogram, func(cc1, cc2 CombinationCount) int {
+		return slices.Compare(cc2.Combination, cc1.Combination)
+	})
+	return histogram
+}
+
+// WriteHistogram reports numbers of hands by combinations. A table has bars as well.
+func WriteHistogram(w io.Writer, hands []Hand, format Format) error {
+	histogram := Histogram(hands)
+
+	var maxHands int
+	for _, cc := range histogram {
+		maxHands = max(maxHands, cc.Hands)
+	}
+
+	header := []string{"combination", "hands"}
+	if format == Table {
+		header = append(header, "")
+	}
+
+	rows := make([][]string, 0, len(histogram))
+	for _, cc := range histogram {
+		row := []string{cc.Combination.String(), strconv.Itoa(cc.Hands)}
+		if format == Table {
+			// a combination present at all gets at least a tick
+			barWidth := max(1, cc.Hands*histogramBarWidth/maxHands)
+			row = append(row, strings.Repeat("#", barWidth))
+		}
+		rows = append(rows, row)
+	}
+
+	return writeRows(w, header, rows, format)
+}
+
+func writeRows(w io.Writer, header []string, rows [][]string, format Format) error {
+	if format == CSV {
+		csvWriter := csv.NewWriter(w)
+		csvWriter.Write(header)
+		csvWriter.WriteAll(rows)
+		return csvWriter.Error()
+	}
+
+	// empty cells at line ends would leave trailing spaces, so the table is trimmed afterwards
+	var table strings.Builder
+	tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, line := range strings.SplitAfter(table.String(), "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		if _, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckReportFile fails if a report in the format can't be written to the path. A CSV report needs
+// a file, as hands and the histogram are written to separate files.
+func CheckReportFile(format Format, path string) error {
+	if format == CSV && len(path) == 0 {
+		return errors.New("A CSV report needs a file, as hands and the histogram are written to " +
+			"separate files")
+	}
+	return nil
+}
+
+// WriteReport writes the ranked hands and the histogram of their combinations. A table report has
+// both tables one after another in the file or in w if the path is empty. A CSV report has them in
+// the file and in the one named by HistogramPath.
+func WriteReport(w io.Writer, path string, ranked []Hand, format Format) error {
+	if err := CheckReportFile(format, path); err != nil {
+		return err
+	}
+
+	if format == CSV {
+		if err := writeFile(path, ranked, format, WriteHands); err != nil {
+			return err
+		}
+		return writeFile(HistogramPath(path), ranked, format, WriteHistogram)
+	}
+
+	if len(path) > 0 {
+		return writeFile(path, ranked, format, writeTables)
+	}
+	return writeTables(w, ranked, format)
+}
+
+// HistogramPath returns the path of the histogram of a CSV report, like hands-histogram.csv for
+// hands.csv
+func HistogramPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-histogram" + ext
+}
+
+func writeTables(w io.Writer, ranked []Hand, format Format) error {
+	if err := WriteHands(w, ranked, format); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	return WriteHistogram(w, ranked, format)
+}
+
+func writeFile(path string, ranked []Hand, format Format,
+	write func(w io.Writer, ranked []Hand, format Format) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Error creating report file <%s>: %w", path, err)
+	}
+
+	if err := write(file, ranked, format); err != nil {
+		file.Close()
+		return fmt.Errorf("Error writing report file <%s>: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,174 @@
+package camel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func rankSample(t *testing.T, rules Rules) []Hand {
+	t.Helper()
+
+	hands, err := newTestEvaluator(t, rules).ParseHands(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Rank(hands)
+	return hands
+}
+
+func TestWriteTables(t *testing.T) {
+	hands := rankSample(t, Jokers)
+
+	var sb strings.Builder
+	if err := WriteHands(&sb, hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "testdata/hands.golden", sb.String())
+
+	sb.Reset()
+	if err := WriteHistogram(&sb, hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, "testdata/histogram.golden", sb.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	hands := rankSample(t, Jokers)
+
+	var sb strings.Builder
+	if err := WriteHands(&sb, hands, CSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != len(hands)+1 {
+		t.Fatalf("Expected %d records, got %d", len(hands)+1, len(records))
+	}
+	expected := []string{"5", "KTJJT", "220", "Four of a kind", "2", "T", "1100"}
+	if last := records[len(records)-1]; !slices.Equal(last, expected) {
+		t.Errorf("Expected record %v, got %v", expected, last)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := Histogram(rankSample(t, Standard))
+
+	var names []string
+	for _, cc := range histogram {
+		names = append(names, fmt.Sprintf("%v=%d", cc.Combination, cc.Hands))
+	}
+	expected := "Three of a kind=2,Two pair=2,One pair=1"
+	if s := strings.Join(names, ","); s != expected {
+		t.Errorf("Expected histogram %s, got %s", expected, s)
+	}
+}
+
+func TestWildAssignment(t *testing.T) {
+	e := newTestEvaluator(t, Jokers)
+
+	tests := []struct {
+		cards, assignment string
+	}{
+		{"32T3K", ""},
+		{"KTJJT", "2 as T"},
+		{"JJJJJ", "5 alone"},
+	}
+	for _, test := range tests {
+		hand, err := e.Evaluate(test.cards, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if assignment := hand.WildAssignment(); assignment != test.assignment {
+			t.Errorf("%s: Expected wild assignment <%s>, got <%s>", test.cards, test.assignment,
+				assignment)
+		}
+	}
+}
+
+func TestHistogramPath(t *testing.T) {
+	tests := []struct {
+		path, expected string
+	}{
+		{"hands.csv", "hands-histogram.csv"},
+		{"out/report.v1.csv", "out/report.v1-histogram.csv"},
+		{"hands", "hands-histogram"},
+	}
+
+	for _, test := range tests {
+		if path := HistogramPath(test.path); path != test.expected {
+			t.Errorf("%s: Expected histogram path %s, got %s", test.path, test.expected, path)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	hands := rankSample(t, Jokers)
+
+	var handsTable, histogramTable strings.Builder
+	if err := WriteHands(&handsTable, hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteHistogram(&histogramTable, hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	expectedTables := handsTable.String() + "\n" + histogramTable.String()
+
+	var sb strings.Builder
+	if err := WriteReport(&sb, "", hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != expectedTables {
+		t.Errorf("Expected table report:\n%s\ngot:\n%s", expectedTables, sb.String())
+	}
+
+	tablePath := filepath.Join(t.TempDir(), "report.txt")
+	if err := WriteReport(nil, tablePath, hands, Table); err != nil {
+		t.Fatal(err)
+	}
+	if report := readFile(t, tablePath); report != expectedTables {
+		t.Errorf("Expected table report file:\n%s\ngot:\n%s", expectedTables, report)
+	}
+
+	var handsCSV, histogramCSV strings.Builder
+	if err := WriteHands(&handsCSV, hands, CSV); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteHistogram(&histogramCSV, hands, CSV); err != nil {
+		t.Fatal(err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "hands.csv")
+	if err := WriteReport(nil, csvPath, hands, CSV); err != nil {
+		t.Fatal(err)
+	}
+	if report := readFile(t, csvPath); report != handsCSV.String() {
+		t.Errorf("Expected CSV hands:\n%s\ngot:\n%s", handsCSV.String(), report)
+	}
+	if report := readFile(t, HistogramPath(csvPath)); report != histogramCSV.String() {
+		t.Errorf("Expected CSV histogram:\n%s\ngot:\n%s", histogramCSV.String(), report)
+	}
+
+	if err := WriteReport(&sb, "", hands, CSV); err == nil {
+		t.Error("CSV report without a file is written")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -1,177 +1,70 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"slices"
-	"strings"
+	"io"
+	"os"
 
+	"github.com/efulmo/advent-of-code-2023/07/camel"
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
-const (
-	comboNotSet    = 0
-	combo5ofAKind  = 1
-	combo4ofAKind  = 2
-	comboFullHouse = 3
-	combo3ofAKind  = 4
-	combo2Pair     = 5
-	combo1Pair     = 6
-	comboHighCard  = 7
-)
-
-const cardChars = "AKQT98765432J"
-const jokerChar = "J"
-
-var cardRanks map[rune]uint8
-
-type Hand struct {
-	cards string
-	bid   uint
-	combo uint8
-}
-
-func (h *Hand) check() {
-	if len(h.cards) != 5 {
-		panic(fmt.Errorf("Invalid number of cards: %d", len(h.cards)))
-	}
-
-	for _, card := range h.cards {
-		if !strings.ContainsRune(cardChars, card) {
-			panic(fmt.Errorf("Invalid card found: %c", card))
-		}
-	}
-}
-
-func (h *Hand) getCombination() uint8 {
-	if h.combo != comboNotSet {
-		return h.combo
+func main() {
+	defer util.ReportAllocs()
+
+	rules := camel.Jokers
+	rules.AddFlags(flag.CommandLine)
+	reportFormat := flag.String("report", "", "report ranked hands and the histogram of "+
+		"combinations in the format: table, csv")
+	reportFile := flag.String("report-file", "", "file to write the report to instead of stdout. "+
+		"A CSV report needs it, as the histogram goes to a separate file with -histogram added "+
+		"to the name")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <input-file-path>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
-
-	countByCard := map[string]uint{}
-	for _, card := range h.cards {
-		countByCard[string(card)]++
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	jokersCount := countByCard[jokerChar]
-	if jokersCount > 0 {
-		var maxNonJokerCard string
-		maxNonJokerCardCount := uint(0)
-		for card, count := range countByCard {
-			if card != jokerChar && count > maxNonJokerCardCount {
-				maxNonJokerCardCount = count
-				maxNonJokerCard = card
-			}
+	reporting := len(*reportFormat) > 0
+	var format camel.Format
+	if reporting {
+		if err := format.Set(*reportFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
 		}
-
-		if maxNonJokerCard != "" {
-			delete(countByCard, jokerChar)
-			countByCard[maxNonJokerCard] += jokersCount
+		if err := camel.CheckReportFile(format, *reportFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
 		}
 	}
 
-	var cardCounts []uint
-	for _, count := range countByCard {
-		cardCounts = append(cardCounts, count)
+	// a report may go to stdout, so progress goes to stderr then; the answer stays the last line
+	// of stdout anyway
+	var log io.Writer = os.Stdout
+	if reporting {
+		log = os.Stderr
 	}
-	cardCountsLen := len(cardCounts)
 
-	var combo uint8 = comboHighCard
-	switch cardCountsLen {
-	case 1:
-		combo = combo5ofAKind
-	case 2:
-		if slices.Contains(cardCounts, 4) {
-			combo = combo4ofAKind
-		} else {
-			combo = comboFullHouse
-		}
-	case 3:
-		if slices.Contains(cardCounts, 3) {
-			combo = combo3ofAKind
-		} else {
-			combo = combo2Pair
-		}
-	case 4:
-		combo = combo1Pair
-	}
-
-	h.combo = combo
-
-	return combo
-}
-
-func main() {
-	lines, err := util.ReadInputFile()
+	evaluator, err := camel.NewEvaluator(rules)
 	util.PanicOnError(err)
 
-	var hands []*Hand
-	for lineIdx, line := range lines {
-		parts := strings.Fields(line)
-		hand := &Hand{
-			cards: parts[0],
-			bid:   util.ParseUintOrPanic(parts[1]),
-		}
-		hand.check()
-
-		fmt.Printf("%d. Hand %v is read\n", lineIdx+1, hand)
-
-		hands = append(hands, hand)
-	}
-
-	fmt.Println("Hands before sorting:")
-	fmt.Println(ptrsToHands(hands))
-
-	slices.SortFunc(hands, compareCards)
-
-	fmt.Println("Hands after sorting:")
-	fmt.Println(ptrsToHands(hands))
-
-	handsLen := len(hands)
-	var winnings uint
-	for i := 0; i < handsLen; i++ {
-		rank := handsLen - i
-		winnings += uint(rank) * hands[i].bid
-	}
-	fmt.Println("Winnings:", winnings)
-}
-
-func compareCards(h1, h2 *Hand) int {
-	h1Combo, h2Combo := h1.getCombination(), h2.getCombination()
-
-	// fmt.Printf("Comparing %v and %v\n", *h1, *h2)
-
-	if h1Combo != h2Combo {
-		return int(h1Combo) - int(h2Combo)
-	}
-
-	ranks := getCardRanks()
-	for idx, h1r := range h1.cards {
-		rank1, rank2 := ranks[h1r], ranks[rune(h2.cards[idx])]
-		if rank1 == rank2 {
-			continue
-		}
-		return int(rank1) - int(rank2)
-	}
+	lines, err := util.ReadFileLinesLoggingTo(log, flag.Arg(0))
+	util.PanicOnError(err)
 
-	fmt.Printf("Equal hands detected! %v and %v\n", *h1, *h2)
-	return 0
-}
+	hands, err := evaluator.ParseHands(lines)
+	util.PanicOnError(err)
 
-func ptrsToHands(ptrs []*Hand) []Hand {
-	var hands []Hand
-	for _, ptr := range ptrs {
-		hands = append(hands, *ptr)
-	}
-	return hands
-}
+	camel.Rank(hands)
+	fmt.Fprintf(log, "Ranked %d hands\n", len(hands))
 
-func getCardRanks() map[rune]uint8 {
-	if cardRanks == nil {
-		cardRanks = make(map[rune]uint8, len(cardChars))
-		for idx, r := range cardChars {
-			cardRanks[r] = uint8(idx)
-		}
+	if reporting {
+		util.PanicOnError(camel.WriteReport(os.Stdout, *reportFile, hands, format))
 	}
 
-	return cardRanks
+	fmt.Println("Winnings:", camel.Winnings(hands))
 }
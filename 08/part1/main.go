@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -22,11 +23,13 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	commands := lines[0]
-	nodeByName := parseNodes(lines[2:])
+	commands, nodeByName, err := parseNetwork(lines)
+	util.PanicOnError(err)
 
 	commandIdx := uint(0)
 	nodeName := startNodeName
@@ -61,13 +64,52 @@ func main() {
 	fmt.Printf("The way took %d steps\n", stepsMade)
 }
 
-func parseNodes(lines []string) map[string]Node {
+func parseNetwork(lines []string) (string, map[string]Node, error) {
+	if len(lines) < 3 {
+		return "", nil, fmt.Errorf("Unexpected number of lines: %d", len(lines))
+	}
+
+	commands := lines[0]
+	if len(commands) == 0 {
+		return "", nil, errors.New("No commands found at line 1")
+	}
+	for _, command := range commands {
+		if command != commandLeft && command != commandRight {
+			return "", nil, fmt.Errorf("Unknown command %c at line 1", command)
+		}
+	}
+
+	if len(lines[1]) != 0 {
+		return "", nil, errors.New("Line 2 isn't empty")
+	}
+
+	nodeByName, err := parseNodes(lines[2:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, found := nodeByName[startNodeName]; !found {
+		return "", nil, fmt.Errorf("Start node %s isn't found", startNodeName)
+	}
+
+	return commands, nodeByName, nil
+}
+
+func parseNodes(lines []string) (map[string]Node, error) {
 	nodeByName := make(map[string]Node, len(lines))
 	r := strings.NewReplacer("=", "", "(", "", ")", "", ",", "")
 
-	for _, line := range lines {
+	for lineIdx, line := range lines {
 		fields := strings.Fields(r.Replace(line))
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Node %d: Unexpected number of fields: %d", lineIdx+1, len(fields))
+		}
+
 		name := fields[0]
+		if _, found := nodeByName[name]; found {
+			return nil, fmt.Errorf("Node %d: Duplicate node %s", lineIdx+1, name)
+		}
+
 		nodeByName[name] = Node{
 			name:          name,
 			leftNodeName:  fields[1],
@@ -75,5 +117,13 @@ func parseNodes(lines []string) map[string]Node {
 		}
 	}
 
-	return nodeByName
+	for _, node := range nodeByName {
+		for _, nextNodeName := range []string{node.leftNodeName, node.rightNodeName} {
+			if _, found := nodeByName[nextNodeName]; !found {
+				return nil, fmt.Errorf("Node %s refers to unknown node %s", node.name, nextNodeName)
+			}
+		}
+	}
+
+	return nodeByName, nil
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -9,12 +10,17 @@ import (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
 	var nextValueSum int
 	for lineIdx, line := range lines {
-		vals := util.StringsToInts(strings.Fields(line))
+		vals, err := parseHistory(line)
+		if err != nil {
+			panic(fmt.Errorf("Line %d: %w", lineIdx+1, err))
+		}
 		slices.Reverse(vals)
 		nextValue := predictNextValue(uint(lineIdx), vals)
 
@@ -26,6 +32,18 @@ func main() {
 	fmt.Println("Next values sum:", nextValueSum)
 }
 
+func parseHistory(line string) ([]int, error) {
+	vals, err := util.ParseInts(strings.Fields(line))
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) == 0 {
+		return nil, errors.New("No values found")
+	}
+
+	return vals, nil
+}
+
 func predictNextValue(lineIdx uint, vals []int) int {
 	var diffs [][]int
 	diffs = append(diffs, vals)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -47,21 +48,13 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var startTile Tile
-	for lineIdx, line := range lines {
-		for colIdx, r := range line {
-			if string(r) == charStart {
-				startTile = Tile{
-					rowIdx: lineIdx,
-					colIdx: colIdx,
-				}
-				break
-			}
-		}
-	}
+	startTile, err := findStartTile(lines)
+	util.PanicOnError(err)
 
 	var currentTile = startTile
 	pathLength := uint(1)
@@ -89,6 +82,36 @@ func main() {
 	fmt.Println("Farthest tile:", pathLength/2)
 }
 
+func findStartTile(lines []string) (Tile, error) {
+	var startTile Tile
+	startTileFound := false
+
+	for lineIdx, line := range lines {
+		for colIdx, r := range line {
+			switch string(r) {
+			case charStart:
+				if startTileFound {
+					return Tile{}, fmt.Errorf("Second start tile found at %d:%d", lineIdx+1, colIdx+1)
+				}
+				startTile = Tile{
+					rowIdx: lineIdx,
+					colIdx: colIdx,
+				}
+				startTileFound = true
+			case charGround, charUpDown, charRightLeft, charUpRight, charUpLeft, charRightUp, charDownRight:
+			default:
+				return Tile{}, fmt.Errorf("Unexpected char %q at %d:%d", r, lineIdx+1, colIdx+1)
+			}
+		}
+	}
+
+	if !startTileFound {
+		return Tile{}, errors.New("Start tile isn't found")
+	}
+
+	return startTile, nil
+}
+
 func getNextStep(lines []string, currentTile Tile, previousTile Tile) Step {
 	availableDirs := getAvailableDirectionsFromChar(getCharAt(lines, currentTile))
 
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"slices"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -58,29 +58,19 @@ const (
 const clusterPath = 0
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var startTile Tile
-	for lineIdx, line := range lines {
-		for colIdx, r := range line {
-			if string(r) == charStart {
-				startTile = Tile{
-					rowIdx: lineIdx,
-					colIdx: colIdx,
-				}
-				break
-			}
-		}
-	}
+	startTile, err := findStartTile(lines)
+	util.PanicOnError(err)
 
 	var currentTile = startTile
 	var previousTile Tile
 	step := uint(1)
 	path := []Tile{startTile}
-	pathCluster := map[Tile]bool{
-		startTile: true,
-	}
+	pathCluster := util.NewSet(startTile)
 
 	for {
 		nextStep := getNextStep(lines, currentTile, previousTile)
@@ -96,7 +86,7 @@ func main() {
 			nextTileChar)
 
 		path = append(path, nextStep.toTile)
-		pathCluster[nextStep.toTile] = true
+		pathCluster.Add(nextStep.toTile)
 		previousTile = currentTile
 		currentTile = nextStep.toTile
 		step++
@@ -118,7 +108,7 @@ func main() {
 			}
 			c := getCharAt(lines, t)
 
-			if pathCluster[t] {
+			if pathCluster.Contains(t) {
 				// north -> inverse
 				if c == charDownUp || c == charLeftUp || c == charRightUp {
 					isWhithinPath = !isWhithinPath
@@ -132,6 +122,36 @@ func main() {
 	fmt.Printf("%d enclosed tiles found: %v\n", len(enclosedTiles), printTiles(enclosedTiles))
 }
 
+func findStartTile(lines []string) (Tile, error) {
+	var startTile Tile
+	startTileFound := false
+
+	for lineIdx, line := range lines {
+		for colIdx, r := range line {
+			switch string(r) {
+			case charStart:
+				if startTileFound {
+					return Tile{}, fmt.Errorf("Second start tile found at %d:%d", lineIdx+1, colIdx+1)
+				}
+				startTile = Tile{
+					rowIdx: lineIdx,
+					colIdx: colIdx,
+				}
+				startTileFound = true
+			case charGround, charUpDown, charRightLeft, charUpRight, charUpLeft, charRightUp, charDownRight:
+			default:
+				return Tile{}, fmt.Errorf("Unexpected char %q at %d:%d", r, lineIdx+1, colIdx+1)
+			}
+		}
+	}
+
+	if !startTileFound {
+		return Tile{}, errors.New("Start tile isn't found")
+	}
+
+	return startTile, nil
+}
+
 func getNextStep(lines []string, currentTile Tile, previousTile Tile) Step {
 	availableDirs := getAvailableDirectionsFromChar(getCharAt(lines, currentTile))
 
@@ -240,12 +260,8 @@ func isTileValid(lines []string, tile Tile) bool {
 	return true
 }
 
-func printCluster(cluster map[Tile]bool) string {
-	var tilesSl []Tile
-	for tile := range cluster {
-		tilesSl = append(tilesSl, tile)
-	}
-	slices.SortFunc(tilesSl, func(t1, t2 Tile) int {
+func printCluster(cluster util.Set[Tile]) string {
+	return printTiles(cluster.SortedFunc(func(t1, t2 Tile) int {
 		if t1.rowIdx != t2.rowIdx {
 			return t1.rowIdx - t2.rowIdx
 		}
@@ -253,9 +269,7 @@ func printCluster(cluster map[Tile]bool) string {
 			return t1.colIdx - t2.colIdx
 		}
 		return 0
-	})
-
-	return printTiles(tilesSl)
+	}))
 }
 
 func printTiles(tiles []Tile) string {
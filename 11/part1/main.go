@@ -1,10 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/compress"
 )
 
 type Galaxy struct {
@@ -22,39 +23,31 @@ const (
 	runeGalaxy = '#'
 	runeDot    = '.'
 )
-const charDot = string(runeDot)
+const expansionRate = 2
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	galaxies := findGalaxies(lines)
+	galaxies, err := findGalaxies(lines)
+	util.PanicOnError(err)
 	galaxiesLen := uint(len(galaxies))
 	fmt.Printf("%d galaxies parsed\n", galaxiesLen)
 	// fmt.Println(galaxies)
 
-	var expandingRows []uint
-	for rowIdx, line := range lines {
-		if strings.Count(line, charDot) == len(line) {
-			expandingRows = append(expandingRows, uint(rowIdx))
-		}
+	// rows and columns between galaxies without any of them end up in gaps of the axes; they are
+	// the expanding ones
+	var galaxyRowIdxs, galaxyColIdxs []int
+	for _, g := range galaxies {
+		galaxyRowIdxs = append(galaxyRowIdxs, int(g.rowIdx))
+		galaxyColIdxs = append(galaxyColIdxs, int(g.colIdx))
 	}
-	fmt.Printf("Found %d expanding rows\n", len(expandingRows))
-	// fmt.Println(expandingRows)
-
-	colCount := uint(len(lines[0]))
-	var expandingCols []uint
-byCols:
-	for colIdx := uint(0); colIdx < colCount; colIdx++ {
-		for _, line := range lines {
-			if line[colIdx] != runeDot {
-				continue byCols
-			}
-		}
-		expandingCols = append(expandingCols, colIdx)
-	}
-	fmt.Printf("Found %d expanding cols\n", len(expandingCols))
-	// fmt.Println(expandingCols)
+	rows := compress.NewAxis(galaxyRowIdxs...)
+	cols := compress.NewAxis(galaxyColIdxs...)
+	fmt.Printf("Found %d expanding rows between galaxies\n", rows.GapsWidth(0, rows.Len()))
+	fmt.Printf("Found %d expanding cols between galaxies\n", cols.GapsWidth(0, cols.Len()))
 
 	var pairs []GalaxyPair
 	for i := uint(1); i <= galaxiesLen; i++ {
@@ -69,13 +62,8 @@ byCols:
 	for _, pair := range pairs {
 		g1 := galaxies[pair.a]
 		g2 := galaxies[pair.b]
-		minRowIdx := min(g1.rowIdx, g2.rowIdx)
-		maxRowIdx := max(g1.rowIdx, g2.rowIdx)
-		minColIdx := min(g1.colIdx, g2.colIdx)
-		maxColIdx := max(g1.colIdx, g2.colIdx)
-		pathLength := (maxRowIdx - minRowIdx) + (maxColIdx - minColIdx) + 
-			countBetween(expandingRows, minRowIdx, maxRowIdx) + 
-			countBetween(expandingCols, minColIdx, maxColIdx)
+		pathLength := expandedDistance(rows, g1.rowIdx, g2.rowIdx) +
+			expandedDistance(cols, g1.colIdx, g2.colIdx)
 
 		// fmt.Printf("Path between G%d(%d:%d) and G%d(%d:%d) is %d\n", g1.ID, g1.rowIdx, g1.colIdx, 
 		// 	g2.ID, g2.rowIdx, g2.colIdx, pathLength)
@@ -84,31 +72,41 @@ byCols:
 	fmt.Println("Paths length sum:", pathLengthSum)
 }
 
-func findGalaxies(lines []string) map[uint]Galaxy {
+func findGalaxies(lines []string) (map[uint]Galaxy, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return nil, errors.New("Image is empty")
+	}
+
 	galaxies := make(map[uint]Galaxy)
 	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return nil, fmt.Errorf("Line %d: Unexpected length %d. Expected %d", rowIdx+1, len(line),
+				len(lines[0]))
+		}
+
 		for colIdx, r := range line {
-			if r == runeGalaxy {
+			switch r {
+			case runeGalaxy:
 				ID := uint(len(galaxies) + 1)
 				galaxies[ID] = Galaxy{
 					ID:     ID,
 					rowIdx: uint(rowIdx),
 					colIdx: uint(colIdx),
 				}
+			case runeDot:
+			default:
+				return nil, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1, colIdx+1)
 			}
 		}
 	}
 
-	return galaxies
+	return galaxies, nil
 }
 
-func countBetween(expandingLines []uint, fromLineIdx, toLineIdx uint) uint {
-	cnt := uint(0)
-	for _, lineIdx := range expandingLines {
-		if lineIdx > fromLineIdx && lineIdx < toLineIdx {
-			cnt++
-		}
-	}
-
-	return cnt
-}
\ No newline at end of file
+// expandedDistance returns the distance between galaxy coordinates on the axis, where every gap
+// row or column is expansionRate times wider
+func expandedDistance(axis *compress.Axis, coord1, coord2 uint) uint {
+	fromIdx, _ := axis.Index(int(min(coord1, coord2)))
+	toIdx, _ := axis.Index(int(max(coord1, coord2)))
+	return uint(axis.Span(fromIdx, toIdx) + axis.GapsWidth(fromIdx, toIdx)*(expansionRate-1))
+}
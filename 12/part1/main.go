@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math/bits"
 	"slices"
@@ -16,23 +17,28 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var damageVariantSum uint
+	records := make([]record, 0, len(lines))
 	for lineIdx, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) != 2 {
-			panic(fmt.Errorf("Unexpected fields count in the line - %d", len(fields)))
+		springMap, checkSum, err := parseRecord(line)
+		if err != nil {
+			panic(fmt.Errorf("Line %d: %w", lineIdx+1, err))
 		}
+		records = append(records, record{springMap, checkSum})
+	}
 
-		springMap := fields[0]
-		damageCheckSum := fields[1]
-		damagedSpringStrSl := strings.Split(damageCheckSum, ",")
-		damagedSpringsSequences := util.StringsToUints(damagedSpringStrSl)
-		cnt := countDamageVariants(springMap, damagedSpringsSequences)
+	// lines are independent, so they are counted in parallel
+	counts := util.ParallelMap(records, func(r record) uint {
+		return countDamageVariants(r.springMap, r.checkSum)
+	})
 
-		fmt.Printf("%d. Map %s %s has %d damage variants\n", lineIdx+1, springMap, damageCheckSum, cnt)
+	var damageVariantSum uint
+	for lineIdx, cnt := range counts {
+		fmt.Printf("%d. Map %s has %d damage variants\n", lineIdx+1, lines[lineIdx], cnt)
 
 		damageVariantSum += cnt
 	}
@@ -40,6 +46,38 @@ func main() {
 	fmt.Println("Damage variant sum:", damageVariantSum)
 }
 
+// record is a spring map and sizes of groups of damaged springs in it
+type record struct {
+	springMap string
+	checkSum  []uint
+}
+
+func parseRecord(line string) (string, []uint, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return "", nil, fmt.Errorf("Unexpected fields count in the line - %d", len(fields))
+	}
+
+	springMap := fields[0]
+	for _, r := range springMap {
+		if r != runeOperationalSpring && r != runeDamagedSpring && r != runeUnknownSpring {
+			return "", nil, fmt.Errorf("Unexpected rune found in spring map: %c", r)
+		}
+	}
+
+	checkSum, err := util.ParseUints(strings.Split(fields[1], ","))
+	if err != nil {
+		return "", nil, err
+	}
+	for _, cnt := range checkSum {
+		if cnt == 0 {
+			return "", nil, errors.New("Damaged springs sequence of length 0 found in checksum")
+		}
+	}
+
+	return springMap, checkSum, nil
+}
+
 func countDamageVariants(springMap string, damagedSpringsCheckSum []uint) uint {
 	var damagedSpringsTotal uint
 	for _, cnt := range damagedSpringsCheckSum {
@@ -90,7 +128,7 @@ func countDamageVariants(springMap string, damagedSpringsCheckSum []uint) uint {
 	var validMapCount uint
 	for _, mask := range variantMasks {
 		unknownSpringIdx := uint(0)
-		newSpringMaps := make(map[string]bool)
+		newSpringMaps := util.NewSet[string]()
 
 		var m strings.Builder
 		for _, r := range springMap {
@@ -109,10 +147,10 @@ func countDamageVariants(springMap string, damagedSpringsCheckSum []uint) uint {
 		}
 
 		newSpringMap := m.String()
-		if newSpringMaps[newSpringMap] {
+		if newSpringMaps.Contains(newSpringMap) {
 			panic("Duplicate spring map generated!")
 		} else {
-			newSpringMaps[newSpringMap] = true
+			newSpringMaps.Add(newSpringMap)
 		}
 
 		if isSpringMapValid(newSpringMap, damagedSpringsCheckSum) {
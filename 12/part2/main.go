@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -18,54 +20,98 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	mapCache := make(map[string]uint)
-	var damageVariantSum uint
+	records := make([]record, 0, len(lines))
 	for lineIdx, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) != 2 {
-			panic(fmt.Errorf("Unexpected fields count in the line - %d", len(fields)))
+		springMap, checkSum, err := parseRecord(line)
+		if err != nil {
+			panic(fmt.Errorf("Line %d: %w", lineIdx+1, err))
 		}
+		records = append(records, record{lineIdx, springMap, checkSum})
+	}
+
+	// lines are counted in parallel, so every line has its own cache; they rarely share entries
+	// anyway as the cache key contains the spring map
+	type lineResult struct {
+		variants, cacheSize uint
+	}
+	results := util.ParallelMap(records, func(r record) lineResult {
+		springMap, damageCheckSum := r.springMap, r.checkSum
 
-		springMap := fields[0]
-		damageCheckSum := fields[1]
 		springMapUnfolded := springMap
-		damageCheckSumUnfolded := damageCheckSum
+		checkSum := slices.Clone(damageCheckSum)
 		for i := uint(0); i < 4; i++ {
 			springMapUnfolded += strUnknownSpring + springMap
-			damageCheckSumUnfolded += "," + damageCheckSum
+			checkSum = append(checkSum, damageCheckSum...)
 		}
-		validateSpringMap(springMapUnfolded)
-
-		checkSumStrSl := strings.Split(damageCheckSumUnfolded, ",")
-		checkSum := util.StringsToUints(checkSumStrSl)
 
-		variants := countDamageVariants(springMapUnfolded, checkSum, mapCache)
+		mapCache := make(map[string]uint)
+		variants := countDamageVariants(springMapUnfolded, checkSum, mapCache, r.lineIdx)
+		return lineResult{variants, uint(len(mapCache))}
+	})
 
-		fmt.Printf("%d. Map %s %s has %d damage variants\n", lineIdx+1, springMap, damageCheckSum,
-			variants)
+	var damageVariantSum, cacheSize uint
+	for lineIdx, result := range results {
+		fmt.Printf("%d. Map %s has %d damage variants\n", lineIdx+1, lines[lineIdx],
+			result.variants)
 
-		damageVariantSum += variants
+		damageVariantSum += result.variants
+		cacheSize += result.cacheSize
 	}
 
-	fmt.Println("Cache map size:", len(mapCache))
+	fmt.Println("Cache entries over all lines:", cacheSize)
 	fmt.Println("Damage variant sum:", damageVariantSum)
 }
 
-func validateSpringMap(m string) {
+// record is a spring map of the line and sizes of groups of damaged springs in it
+type record struct {
+	lineIdx   int
+	springMap string
+	checkSum  []uint
+}
+
+func parseRecord(line string) (string, []uint, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return "", nil, fmt.Errorf("Unexpected fields count in the line - %d", len(fields))
+	}
+
+	springMap := fields[0]
+	if err := validateSpringMap(springMap); err != nil {
+		return "", nil, err
+	}
+
+	checkSum, err := util.ParseUints(strings.Split(fields[1], ","))
+	if err != nil {
+		return "", nil, err
+	}
+	for _, cnt := range checkSum {
+		if cnt == 0 {
+			return "", nil, errors.New("Damaged springs sequence of length 0 found in checksum")
+		}
+	}
+
+	return springMap, checkSum, nil
+}
+
+func validateSpringMap(m string) error {
 	for _, r := range m {
 		if r != runeOperationalSpring && r != runeDamagedSpring && r != runeUnknownSpring {
-			panic(fmt.Errorf("Unexpected run found in spring map: %c", r))
+			return fmt.Errorf("Unexpected rune found in spring map: %c", r)
 		}
 	}
+	return nil
 }
 
 func countDamageVariants(
 	springMap string,
 	checkSum []uint,
-	mapCache map[string]uint) uint {
+	mapCache map[string]uint,
+	lineIdx int) uint {
 	
 	cacheKey := springMap + fmt.Sprintf("%v", checkSum)
 	if vars, ok := mapCache[cacheKey]; ok {
@@ -76,12 +122,12 @@ func countDamageVariants(
 	if len(springMap) == 0 {
 		// for good: all sequences are matched
 		if (len(checkSum)) == 0 {
-			util.DebugLog("%s %v: String map is empty as well as checksum - 1\n", springMap,
+			debugLog(lineIdx, "%s %v: String map is empty as well as checksum - 1\n", springMap,
 				checkSum)
 			return 1
 		}
 		// for bad: some sequences left unmatched; invalid case
-		util.DebugLog("%s %v: String map is empty but checksum isn't - 0\n", springMap,
+		debugLog(lineIdx, "%s %v: String map is empty but checksum isn't - 0\n", springMap,
 			checkSum)
 		return 0
 	}
@@ -105,13 +151,15 @@ func countDamageVariants(
 	if checkSumSum == 0 {
 		// but they exist; invalid case
 		if damagedSpringsMarked > 0 {
-			util.DebugLog("%s %v: Damaged springs are in the map, but they are not expected - 0\n",
+			debugLog(lineIdx,
+				"%s %v: Damaged springs are in the map, but they are not expected - 0\n",
 				springMap, checkSum)
 			mapCache[cacheKey] = 0
 			return 0
 		}
 		// no damaged springs in the map; valid case
-		util.DebugLog("%s %v: No damaged springs are in the map and no of them are expected - 1\n",
+		debugLog(lineIdx,
+			"%s %v: No damaged springs are in the map and no of them are expected - 1\n",
 			springMap, checkSum)
 		mapCache[cacheKey] = 1
 		return 1
@@ -119,21 +167,21 @@ func countDamageVariants(
 
 	// no unknown springs
 	if unknownSpringsCount == 0 && checkSumSum == 0 {
-		util.DebugLog("%s %v: No unknown springs left - 1\n", springMap, checkSum)
+		debugLog(lineIdx, "%s %v: No unknown springs left - 1\n", springMap, checkSum)
 		mapCache[cacheKey] = 1
 		return 1
 	}
 
 	// checksum is too high; invalid case
 	if checkSumSum > damagedSpringsMarked+unknownSpringsCount {
-		util.DebugLog("%s %v: Checksum is too high - 0\n", springMap, checkSum)
+		debugLog(lineIdx, "%s %v: Checksum is too high - 0\n", springMap, checkSum)
 		mapCache[cacheKey] = 0
 		return 0
 	}
 
 	// to many damaged springs; invalid case
 	if damagedSpringsMarked > checkSumSum {
-		util.DebugLog("%s %v: Too many(%d) damaged springs are in the map for checksum - 0\n",
+		debugLog(lineIdx, "%s %v: Too many(%d) damaged springs are in the map for checksum - 0\n",
 			springMap, checkSum, damagedSpringsMarked)
 		mapCache[cacheKey] = 0
 		return 0
@@ -143,7 +191,8 @@ func countDamageVariants(
 
 	// to little unknown springs; invalid case
 	if damagedSpringsToLocate > unknownSpringsCount {
-		util.DebugLog("%s %v Too many damaged springs to locate(%d) for %d unknown springs - 0\n",
+		debugLog(lineIdx,
+			"%s %v Too many damaged springs to locate(%d) for %d unknown springs - 0\n",
 			springMap, checkSum, damagedSpringsToLocate, unknownSpringsCount)
 		mapCache[cacheKey] = 0
 		return 0
@@ -152,7 +201,7 @@ func countDamageVariants(
 	switch springMap[0] {
 	case runeOperationalSpring:
 		modifiedStringMap := strings.TrimLeft(springMap, strOperationalSpring)
-		result := countDamageVariants(modifiedStringMap, checkSum, mapCache)
+		result := countDamageVariants(modifiedStringMap, checkSum, mapCache, lineIdx)
 
 		mapCache[cacheKey] = result
 		return result
@@ -172,19 +221,19 @@ func countDamageVariants(
 				}
 			}
 
-			result := countDamageVariants(cutSpringMap, cutCheckSum, mapCache)
+			result := countDamageVariants(cutSpringMap, cutCheckSum, mapCache, lineIdx)
 
 			mapCache[cacheKey] = result
 			return result
 		} else if damagedSpringsAtBeginning > firstSeq {
-			util.DebugLog("%s %v: Too long sequence of damaged springs at beginning - 0\n", springMap,
-				checkSum)
+			debugLog(lineIdx, "%s %v: Too long sequence of damaged springs at beginning - 0\n",
+				springMap, checkSum)
 			mapCache[cacheKey] = 0
 			return 0
 		} else if uint(len(springMap)) > damagedSpringsAtBeginning {
 			nextChar := springMap[damagedSpringsAtBeginning]
 			if nextChar == runeOperationalSpring {
-				util.DebugLog("%s %v: Too short sequence of damaged springs at beginning - 0\n",
+				debugLog(lineIdx, "%s %v: Too short sequence of damaged springs at beginning - 0\n",
 					springMap, checkSum)
 				mapCache[cacheKey] = 0
 				return 0
@@ -193,21 +242,21 @@ func countDamageVariants(
 			// next char is unknown spring
 			modifiedSpringMap := strings.Replace(springMap, strUnknownSpring,
 				strDamagedSpring, 1)
-			result := countDamageVariants(modifiedSpringMap, checkSum, mapCache)
+			result := countDamageVariants(modifiedSpringMap, checkSum, mapCache, lineIdx)
 			
 			mapCache[cacheKey] = result
 			return result
 		} else {
-			util.DebugLog("%s %v: Unable to match first damaged springs sequence - 0\n", springMap,
-				checkSum)
+			debugLog(lineIdx, "%s %v: Unable to match first damaged springs sequence - 0\n",
+				springMap, checkSum)
 			mapCache[cacheKey] = 0
 			return 0
 		}
 	case runeUnknownSpring:
 		operationalCaseVariants := countDamageVariants(strOperationalSpring+springMap[1:],
-			checkSum, mapCache)
+			checkSum, mapCache, lineIdx)
 		damagedCaseVariants := countDamageVariants(strDamagedSpring+springMap[1:],
-			checkSum, mapCache)
+			checkSum, mapCache, lineIdx)
 		result := operationalCaseVariants + damagedCaseVariants
 
 		mapCache[cacheKey] = result
@@ -217,6 +266,12 @@ func countDamageVariants(
 	}
 }
 
+// debugLog prefixes the message with the line, as lines are counted in parallel and messages of
+// different lines interleave
+func debugLog(lineIdx int, format string, params ...any) {
+	util.DebugLog("Line %d: "+format, append([]any{lineIdx + 1}, params...)...)
+}
+
 func countStarting(str string, run rune) uint {
 	for i, r := range str {
 		if r != run {
@@ -8,11 +8,19 @@ import (
 	"github.com/efulmo/advent-of-code-2023/util"
 )
 
+const (
+	runeAsh  = '.'
+	runeRock = '#'
+)
+
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	patterns := groupPatterns(lines)
+	patterns, err := groupPatterns(lines)
+	util.PanicOnError(err)
 	fmt.Printf("Detected %d patterns\n", len(patterns))
 
 	var pointsSum uint
@@ -23,14 +31,28 @@ func main() {
 	fmt.Println("Pattern points sum:", pointsSum)
 }
 
-func groupPatterns(lines []string) [][]string {
+func groupPatterns(lines []string) ([][]string, error) {
 	var patterns [][]string
 	var pattern []string
 
-	for _, line := range lines {
+	for lineIdx, line := range lines {
 		if len(line) > 0 {
+			if len(pattern) > 0 && len(line) != len(pattern[0]) {
+				return nil, fmt.Errorf("Line %d: Unexpected length %d. Expected %d", lineIdx+1,
+					len(line), len(pattern[0]))
+			}
+			for colIdx, r := range line {
+				if r != runeAsh && r != runeRock {
+					return nil, fmt.Errorf("Unexpected char %q at %d:%d", r, lineIdx+1, colIdx+1)
+				}
+			}
+
 			pattern = append(pattern, line)
 		} else {
+			if len(pattern) == 0 {
+				return nil, fmt.Errorf("Line %d: Empty pattern found", lineIdx+1)
+			}
+
 			patterns = append(patterns, pattern)
 			pattern = make([]string, 0)
 		}
@@ -40,7 +62,7 @@ func groupPatterns(lines []string) [][]string {
 		patterns = append(patterns, pattern)
 	}
 
-	return patterns
+	return patterns, nil
 }
 
 func calculatePatternPoints(pattern []string) uint {
@@ -28,10 +28,13 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	patterns := groupPatterns(lines)
+	patterns, err := groupPatterns(lines)
+	util.PanicOnError(err)
 	fmt.Printf("Detected %d patterns\n", len(patterns))
 
 	var pointsSum uint
@@ -42,14 +45,28 @@ func main() {
 	fmt.Println("Pattern points sum:", pointsSum)
 }
 
-func groupPatterns(lines []string) [][]string {
+func groupPatterns(lines []string) ([][]string, error) {
 	var patterns [][]string
 	var pattern []string
 
-	for _, line := range lines {
+	for lineIdx, line := range lines {
 		if len(line) > 0 {
+			if len(pattern) > 0 && len(line) != len(pattern[0]) {
+				return nil, fmt.Errorf("Line %d: Unexpected length %d. Expected %d", lineIdx+1,
+					len(line), len(pattern[0]))
+			}
+			for colIdx, r := range line {
+				if r != runeAsh && r != runeRock {
+					return nil, fmt.Errorf("Unexpected char %q at %d:%d", r, lineIdx+1, colIdx+1)
+				}
+			}
+
 			pattern = append(pattern, line)
 		} else {
+			if len(pattern) == 0 {
+				return nil, fmt.Errorf("Line %d: Empty pattern found", lineIdx+1)
+			}
+
 			patterns = append(patterns, pattern)
 			pattern = make([]string, 0)
 		}
@@ -59,7 +76,7 @@ func groupPatterns(lines []string) [][]string {
 		patterns = append(patterns, pattern)
 	}
 
-	return patterns
+	return patterns, nil
 }
 
 func calculatePatternPoints(pattern []string) uint {
@@ -81,14 +98,10 @@ func calculatePatternPoints(pattern []string) uint {
 			rowIdx: tSmudge.colIdx,
 			colIdx: tSmudge.rowIdx,
 		}
-		smudgesMap[correctedSmudge] = true
+		smudgesMap.Add(correctedSmudge)
 	}
 
-	var smudges []Coord
-	for smudge := range smudgesMap {
-		smudges = append(smudges, smudge)
-	}
-	slices.SortFunc(smudges, func(c1, c2 Coord) int {
+	smudges := smudgesMap.SortedFunc(func(c1, c2 Coord) int {
 		res := 0
 		if c1.rowIdx != c2.rowIdx {
 			res = int(c1.rowIdx) - int(c2.rowIdx)
@@ -238,10 +251,10 @@ func transposePattern(pattern []string) []string {
 	return newPattern
 }
 
-func findPotentialSmudges(pattern []string) map[Coord]bool {
+func findPotentialSmudges(pattern []string) util.Set[Coord] {
 	rowsTotal := uint(len(pattern))
 
-	smudges := make(map[Coord]bool)
+	smudges := util.NewSet[Coord]()
 	for i := uint(0); i < rowsTotal-1; i++ {
 		for j := i + 1; j < rowsTotal; j++ {
 			colIndexes := findDifferentIndexes(pattern[i], pattern[j])
@@ -250,14 +263,13 @@ func findPotentialSmudges(pattern []string) map[Coord]bool {
 			}
 
 			for _, colIdx := range colIndexes {
-				smudges[Coord{
+				smudges.Add(Coord{
 					rowIdx: i,
 					colIdx: colIdx,
-				}] = true
-				smudges[Coord{
+				}, Coord{
 					rowIdx: j,
 					colIdx: colIdx,
-				}] = true
+				})
 			}
 		}
 	}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParsePlatform(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		parsePlatform(lines)
+	})
+}
+
+func TestFormatBytesGolden(t *testing.T) {
+	platform, err := parsePlatform(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tiltNorth(platform)
+	testutil.AssertGolden(t, "testdata/tilted-north.golden", formatBytes(platform))
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
 )
@@ -14,17 +16,13 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var platform [][]byte
-	for _, line := range lines {
-		byteRow := make([]byte, 0, len(line))
-		for _, r := range line {
-			byteRow = append(byteRow, byte(r))
-		}
-		platform = append(platform, byteRow)
-	}
+	platform, err := parsePlatform(lines)
+	util.PanicOnError(err)
 
 	fmt.Println("Initial platform:")
 	printBytes(platform)
@@ -69,6 +67,31 @@ func main() {
 	fmt.Println("Load:", calculateNorhtBeamLoad(platform))
 }
 
+func parsePlatform(lines []string) ([][]byte, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return nil, errors.New("Platform is empty")
+	}
+
+	var platform [][]byte
+	for lineIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return nil, fmt.Errorf("Line %d: Unexpected length %d. Expected %d", lineIdx+1, len(line),
+				len(lines[0]))
+		}
+
+		byteRow := make([]byte, 0, len(line))
+		for colIdx, r := range line {
+			if r != rune(roundRock) && r != rune(cubeRock) && r != rune(space) {
+				return nil, fmt.Errorf("Unexpected char %q at %d:%d", r, lineIdx+1, colIdx+1)
+			}
+			byteRow = append(byteRow, byte(r))
+		}
+		platform = append(platform, byteRow)
+	}
+
+	return platform, nil
+}
+
 func tiltNorth(bytes [][]byte) {
 	colCount := uint(len(bytes[0]))
 	rowCount := uint(len(bytes))
@@ -170,12 +193,15 @@ func swapBytes(bytes [][]byte, row1, col1, row2, col2 uint) {
 }
 
 func printBytes(bytes [][]byte) {
+	fmt.Println(formatBytes(bytes))
+}
+
+func formatBytes(bytes [][]byte) string {
+	rows := make([]string, 0, len(bytes))
 	for _, row := range bytes {
-		for _, b := range row {
-			fmt.Printf("%c", b)
-		}
-		fmt.Println()
+		rows = append(rows, string(row))
 	}
+	return strings.Join(rows, "\n")
 }
 
 func doTitlCycle(bytes [][]byte) {
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParsePlatform(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		parsePlatform(lines)
+	})
+}
+
+func TestFormatBytesGolden(t *testing.T) {
+	platform, err := parsePlatform(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var renderings []string
+	for cycle := 1; cycle <= 3; cycle++ {
+		doTitlCycle(platform)
+		renderings = append(renderings, fmt.Sprintf("After cycle %d:\n%s", cycle,
+			formatBytes(platform)))
+	}
+	testutil.AssertGolden(t, "testdata/tilt-cycles.golden", strings.Join(renderings, "\n\n"))
+}
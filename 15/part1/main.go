@@ -8,10 +8,13 @@ import (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	instructions := strings.Split(lines[0], ",")
+	instructions, err := parseSteps(lines)
+	util.PanicOnError(err)
 	fmt.Printf("Found %d instructions\n", len(instructions))
 
 	var hashSum uint
@@ -24,6 +27,21 @@ func main() {
 	fmt.Println("Hash sum:", hashSum)
 }
 
+func parseSteps(lines []string) ([]string, error) {
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("Unexpected number of lines: %d", len(lines))
+	}
+
+	steps := strings.Split(lines[0], ",")
+	for stepIdx, step := range steps {
+		if len(step) == 0 {
+			return nil, fmt.Errorf("Step %d is empty", stepIdx+1)
+		}
+	}
+
+	return steps, nil
+}
+
 func computeHash(s string) uint8 {
 	var hash uint
 	for _, r := range s {
@@ -28,6 +28,8 @@ type Lens struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
@@ -36,7 +38,10 @@ func main() {
 
 	boxes := make(map[uint8][]Lens)
 	for instrIdx, instrStr := range instructionsStr {
-		instr := parseInstruction(instrStr)
+		instr, err := parseInstruction(instrStr)
+		if err != nil {
+			panic(fmt.Errorf("Instruction %d: %w", instrIdx+1, err))
+		}
 		util.DebugLog("Parsed instruction: %v\n", instr)
 
 		boxIdx := computeHash(instr.lensLabel)
@@ -79,33 +84,39 @@ func main() {
 	fmt.Println("Total focusing power:", totalFocusingPower)
 }
 
-func parseInstruction(s string) Instruction {
-	var labelRunes []rune
-	for _, r := range s {
-		if unicode.IsLetter(r) {
-			labelRunes = append(labelRunes, r)
-		} else {
-			break
-		}
+func parseInstruction(s string) (Instruction, error) {
+	labelLength := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if labelLength <= 0 {
+		return Instruction{}, fmt.Errorf("Failed to find lens label in %s", s)
 	}
 
-	operation := s[len(labelRunes)]
+	operation := s[labelLength]
 	var lensLength uint8
-	if operation == runeOperationAdd {
-		lensLengthStr := string(s[len(labelRunes)+1])
+	switch operation {
+	case runeOperationAdd:
+		lensLengthStr := s[labelLength+1:]
 
 		parsedLensLength, err := strconv.ParseUint(lensLengthStr, 10, 4)
-		if err != nil {
-			panic(errors.Join(fmt.Errorf("Failed to parse lens length from %s", lensLengthStr)))
+		if err != nil || parsedLensLength == 0 || parsedLensLength > 9 {
+			return Instruction{}, errors.Join(
+				fmt.Errorf("Failed to parse lens length from %s", lensLengthStr), err)
 		}
 		lensLength = uint8(parsedLensLength)
+	case runeOperationRemove:
+		if labelLength+1 != len(s) {
+			return Instruction{}, fmt.Errorf("Unexpected suffix after remove operation in %s", s)
+		}
+	default:
+		return Instruction{}, fmt.Errorf("Unknown operation %c in %s", operation, s)
 	}
 
 	return Instruction{
-		lensLabel:   string(labelRunes),
+		lensLabel:   s[:labelLength],
 		operation:   rune(operation),
 		focalLength: uint8(lensLength),
-	}
+	}, nil
 }
 
 func computeHash(s string) uint8 {
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseInstruction(f *testing.F) {
+	testutil.AddSampleSeeds(f, "../sample.txt")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, instruction := range strings.Split(input, ",") {
+			parseInstruction(instruction)
+		}
+	})
+}
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"slices"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -22,31 +22,94 @@ const (
 	directionDown  = 2
 	directionLeft  = 3
 	directionUp    = 4
+
+	directionsTotal = 4
 )
 
 type Coord struct {
 	rowIdx, colIdx int
 }
 
+// VisitedTiles keeps tiles the beam has passed and directions it has passed them in
+type VisitedTiles struct {
+	columnsTotal int
+	// directions has a bit per every direction of every tile
+	directions util.Bitset
+	tiles      util.Bitset
+}
+
+func newVisitedTiles(columnsTotal uint) *VisitedTiles {
+	return &VisitedTiles{columnsTotal: int(columnsTotal)}
+}
+
+// visit marks the tile visited in the direction. It returns false if the tile was already visited
+// in the direction.
+func (v *VisitedTiles) visit(coord Coord, direction uint8) bool {
+	tileIdx := coord.rowIdx*v.columnsTotal + coord.colIdx
+	directionIdx := tileIdx*directionsTotal + int(direction) - 1
+	if v.directions.Test(directionIdx) {
+		return false
+	}
+
+	v.directions.Set(directionIdx)
+	v.tiles.Set(tileIdx)
+	return true
+}
+
+func (v *VisitedTiles) contains(coord Coord) bool {
+	return v.tiles.Test(coord.rowIdx*v.columnsTotal + coord.colIdx)
+}
+
+func (v *VisitedTiles) count() uint {
+	return uint(v.tiles.Count())
+}
+
 func main() {
+	defer util.ReportAllocs()
+
 	contraption, err := util.ReadInputFile()
 	util.PanicOnError(err)
+	util.PanicOnError(validateContraption(contraption))
 
 	rowsTotal := uint(len(contraption))
 	columnsTotal := uint(len(contraption[0]))
 	fmt.Printf("A contraption %dx%d is read\n", rowsTotal, columnsTotal)
 
-	visitedTiles := make(map[Coord][]uint8)
+	visitedTiles := newVisitedTiles(columnsTotal)
 
 	simulateBeam(contraption, visitedTiles, Coord{0, 0}, directionRight)
 	fmt.Println("Energized tiles after simulation:")
 	fmt.Println(formatVisitedTiles(rowsTotal, columnsTotal, visitedTiles))
-	fmt.Println("Count of visited tiles:", len(visitedTiles))
+	fmt.Println("Count of visited tiles:", visitedTiles.count())
+}
+
+func validateContraption(contraption []string) error {
+	if len(contraption) == 0 || len(contraption[0]) == 0 {
+		return errors.New("Contraption is empty")
+	}
+
+	for rowIdx, row := range contraption {
+		if len(row) != len(contraption[0]) {
+			return fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1, len(row),
+				len(contraption[0]))
+		}
+
+		for colIdx, r := range row {
+			switch r {
+			case runeEmpty, runeMirrorForward, runeMirrorBackward, runeSplitterHorizontal,
+				runeSplitterVertical:
+			default:
+				return fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1, colIdx+1)
+			}
+		}
+	}
+
+	return nil
 }
 
 func simulateBeam(
 	contraction []string,
-	visitedTiles map[Coord][]uint8,
+	visitedTiles *VisitedTiles,
 	startCoord Coord,
 	startDirection uint8,
 ) {
@@ -57,13 +120,10 @@ simulationLoop:
 	for {
 		rowIdx, colIdx := coord.rowIdx, coord.colIdx
 
-		visitedFromDirections := visitedTiles[coord]
-		if slices.Contains(visitedFromDirections, direction) {
+		if !visitedTiles.visit(coord, direction) {
 			util.DebugLog("Tile %d:%d was already visited from direction %d. Stopping beam simulation\n",
 				rowIdx+1, colIdx+1, direction)
 			break simulationLoop
-		} else {
-			visitedTiles[coord] = append(visitedFromDirections, direction)
 		}
 
 		run := rune(contraction[rowIdx][colIdx])
@@ -193,13 +253,12 @@ func reflectBeam(mirror rune, direction uint8) uint8 {
 	panic(fmt.Errorf("Unexpected mirror %c or direction %d", mirror, direction))
 }
 
-func formatVisitedTiles(rowsTotal, columnsTotal uint, tiles map[Coord][]uint8) string {
+func formatVisitedTiles(rowsTotal, columnsTotal uint, tiles *VisitedTiles) string {
 	var rows []string
 	for rowIdx := uint(0); rowIdx < rowsTotal; rowIdx++ {
 		var rowBld strings.Builder
 		for colIdx := uint(0); colIdx < columnsTotal; colIdx++ {
-			_, wasTileVisited := tiles[Coord{int(rowIdx), int(colIdx)}]
-			if wasTileVisited {
+			if tiles.contains(Coord{int(rowIdx), int(colIdx)}) {
 				rowBld.WriteRune(runeTileEnergized)
 			} else {
 				rowBld.WriteRune(runeTileRegular)
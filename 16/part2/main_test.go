@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzValidateContraption(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		validateContraption(lines)
+	})
+}
+
+// the beam entering the 4th column from the top energizes the most tiles of the sample
+func TestFormatVisitedTilesGolden(t *testing.T) {
+	contraption := testutil.ReadSampleLines(t, "../sample.txt")
+	if err := validateContraption(contraption); err != nil {
+		t.Fatal(err)
+	}
+
+	visitedTiles := newVisitedTiles(uint(len(contraption[0])))
+	simulateBeam(contraption, visitedTiles, Coord{0, 3}, directionDown)
+	testutil.AssertGolden(t, "testdata/visited-tiles.golden", formatVisitedTiles(
+		uint(len(contraption)), uint(len(contraption[0])), visitedTiles))
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
+	"math"
 	"slices"
+	"strconv"
 
 	"github.com/efulmo/advent-of-code-2023/util"
 )
@@ -37,23 +40,17 @@ type NodeInfo struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	heatLossByCoord, err := parseHeatLossMap(lines)
+	util.PanicOnError(err)
+
 	rowsTotal := uint8(len(lines))
 	colsTotal := uint8(len(lines[0]))
 
-	heatLossByCoord := make(map[Coord]uint8, rowsTotal*colsTotal)
-	for rowIdx, row := range lines {
-		for colIdx := range row {
-			coord := Coord{
-				rowIdx: uint8(rowIdx),
-				colIdx: uint8(colIdx),
-			}
-			heatLossByCoord[coord] = uint8(util.ParseUintOrPanic(row[colIdx : colIdx+1]))
-		}
-	}
-
 	startNode := Node{
 		coord:                Coord{0, 0},
 		inDirection:          directionNone,
@@ -65,7 +62,7 @@ func main() {
 		prevNode:      startNode,
 	}
 
-	analyzedNodes := make(map[Node]bool)
+	analyzedNodes := util.NewSet[Node]()
 
 	nodesToAnalyze := []Node{startNode}
 	nodeComparator := byTotalHeatLossComparator(nodeInfos)
@@ -119,7 +116,7 @@ func main() {
 			}
 		}
 
-		analyzedNodes[currentNode] = true
+		analyzedNodes.Add(currentNode)
 	}
 
 	finishCoord := Coord{
@@ -157,6 +154,38 @@ func main() {
 	}
 }
 
+func parseHeatLossMap(lines []string) (map[Coord]uint8, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return nil, errors.New("Heat loss map is empty")
+	}
+	if len(lines) > math.MaxUint8 || len(lines[0]) > math.MaxUint8 {
+		return nil, fmt.Errorf("Heat loss map %dx%d is too big", len(lines), len(lines[0]))
+	}
+
+	heatLossByCoord := make(map[Coord]uint8, len(lines)*len(lines[0]))
+	for rowIdx, row := range lines {
+		if len(row) != len(lines[0]) {
+			return nil, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1, len(row),
+				len(lines[0]))
+		}
+
+		for colIdx := range row {
+			heatLoss, err := strconv.ParseUint(row[colIdx:colIdx+1], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse heat loss at %d:%d: %w", rowIdx+1, colIdx+1, err)
+			}
+
+			coord := Coord{
+				rowIdx: uint8(rowIdx),
+				colIdx: uint8(colIdx),
+			}
+			heatLossByCoord[coord] = uint8(heatLoss)
+		}
+	}
+
+	return heatLossByCoord, nil
+}
+
 func byTotalHeatLossComparator(nodeInfos map[Node]NodeInfo) func(Node, Node) int {
 	return func(n1, n2 Node) int {
 		info1, found1 := nodeInfos[n1]
@@ -184,7 +213,7 @@ func insertSortingAwareIfAbsent(nodes []Node, nodeToInsert Node, comparator func
 	return nodes
 }
 
-func getNeighbourNodes(node Node, analyzedNodes map[Node]bool, rowsTotal, colsTotal uint8) []Node {
+func getNeighbourNodes(node Node, analyzedNodes util.Set[Node], rowsTotal, colsTotal uint8) []Node {
 	var allowedNextMoveDirections []string
 
 	if node.inDirection != directionNone && node.stepsMadeInDirection < minStepsInSameDirection {
@@ -244,7 +273,7 @@ func getNeighbourNodes(node Node, analyzedNodes map[Node]bool, rowsTotal, colsTo
 				inDirection:          direction,
 				stepsMadeInDirection: stepsMadeInThatDirection + 1,
 			}
-			if !analyzedNodes[newNode] {
+			if !analyzedNodes.Contains(newNode) {
 				neighboursNodes = append(neighboursNodes, newNode)
 			}
 		}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseHeatLossMap(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt", "sample*.txt"},
+		func(t *testing.T, lines []string) {
+			parseHeatLossMap(lines)
+		})
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/grid"
 )
 
 const (
@@ -19,9 +21,37 @@ type Coord struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	coords, perimiter, err := digTrench(lines)
+	util.PanicOnError(err)
+
+	fmt.Println("Trench:")
+	fmt.Println(formatTrench(coords))
+
+	coordsLen := uint(len(coords))
+	var sum int
+	for i := uint(0); i < coordsLen-1; i++ {
+		coord, nextCoord := coords[i], coords[i+1]
+
+		toAdd := coord.x * nextCoord.y
+		toSubtract := nextCoord.x * coord.y
+		sum += toAdd - toSubtract
+
+		// fmt.Printf("calculations: add %d, subtract %d\n", toAdd, toSubtract)
+	}
+
+	internalArea := abs(sum) / 2
+	edgeArea := int(perimiter)/2 + 1
+	fmt.Println("Area:", internalArea+edgeArea)
+}
+
+// digTrench returns coords of the trench corners starting and ending at {0, 0} and the trench
+// length. Y axis points up.
+func digTrench(lines []string) ([]Coord, uint, error) {
 	startCoord := Coord{
 		x: 0,
 		y: 0,
@@ -29,10 +59,11 @@ func main() {
 	coords := []Coord{startCoord}
 	var perimiter uint
 
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		direction, lengthStr := fields[0], fields[1]
-		length := util.ParseUintOrPanic(lengthStr)
+	for lineIdx, line := range lines {
+		direction, length, err := parseDigStep(line)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
 
 		prevCoord := coords[len(coords)-1]
 		var newCoord Coord
@@ -59,7 +90,7 @@ func main() {
 				y: prevCoord.y,
 			}
 		default:
-			panic(fmt.Errorf("Unknown direction: %s", direction))
+			return nil, 0, fmt.Errorf("Line %d: Unknown direction: %s", lineIdx+1, direction)
 		}
 
 		coords = append(coords, newCoord)
@@ -69,25 +100,47 @@ func main() {
 		// 	newCoord.x, newCoord.y)
 	}
 
-	coordsLen := uint(len(coords))
-	if coords[0] != coords[coordsLen-1] {
-		panic(fmt.Errorf("Lava pool isn't closed"))
+	if coords[0] != coords[len(coords)-1] {
+		return nil, 0, errors.New("Lava pool isn't closed")
 	}
 
-	var sum int
-	for i := uint(0); i < coordsLen-1; i++ {
-		coord, nextCoord := coords[i], coords[i+1]
+	return coords, perimiter, nil
+}
 
-		toAdd := coord.x * nextCoord.y
-		toSubtract := nextCoord.x * coord.y
-		sum += toAdd - toSubtract
+// formatTrench draws the trench with '#' on ground drawn with '.'. Top row has the biggest Y.
+func formatTrench(coords []Coord) string {
+	trench := grid.NewSparse[byte]('.')
+	for i := 1; i < len(coords); i++ {
+		from, to := coords[i-1], coords[i]
+		for x := min(from.x, to.x); x <= max(from.x, to.x); x++ {
+			for y := min(from.y, to.y); y <= max(from.y, to.y); y++ {
+				// rows grow down, so they go against Y
+				trench.Set(grid.Point{Row: -y, Col: x}, '#')
+			}
+		}
+	}
+	return grid.Format(trench)
+}
 
-		// fmt.Printf("calculations: add %d, subtract %d\n", toAdd, toSubtract)
+func parseDigStep(line string) (string, uint, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return "", 0, fmt.Errorf("Unexpected number of space-delimited groups: %d", len(fields))
 	}
 
-	internalArea := abs(sum) / 2
-	edgeArea := int(perimiter)/2 + 1
-	fmt.Println("Area:", internalArea+edgeArea)
+	direction, lengthStr := fields[0], fields[1]
+	switch direction {
+	case directionUp, directionDown, directionLeft, directionRight:
+	default:
+		return "", 0, fmt.Errorf("Unknown direction: %s", direction)
+	}
+
+	length, err := util.ParseUint(lengthStr)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return direction, length, nil
 }
 
 func abs(i int) int {
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseDigStep(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			parseDigStep(line)
+		})
+}
+
+func TestFormatTrenchGolden(t *testing.T) {
+	coords, _, err := digTrench(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertGolden(t, "../sample-render.txt", formatTrench(coords))
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/compress"
 )
 
 const (
@@ -20,34 +23,29 @@ type Coord struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	coords, err := digTrench(lines)
+	util.PanicOnError(err)
+
+	fmt.Println("Area:", lagoonArea(coords))
+}
+
+// digTrench returns coords of the trench corners starting and ending at {0, 0}. Y axis points up.
+func digTrench(lines []string) ([]Coord, error) {
 	startCoord := Coord{
 		x: 0,
 		y: 0,
 	}
 	coords := []Coord{startCoord}
-	var perimiter uint64
 
 	for lineIdx, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) != 3 {
-			panic(fmt.Errorf("Unexpected number of space-delimited groups in line %d: %d",
-				lineIdx+1, len(fields)))
-		}
-
-		colorCode := fields[2]
-		hexStr := strings.Trim(colorCode, "#()")
-		if len(hexStr) != 6 {
-			panic(fmt.Errorf("Unexpected length of the color code in line %d: %d", lineIdx,
-				len(hexStr)))
-		}
-
-		lengthHex, direction := hexStr[:5], hexStr[5:]
-		length, err := strconv.ParseUint(lengthHex, 16, 64)
+		direction, length, err := parseDigStep(line)
 		if err != nil {
-			panic(fmt.Errorf("Unable to parse %s as hex uint", lengthHex))
+			return nil, fmt.Errorf("Line %d: %w", lineIdx+1, err)
 		}
 
 		prevCoord := coords[len(coords)-1]
@@ -75,40 +73,112 @@ func main() {
 				y: prevCoord.y,
 			}
 		default:
-			panic(fmt.Errorf("Unknown direction: %s", direction))
+			return nil, fmt.Errorf("Line %d: Unknown direction: %s", lineIdx+1, direction)
 		}
 
 		coords = append(coords, newCoord)
-		perimiter += length
 
 		// fmt.Printf("%s %d: %d:%d -> %d:%d\n", direction, length, prevCoord.x, prevCoord.y,
 		// 	newCoord.x, newCoord.y)
 	}
 
-	coordsLen := uint(len(coords))
-	if coords[0] != coords[coordsLen-1] {
-		panic(fmt.Errorf("Lava pool isn't closed"))
+	if coords[0] != coords[len(coords)-1] {
+		return nil, errors.New("Lava pool isn't closed")
 	}
 
-	var sum int
-	for i := uint(0); i < coordsLen-1; i++ {
-		coord, nextCoord := coords[i], coords[i+1]
+	return coords, nil
+}
 
-		toAdd := coord.x * nextCoord.y
-		toSubtract := nextCoord.x * coord.y
-		sum += toAdd - toSubtract
+// lagoonArea digs the trench on the grid compressed around its corners, so there are about as
+// many cells as corners squared, fills the ground outside of the trench and sums areas of the rest
+func lagoonArea(coords []Coord) int {
+	xs := make([]int, 0, len(coords)+2)
+	ys := make([]int, 0, len(coords)+2)
+	for _, coord := range coords {
+		xs = append(xs, coord.x)
+		ys = append(ys, coord.y)
+	}
+	// a frame of ground around the trench lets the fill get everywhere outside from a corner
+	xs = append(xs, slices.Min(xs)-1, slices.Max(xs)+1)
+	ys = append(ys, slices.Min(ys)-1, slices.Max(ys)+1)
+
+	grid := compress.NewGrid(xs, ys)
+	cols, rows := grid.Size()
+	cellIdx := func(col, row int) int {
+		return row*cols + col
+	}
+
+	trench := util.NewBitset(cols * rows)
+	for i := 1; i < len(coords); i++ {
+		fromCol, fromRow, _ := grid.Index(coords[i-1].x, coords[i-1].y)
+		toCol, toRow, _ := grid.Index(coords[i].x, coords[i].y)
+		for col := min(fromCol, toCol); col <= max(fromCol, toCol); col++ {
+			for row := min(fromRow, toRow); row <= max(fromRow, toRow); row++ {
+				trench.Set(cellIdx(col, row))
+			}
+		}
+	}
 
-		// fmt.Printf("calculations: add %d, subtract %d\n", toAdd, toSubtract)
+	outside := util.NewBitset(cols * rows)
+	outside.Set(cellIdx(0, 0))
+	queue := []Coord{{0, 0}}
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+
+		neighbours := []Coord{
+			{cell.x - 1, cell.y},
+			{cell.x + 1, cell.y},
+			{cell.x, cell.y - 1},
+			{cell.x, cell.y + 1},
+		}
+		for _, n := range neighbours {
+			if n.x < 0 || n.x >= cols || n.y < 0 || n.y >= rows {
+				continue
+			}
+			idx := cellIdx(n.x, n.y)
+			if trench.Test(idx) || outside.Test(idx) {
+				continue
+			}
+			outside.Set(idx)
+			queue = append(queue, n)
+		}
 	}
 
-	internalArea := abs(sum) / 2
-	edgeArea := int(perimiter)/2 + 1
-	fmt.Println("Area:", internalArea+edgeArea)
+	area := 0
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if !outside.Test(cellIdx(col, row)) {
+				area += grid.Area(col, row)
+			}
+		}
+	}
+	return area
 }
 
-func abs(i int) int {
-	if i >= 0 {
-		return i
+func parseDigStep(line string) (string, uint64, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return "", 0, fmt.Errorf("Unexpected number of space-delimited groups: %d", len(fields))
 	}
-	return -i
+
+	colorCode := fields[2]
+	hexStr := strings.Trim(colorCode, "#()")
+	if len(hexStr) != 6 {
+		return "", 0, fmt.Errorf("Unexpected length of the color code: %d", len(hexStr))
+	}
+
+	lengthHex, direction := hexStr[:5], hexStr[5:]
+	length, err := strconv.ParseUint(lengthHex, 16, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("Unable to parse %s as hex uint", lengthHex)
+	}
+
+	switch direction {
+	case directionUp, directionDown, directionLeft, directionRight:
+	default:
+		return "", 0, fmt.Errorf("Unknown direction: %s", direction)
+	}
+
+	return direction, length, nil
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseDigStep(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			parseDigStep(line)
+		})
+}
+
+func TestLagoonArea(t *testing.T) {
+	coords, err := digTrench(testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if area := lagoonArea(coords); area != 952408144115 {
+		t.Errorf("Unexpected area %d", area)
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -16,6 +18,9 @@ const (
 
 	operatorMore = ">"
 	operatorLess = "<"
+
+	startWorkflowName = "in"
+	categories        = "xmas"
 )
 
 type Rule struct {
@@ -29,9 +34,40 @@ type Rule struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	workflows, partsLineIdx, err := parseWorkflows(lines)
+	util.PanicOnError(err)
+
+	fmt.Printf("%d workflows are parsed:\n", len(workflows))
+	for _, w := range workflows {
+		fmt.Println(w)
+	}
+
+	parts, err := parseParts(lines[partsLineIdx:])
+	util.PanicOnError(err)
+
+	fmt.Printf("%d parts are parsed:\n", len(parts))
+	for _, p := range parts {
+		fmt.Println(p)
+	}
+
+	var acceptedPartsSum uint
+	for _, part := range parts {
+		decision := analyzePart(part, startWorkflowName, workflows)
+		if decision == decisionAccept {
+			for _, catVal := range part {
+				acceptedPartsSum += uint(catVal)
+			}
+		}
+	}
+	fmt.Println("Accepted parts sum:", acceptedPartsSum)
+}
+
+func parseWorkflows(lines []string) (map[string][]Rule, uint, error) {
 	linesCount := uint(len(lines))
 	var lineIdx uint
 	workflows := make(map[string][]Rule)
@@ -42,70 +78,161 @@ func main() {
 			break
 		}
 
-		ruleParts := strings.Split(line, "{")
-		name := ruleParts[0]
-
-		rulesStr := strings.TrimRight(ruleParts[1], "}")
-		rulesStrSl := strings.Split(rulesStr, ",")
-
-		rules := make([]Rule, 0, 2)
-		for _, rule := range rulesStrSl {
-			if strings.ContainsAny(rule, "<>:") {
-				category, operator, valueAndNextWorkflow := string(rule[0]), string(rule[1]), rule[2:]
-				valueParts := strings.Split(valueAndNextWorkflow, ":")
-
-				rules = append(rules, Rule{
-					kind:             kindCondition,
-					category:         category,
-					operator:         operator,
-					value:            uint16(util.ParseUintOrPanic(valueParts[0])),
-					nextWorkflowName: valueParts[1],
-				})
-
-			} else {
-				rules = append(rules, Rule{
-					kind:             kindRedirect,
-					nextWorkflowName: rule,
-				})
-			}
+		name, rules, err := parseWorkflow(line)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
+		if _, found := workflows[name]; found {
+			return nil, 0, fmt.Errorf("Line %d: Duplicate workflow %s", lineIdx+1, name)
 		}
 
 		workflows[name] = rules
 	}
 
-	fmt.Printf("%d workflows are parsed:\n", len(workflows))
-	for _, w := range workflows {
-		fmt.Println(w)
+	if _, found := workflows[startWorkflowName]; !found {
+		return nil, 0, fmt.Errorf("Start workflow %s isn't found", startWorkflowName)
 	}
 
-	var parts []map[string]uint16
-	for lineIdx++; lineIdx < linesCount; lineIdx++ {
-		line := strings.Trim(lines[lineIdx], "{}")
-		categoryStrs := strings.Split(line, ",")
+	for name, rules := range workflows {
+		for _, rule := range rules {
+			nextName := rule.nextWorkflowName
+			if _, found := workflows[nextName]; !found && nextName != decisionAccept &&
+				nextName != decisionReject {
+				return nil, 0, fmt.Errorf("Workflow %s refers to unknown workflow %s", name, nextName)
+			}
+		}
+	}
 
-		part := make(map[string]uint16, 4)
-		for _, catStr := range categoryStrs {
-			name, valueStr := catStr[:1], catStr[2:]
-			part[name] = uint16(util.ParseUintOrPanic(valueStr))
+	if err := checkWorkflowCycles(startWorkflowName, workflows, util.NewSet[string](),
+		util.NewSet[string]()); err != nil {
+		return nil, 0, err
+	}
+
+	// skip the empty line separating workflows from parts
+	return workflows, min(lineIdx+1, linesCount), nil
+}
+
+func parseWorkflow(line string) (string, []Rule, error) {
+	name, rulesStr, found := strings.Cut(line, "{")
+	if !found || len(name) == 0 || !strings.HasSuffix(rulesStr, "}") {
+		return "", nil, fmt.Errorf("Unexpected workflow format: %s", line)
+	}
+	rulesStr = strings.TrimSuffix(rulesStr, "}")
+	rulesStrSl := strings.Split(rulesStr, ",")
+
+	rules := make([]Rule, 0, 2)
+	for ruleIdx, rule := range rulesStrSl {
+		isLastRule := ruleIdx == len(rulesStrSl)-1
+
+		if strings.ContainsAny(rule, "<>:") {
+			if isLastRule {
+				return "", nil, fmt.Errorf("Workflow %s doesn't end with redirect rule", name)
+			}
+			if len(rule) < 2 {
+				return "", nil, fmt.Errorf("Unexpected rule format: %s", rule)
+			}
+
+			category, operator, valueAndNextWorkflow := string(rule[0]), string(rule[1]), rule[2:]
+			if !strings.Contains(categories, category) {
+				return "", nil, fmt.Errorf("Unknown category %s in rule %s", category, rule)
+			}
+			if operator != operatorMore && operator != operatorLess {
+				return "", nil, fmt.Errorf("Unexpected operator <%s> in rule %s", operator, rule)
+			}
+
+			valueParts := strings.Split(valueAndNextWorkflow, ":")
+			if len(valueParts) != 2 || len(valueParts[1]) == 0 {
+				return "", nil, fmt.Errorf("Unexpected rule format: %s", rule)
+			}
+			value, err := strconv.ParseUint(valueParts[0], 10, 16)
+			if err != nil {
+				return "", nil, errors.Join(fmt.Errorf("Failed to parse value of rule %s", rule), err)
+			}
+
+			rules = append(rules, Rule{
+				kind:             kindCondition,
+				category:         category,
+				operator:         operator,
+				value:            uint16(value),
+				nextWorkflowName: valueParts[1],
+			})
+
+		} else {
+			if !isLastRule || len(rule) == 0 {
+				return "", nil, fmt.Errorf("Unexpected redirect rule <%s> in workflow %s", rule, name)
+			}
+
+			rules = append(rules, Rule{
+				kind:             kindRedirect,
+				nextWorkflowName: rule,
+			})
 		}
-		parts = append(parts, part)
 	}
 
-	fmt.Printf("%d parts are parsed:\n", len(parts))
-	for _, p := range parts {
-		fmt.Println(p)
+	return name, rules, nil
+}
+
+// checkWorkflowCycles walks workflows reachable from the named one. path has workflows on the way
+// to the current one, so reaching one of them again is a cycle. done has workflows whose
+// successors are all checked, so workflows reachable by several ways are walked once.
+func checkWorkflowCycles(
+	workflowName string,
+	workflows map[string][]Rule,
+	path, done util.Set[string],
+) error {
+	if workflowName == decisionAccept || workflowName == decisionReject ||
+		done.Contains(workflowName) {
+		return nil
+	}
+	if path.Contains(workflowName) {
+		return fmt.Errorf("Workflow %s is a part of a cycle", workflowName)
 	}
 
-	var acceptedPartsSum uint
-	for _, part := range parts {
-		decision := analyzePart(part, "in", workflows)
-		if decision == decisionAccept {
-			for _, catVal := range part {
-				acceptedPartsSum += uint(catVal)
+	path.Add(workflowName)
+	for _, rule := range workflows[workflowName] {
+		if err := checkWorkflowCycles(rule.nextWorkflowName, workflows, path, done); err != nil {
+			return err
+		}
+	}
+	path.Remove(workflowName)
+	done.Add(workflowName)
+
+	return nil
+}
+
+func parseParts(lines []string) ([]map[string]uint16, error) {
+	var parts []map[string]uint16
+	for lineIdx, line := range lines {
+		if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+			return nil, fmt.Errorf("Part %d: Unexpected format: %s", lineIdx+1, line)
+		}
+		categoryStrs := strings.Split(strings.Trim(line, "{}"), ",")
+
+		part := make(map[string]uint16, 4)
+		for _, catStr := range categoryStrs {
+			name, valueStr, found := strings.Cut(catStr, "=")
+			if !found || !strings.Contains(categories, name) || len(name) != 1 {
+				return nil, fmt.Errorf("Part %d: Unexpected category format: %s", lineIdx+1, catStr)
+			}
+			if _, found := part[name]; found {
+				return nil, fmt.Errorf("Part %d: Duplicate category %s", lineIdx+1, name)
+			}
+
+			value, err := strconv.ParseUint(valueStr, 10, 16)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("Part %d: Failed to parse category value %s",
+					lineIdx+1, valueStr), err)
 			}
+			part[name] = uint16(value)
 		}
+		if len(part) != len(categories) {
+			return nil, fmt.Errorf("Part %d: Unexpected number of categories: %d", lineIdx+1, len(part))
+		}
+
+		parts = append(parts, part)
 	}
-	fmt.Println("Accepted parts sum:", acceptedPartsSum)
+
+	return parts, nil
 }
 
 func analyzePart(part map[string]uint16, workflowName string, workflows map[string][]Rule) string {
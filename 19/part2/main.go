@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"maps"
+	"strconv"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -17,6 +19,9 @@ const (
 
 	operatorMore = ">"
 	operatorLess = "<"
+
+	startWorkflowName = "in"
+	categories        = "xmas"
 )
 
 type Rule struct {
@@ -33,49 +38,13 @@ type Range struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	linesCount := uint(len(lines))
-	var lineIdx uint
-	workflows := make(map[string][]Rule)
-
-	for lineIdx = uint(0); lineIdx < linesCount; lineIdx++ {
-		line := lines[lineIdx]
-		if len(line) == 0 {
-			break
-		}
-
-		ruleParts := strings.Split(line, "{")
-		name := ruleParts[0]
-
-		rulesStr := strings.TrimRight(ruleParts[1], "}")
-		rulesStrSl := strings.Split(rulesStr, ",")
-
-		rules := make([]Rule, 0, 2)
-		for _, rule := range rulesStrSl {
-			if strings.ContainsAny(rule, "<>:") {
-				category, operator, valueAndNextWorkflow := string(rule[0]), string(rule[1]), rule[2:]
-				valueParts := strings.Split(valueAndNextWorkflow, ":")
-
-				rules = append(rules, Rule{
-					kind:             kindCondition,
-					category:         category,
-					operator:         operator,
-					value:            uint16(util.ParseUintOrPanic(valueParts[0])),
-					nextWorkflowName: valueParts[1],
-				})
-
-			} else {
-				rules = append(rules, Rule{
-					kind:             kindRedirect,
-					nextWorkflowName: rule,
-				})
-			}
-		}
-
-		workflows[name] = rules
-	}
+	workflows, _, err := parseWorkflows(lines)
+	util.PanicOnError(err)
 
 	fmt.Printf("%d workflows are parsed\n", len(workflows))
 
@@ -86,7 +55,7 @@ func main() {
 		"x": {moreThan: 0, lessThan: 4001},
 	}
 
-	acceptedCombos := getAcceptedCombos("in", workflows, categoryCombination)
+	acceptedCombos := getAcceptedCombos(startWorkflowName, workflows, categoryCombination)
 	acceptedCombosLen := uint(len(acceptedCombos))
 	fmt.Printf("%d derived combos found\n", acceptedCombosLen)
 
@@ -117,6 +86,139 @@ func main() {
 	fmt.Println("Total combo count:", totalCombos)
 }
 
+func parseWorkflows(lines []string) (map[string][]Rule, uint, error) {
+	linesCount := uint(len(lines))
+	var lineIdx uint
+	workflows := make(map[string][]Rule)
+
+	for lineIdx = uint(0); lineIdx < linesCount; lineIdx++ {
+		line := lines[lineIdx]
+		if len(line) == 0 {
+			break
+		}
+
+		name, rules, err := parseWorkflow(line)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+		}
+		if _, found := workflows[name]; found {
+			return nil, 0, fmt.Errorf("Line %d: Duplicate workflow %s", lineIdx+1, name)
+		}
+
+		workflows[name] = rules
+	}
+
+	if _, found := workflows[startWorkflowName]; !found {
+		return nil, 0, fmt.Errorf("Start workflow %s isn't found", startWorkflowName)
+	}
+
+	for name, rules := range workflows {
+		for _, rule := range rules {
+			nextName := rule.nextWorkflowName
+			if _, found := workflows[nextName]; !found && nextName != decisionAccept &&
+				nextName != decisionReject {
+				return nil, 0, fmt.Errorf("Workflow %s refers to unknown workflow %s", name, nextName)
+			}
+		}
+	}
+
+	if err := checkWorkflowCycles(startWorkflowName, workflows, util.NewSet[string](),
+		util.NewSet[string]()); err != nil {
+		return nil, 0, err
+	}
+
+	// skip the empty line separating workflows from parts
+	return workflows, min(lineIdx+1, linesCount), nil
+}
+
+func parseWorkflow(line string) (string, []Rule, error) {
+	name, rulesStr, found := strings.Cut(line, "{")
+	if !found || len(name) == 0 || !strings.HasSuffix(rulesStr, "}") {
+		return "", nil, fmt.Errorf("Unexpected workflow format: %s", line)
+	}
+	rulesStr = strings.TrimSuffix(rulesStr, "}")
+	rulesStrSl := strings.Split(rulesStr, ",")
+
+	rules := make([]Rule, 0, 2)
+	for ruleIdx, rule := range rulesStrSl {
+		isLastRule := ruleIdx == len(rulesStrSl)-1
+
+		if strings.ContainsAny(rule, "<>:") {
+			if isLastRule {
+				return "", nil, fmt.Errorf("Workflow %s doesn't end with redirect rule", name)
+			}
+			if len(rule) < 2 {
+				return "", nil, fmt.Errorf("Unexpected rule format: %s", rule)
+			}
+
+			category, operator, valueAndNextWorkflow := string(rule[0]), string(rule[1]), rule[2:]
+			if !strings.Contains(categories, category) {
+				return "", nil, fmt.Errorf("Unknown category %s in rule %s", category, rule)
+			}
+			if operator != operatorMore && operator != operatorLess {
+				return "", nil, fmt.Errorf("Unexpected operator <%s> in rule %s", operator, rule)
+			}
+
+			valueParts := strings.Split(valueAndNextWorkflow, ":")
+			if len(valueParts) != 2 || len(valueParts[1]) == 0 {
+				return "", nil, fmt.Errorf("Unexpected rule format: %s", rule)
+			}
+			value, err := strconv.ParseUint(valueParts[0], 10, 16)
+			if err != nil {
+				return "", nil, errors.Join(fmt.Errorf("Failed to parse value of rule %s", rule), err)
+			}
+
+			rules = append(rules, Rule{
+				kind:             kindCondition,
+				category:         category,
+				operator:         operator,
+				value:            uint16(value),
+				nextWorkflowName: valueParts[1],
+			})
+
+		} else {
+			if !isLastRule || len(rule) == 0 {
+				return "", nil, fmt.Errorf("Unexpected redirect rule <%s> in workflow %s", rule, name)
+			}
+
+			rules = append(rules, Rule{
+				kind:             kindRedirect,
+				nextWorkflowName: rule,
+			})
+		}
+	}
+
+	return name, rules, nil
+}
+
+// checkWorkflowCycles walks workflows reachable from the named one. path has workflows on the way
+// to the current one, so reaching one of them again is a cycle. done has workflows whose
+// successors are all checked, so workflows reachable by several ways are walked once.
+func checkWorkflowCycles(
+	workflowName string,
+	workflows map[string][]Rule,
+	path, done util.Set[string],
+) error {
+	if workflowName == decisionAccept || workflowName == decisionReject ||
+		done.Contains(workflowName) {
+		return nil
+	}
+	if path.Contains(workflowName) {
+		return fmt.Errorf("Workflow %s is a part of a cycle", workflowName)
+	}
+
+	path.Add(workflowName)
+	for _, rule := range workflows[workflowName] {
+		if err := checkWorkflowCycles(rule.nextWorkflowName, workflows, path, done); err != nil {
+			return err
+		}
+	}
+	path.Remove(workflowName)
+	done.Add(workflowName)
+
+	return nil
+}
+
 func getAcceptedCombos(
 	workflowName string,
 	workflows map[string][]Rule,
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseWorkflows(f *testing.F) {
+	testutil.FuzzLines(f, []string{"../sample.txt"}, func(t *testing.T, lines []string) {
+		workflows, _, err := parseWorkflows(lines)
+		if err != nil {
+			return
+		}
+
+		for name, rules := range workflows {
+			// a workflow ends with the only redirect rule and refers to known workflows
+			for ruleIdx, rule := range rules {
+				if (rule.kind == kindRedirect) != (ruleIdx == len(rules)-1) {
+					t.Fatalf("Workflow %s has rule %d of %d of kind %d", name, ruleIdx+1,
+						len(rules), rule.kind)
+				}
+				if _, found := workflows[rule.nextWorkflowName]; !found &&
+					rule.nextWorkflowName != decisionAccept &&
+					rule.nextWorkflowName != decisionReject {
+					t.Fatalf("Workflow %s refers to unknown workflow %s", name,
+						rule.nextWorkflowName)
+				}
+			}
+
+			// a parsed workflow is the same when written back in the input format
+			formatted := formatWorkflow(name, rules)
+			reparsedName, reparsedRules, err := parseWorkflow(formatted)
+			if err != nil || reparsedName != name || !slices.Equal(reparsedRules, rules) {
+				t.Errorf("Workflow %s is parsed as %v, but %q as %s %v, %v", name, rules,
+					formatted, reparsedName, reparsedRules, err)
+			}
+		}
+	})
+}
+
+func formatWorkflow(name string, rules []Rule) string {
+	ruleStrs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if rule.kind == kindRedirect {
+			ruleStrs = append(ruleStrs, rule.nextWorkflowName)
+		} else {
+			ruleStrs = append(ruleStrs, fmt.Sprintf("%s%s%d:%s", rule.category, rule.operator,
+				rule.value, rule.nextWorkflowName))
+		}
+	}
+	return name + "{" + strings.Join(ruleStrs, ",") + "}"
+}
+
+func TestParseWorkflowsWalksDiamondsOnce(t *testing.T) {
+	// every diamond doubles the ways to the last workflow, so walking every way would never end
+	const diamondsCount = 40
+	lines := []string{"in{x<1:b0,c0}"}
+	for i := 0; i < diamondsCount; i++ {
+		lines = append(lines, fmt.Sprintf("b%d{w%d}", i, i+1), fmt.Sprintf("c%d{w%d}", i, i+1),
+			fmt.Sprintf("w%d{x<1:b%d,c%d}", i+1, i+1, i+1))
+	}
+	lines = append(lines, fmt.Sprintf("b%d{A}", diamondsCount), fmt.Sprintf("c%d{R}", diamondsCount))
+
+	startTime := time.Now()
+	if _, _, err := parseWorkflows(lines); err != nil {
+		t.Fatal(err)
+	}
+	if duration := time.Since(startTime); duration > time.Second {
+		t.Errorf("Parsing took %s", duration)
+	}
+
+	// a cycle through the last diamond is still found
+	lines[len(lines)-1] = "c40{in}"
+	if _, _, err := parseWorkflows(lines); err == nil {
+		t.Error("Cycle isn't found")
+	}
+}
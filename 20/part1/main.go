@@ -34,21 +34,66 @@ type Pulse struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	modules, flipFlopNames, conjunctionNames, err := parseModules(lines)
+	util.PanicOnError(err)
+	fmt.Printf("%d modules are parsed\n", len(modules))
+
+	flipFlopStates := make(map[string]bool, len(flipFlopNames))
+	for _, name := range flipFlopNames {
+		flipFlopStates[name] = stateOff
+	}
+
+	conjunctionStates := make(map[string]map[string]string, len(conjunctionNames))
+	for _, name := range conjunctionNames {
+		inputNames := modules[name].inputModuleNames
+		inputStates := make(map[string]string, len(inputNames))
+		for _, inputName := range inputNames {
+			inputStates[inputName] = pulseLow
+		}
+		conjunctionStates[name] = inputStates
+	}
+
+	var lowPulseCount, highPulseCount uint
+	for i := uint(0); i < 1000; i++ {
+		lowPulses, highPulses := pressButton(flipFlopStates, conjunctionStates, modules)
+		lowPulseCount += lowPulses
+		highPulseCount += highPulses
+	}
+	fmt.Printf("%d low * %d high = %d\n", lowPulseCount, highPulseCount, lowPulseCount*highPulseCount)
+}
+
+func parseModules(lines []string) (map[string]Module, []string, []string, error) {
 	modules := make(map[string]Module, len(lines))
 	var flipFlopNames, conjunctionNames []string
 
-	for _, line := range lines {
+	for lineIdx, line := range lines {
 		parts := strings.Split(line, "->")
+		if len(parts) != 2 {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unexpected module format: %s", lineIdx+1, line)
+		}
 		kindAndName, outputsStr := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
 		outputNames := strings.Split(outputsStr, ", ")
+		for _, outputName := range outputNames {
+			if len(outputName) == 0 || strings.ContainsAny(outputName, " ,") {
+				return nil, nil, nil, fmt.Errorf("Line %d: Unexpected output module name <%s>",
+					lineIdx+1, outputName)
+			}
+		}
+		if len(kindAndName) < 2 {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unexpected module name <%s>", lineIdx+1,
+				kindAndName)
+		}
 
+		var module Module
 		kindAndNameFirstChar := kindAndName[:1]
 		if kindAndNameFirstChar == kindFlipFlop {
 			name := kindAndName[1:]
-			modules[name] = Module{
+			module = Module{
 				kind:              kindFlipFlop,
 				name:              name,
 				outputModuleNames: outputNames,
@@ -56,22 +101,32 @@ func main() {
 			flipFlopNames = append(flipFlopNames, name)
 		} else if kindAndNameFirstChar == kindConjunction {
 			name := kindAndName[1:]
-			modules[name] = Module{
+			module = Module{
 				kind:              kindConjunction,
 				name:              name,
 				outputModuleNames: outputNames,
 			}
 			conjunctionNames = append(conjunctionNames, name)
-		} else {
-			// broadcaster
-			modules[kindAndName] = Module{
+		} else if kindAndName == kindBroadcaster {
+			module = Module{
 				kind:              kindAndName,
 				name:              kindAndName,
 				outputModuleNames: outputNames,
 			}
+		} else {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unknown module kind of %s", lineIdx+1,
+				kindAndName)
+		}
+
+		if _, found := modules[module.name]; found {
+			return nil, nil, nil, fmt.Errorf("Line %d: Duplicate module %s", lineIdx+1, module.name)
 		}
+		modules[module.name] = module
+	}
+
+	if _, found := modules[kindBroadcaster]; !found {
+		return nil, nil, nil, fmt.Errorf("Module %s isn't found", kindBroadcaster)
 	}
-	fmt.Printf("%d modules are parsed\n", len(modules))
 
 	for _, conjName := range conjunctionNames {
 		var conjInputs []string
@@ -85,29 +140,7 @@ func main() {
 		conjModule.inputModuleNames = conjInputs
 		modules[conjName] = conjModule
 	}
-
-	flipFlopStates := make(map[string]bool, len(flipFlopNames))
-	for _, name := range flipFlopNames {
-		flipFlopStates[name] = stateOff
-	}
-
-	conjunctionStates := make(map[string]map[string]string, len(conjunctionNames))
-	for _, name := range conjunctionNames {
-		inputNames := modules[name].inputModuleNames
-		inputStates := make(map[string]string, len(inputNames))
-		for _, inputName := range inputNames {
-			inputStates[inputName] = pulseLow
-		}
-		conjunctionStates[name] = inputStates
-	}
-
-	var lowPulseCount, highPulseCount uint
-	for i := uint(0); i < 1000; i++ {
-		lowPulses, highPulses := pressButton(flipFlopStates, conjunctionStates, modules)
-		lowPulseCount += lowPulses
-		highPulseCount += highPulses
-	}
-	fmt.Printf("%d low * %d high = %d\n", lowPulseCount, highPulseCount, lowPulseCount*highPulseCount)
+	return modules, flipFlopNames, conjunctionNames, nil
 }
 
 func pressButton(
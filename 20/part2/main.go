@@ -33,58 +33,15 @@ type Pulse struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	modules := make(map[string]Module, len(lines))
-	var flipFlopNames, conjunctionNames []string
-
-	for _, line := range lines {
-		parts := strings.Split(line, "->")
-		kindAndName, outputsStr := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
-		outputNames := strings.Split(outputsStr, ", ")
-
-		kindAndNameFirstChar := kindAndName[:1]
-		if kindAndNameFirstChar == moduleKindFlipFlop {
-			name := kindAndName[1:]
-			modules[name] = Module{
-				kind:              moduleKindFlipFlop,
-				name:              name,
-				outputModuleNames: outputNames,
-			}
-			flipFlopNames = append(flipFlopNames, name)
-		} else if kindAndNameFirstChar == moduleKindConjunction {
-			name := kindAndName[1:]
-			modules[name] = Module{
-				kind:              moduleKindConjunction,
-				name:              name,
-				outputModuleNames: outputNames,
-			}
-			conjunctionNames = append(conjunctionNames, name)
-		} else {
-			// broadcaster
-			modules[kindAndName] = Module{
-				kind:              kindAndName,
-				name:              kindAndName,
-				outputModuleNames: outputNames,
-			}
-		}
-	}
+	modules, flipFlopNames, conjunctionNames, err := parseModules(lines)
+	util.PanicOnError(err)
 	fmt.Printf("%d modules are parsed\n", len(modules))
 
-	for _, conjName := range conjunctionNames {
-		var conjInputs []string
-		for modName, module := range modules {
-			if slices.Contains(module.outputModuleNames, conjName) {
-				conjInputs = append(conjInputs, modName)
-			}
-		}
-
-		conjModule := modules[conjName]
-		conjModule.inputModuleNames = conjInputs
-		modules[conjName] = conjModule
-	}
-
 	var rxModuleInputs []Module
 	for _, module := range modules {
 		if slices.Contains(module.outputModuleNames, "rx") {
@@ -138,6 +95,82 @@ func main() {
 	}
 }
 
+func parseModules(lines []string) (map[string]Module, []string, []string, error) {
+	modules := make(map[string]Module, len(lines))
+	var flipFlopNames, conjunctionNames []string
+
+	for lineIdx, line := range lines {
+		parts := strings.Split(line, "->")
+		if len(parts) != 2 {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unexpected module format: %s", lineIdx+1, line)
+		}
+		kindAndName, outputsStr := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		outputNames := strings.Split(outputsStr, ", ")
+		for _, outputName := range outputNames {
+			if len(outputName) == 0 || strings.ContainsAny(outputName, " ,") {
+				return nil, nil, nil, fmt.Errorf("Line %d: Unexpected output module name <%s>",
+					lineIdx+1, outputName)
+			}
+		}
+		if len(kindAndName) < 2 {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unexpected module name <%s>", lineIdx+1,
+				kindAndName)
+		}
+
+		var module Module
+		kindAndNameFirstChar := kindAndName[:1]
+		if kindAndNameFirstChar == moduleKindFlipFlop {
+			name := kindAndName[1:]
+			module = Module{
+				kind:              moduleKindFlipFlop,
+				name:              name,
+				outputModuleNames: outputNames,
+			}
+			flipFlopNames = append(flipFlopNames, name)
+		} else if kindAndNameFirstChar == moduleKindConjunction {
+			name := kindAndName[1:]
+			module = Module{
+				kind:              moduleKindConjunction,
+				name:              name,
+				outputModuleNames: outputNames,
+			}
+			conjunctionNames = append(conjunctionNames, name)
+		} else if kindAndName == moduleKindBroadcaster {
+			module = Module{
+				kind:              kindAndName,
+				name:              kindAndName,
+				outputModuleNames: outputNames,
+			}
+		} else {
+			return nil, nil, nil, fmt.Errorf("Line %d: Unknown module kind of %s", lineIdx+1,
+				kindAndName)
+		}
+
+		if _, found := modules[module.name]; found {
+			return nil, nil, nil, fmt.Errorf("Line %d: Duplicate module %s", lineIdx+1, module.name)
+		}
+		modules[module.name] = module
+	}
+
+	if _, found := modules[moduleKindBroadcaster]; !found {
+		return nil, nil, nil, fmt.Errorf("Module %s isn't found", moduleKindBroadcaster)
+	}
+
+	for _, conjName := range conjunctionNames {
+		var conjInputs []string
+		for modName, module := range modules {
+			if slices.Contains(module.outputModuleNames, conjName) {
+				conjInputs = append(conjInputs, modName)
+			}
+		}
+
+		conjModule := modules[conjName]
+		conjModule.inputModuleNames = conjInputs
+		modules[conjName] = conjModule
+	}
+	return modules, flipFlopNames, conjunctionNames, nil
+}
+
 func detectPulse(
 	sourceModuleName, pulseKind, targetModuleName string,
 	times, iterations uint,
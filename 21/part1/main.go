@@ -1,69 +1,44 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/grid"
 )
 
 const (
 	charStart  = "S"
 	charGarden = "."
+	charRock   = "#"
 
 	steps = 64
 )
 
-type Coord struct {
-	rowIdx, colIdx uint8
-}
-
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var startCoord Coord
-	for rowIdx, line := range lines {
-		if startIdx := strings.Index(line, charStart); startIdx != -1 {
-			startCoord = Coord{
-				rowIdx: uint8(rowIdx),
-				colIdx: uint8(startIdx),
-			}
-			break
-		}
-	}
+	startCoord, err := findStart(lines)
+	util.PanicOnError(err)
 
-	fmt.Printf("Start is detected at coord %d:%d\n", startCoord.rowIdx+1, startCoord.colIdx+1)
+	fmt.Printf("Start is detected at coord %d:%d\n", startCoord.Row+1, startCoord.Col+1)
 
-	prevCoords := map[Coord]bool{
-		startCoord: true,
-	}
-	
-	for i := uint(0); i < steps; i++ {
-		curCoords := make(map[Coord]bool)
-		for prevCoord := range prevCoords {
-			// up
-			coord, valid := getGardenCoordIfValid(lines, int(prevCoord.rowIdx)-1, int(prevCoord.colIdx))
-			if valid {
-				curCoords[coord] = true
-			}
-
-			// down
-			coord, valid = getGardenCoordIfValid(lines, int(prevCoord.rowIdx)+1, int(prevCoord.colIdx))
-			if valid {
-				curCoords[coord] = true
-			}
+	garden, err := grid.FromLines(lines)
+	util.PanicOnError(err)
 
-			// right
-			coord, valid = getGardenCoordIfValid(lines, int(prevCoord.rowIdx), int(prevCoord.colIdx)+1)
-			if valid {
-				curCoords[coord] = true
-			}
+	prevCoords := util.NewSet(startCoord)
 
-			// left
-			coord, valid = getGardenCoordIfValid(lines, int(prevCoord.rowIdx), int(prevCoord.colIdx)-1)
-			if valid {
-				curCoords[coord] = true
+	for i := uint(0); i < steps; i++ {
+		curCoords := util.NewSet[grid.Point]()
+		for prevCoord := range prevCoords {
+			for _, coord := range grid.Neighbours[byte](garden, prevCoord) {
+				if garden.At(coord) != charRock[0] {
+					curCoords.Add(coord)
+				}
 			}
 		}
 		prevCoords = curCoords
@@ -72,23 +47,39 @@ func main() {
 	fmt.Printf("Reachable coords in %d steps: %d\n", steps, len(prevCoords))
 }
 
-func getGardenCoordIfValid(lines []string, rowIdx, colIdx int) (Coord, bool) {
-	if rowIdx < 0 || colIdx < 0 || rowIdx >= len(lines) {
-		return Coord{}, false
+func findStart(lines []string) (grid.Point, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return grid.Point{}, errors.New("Garden is empty")
 	}
 
-	line := lines[rowIdx]
-	if colIdx >= len(line) {
-		return Coord{}, false
+	var startCoord *grid.Point
+	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return grid.Point{}, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1,
+				len(line), len(lines[0]))
+		}
+
+		for colIdx, r := range line {
+			switch string(r) {
+			case charStart:
+				if startCoord != nil {
+					return grid.Point{}, fmt.Errorf("Second starting coord found at %d:%d", rowIdx+1,
+						colIdx+1)
+				}
+				startCoord = &grid.Point{
+					Row: rowIdx,
+					Col: colIdx,
+				}
+			case charGarden, charRock:
+			default:
+				return grid.Point{}, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1, colIdx+1)
+			}
+		}
 	}
 
-	charAtCoord := line[colIdx : colIdx+1]
-	if charAtCoord != charStart && charAtCoord != charGarden {
-		return Coord{}, false
+	if startCoord == nil {
+		return grid.Point{}, errors.New("Starting coord isn't found")
 	}
 
-	return Coord{
-		rowIdx: uint8(rowIdx),
-		colIdx: uint8(colIdx),
-	}, true
+	return *startCoord, nil
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
@@ -25,31 +26,24 @@ type Diff struct {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	accesibleTilesCount := uint(0)
-	var startCoord *Coord
+	startCoord, err := findStart(lines)
+	util.PanicOnError(err)
 
-	// find start coord and count non-rock tiles
-	for rowIdx, line := range lines {
-		for colIdx, r := range line {
+	// count non-rock tiles
+	accesibleTilesCount := uint(0)
+	for _, line := range lines {
+		for _, r := range line {
 			b := byte(r)
-			if b == charStart[0] {
-				startCoord = &Coord{
-					rowIdx: uint8(rowIdx),
-					colIdx: uint8(colIdx),
-				}
-				accesibleTilesCount++
-			} else if b == charGarden[0] {
+			if b == charStart[0] || b == charGarden[0] {
 				accesibleTilesCount++
 			}
 		}
 	}
-
-	if startCoord == nil {
-		panic(fmt.Errorf("Starting coord isn't found"))
-	}
 	fmt.Printf("Start is detected at coord %d:%d\n", startCoord.rowIdx+1, startCoord.colIdx+1)
 
 	directionDiffs := []Diff{
@@ -59,23 +53,21 @@ func main() {
 		{-1, 0}, // up
 	}
 	minDistanceByCoord := map[Coord]uint{
-		*startCoord: 0,
-	}
-	prevCoords := map[Coord]bool{
-		*startCoord: true,
+		startCoord: 0,
 	}
+	prevCoords := util.NewSet(startCoord)
 
 	step := uint(1)
 	for {
 		distancesMeasured := len(minDistanceByCoord)
-		curCoords := make(map[Coord]bool)
+		curCoords := util.NewSet[Coord]()
 
 		for prevCoord := range prevCoords {
 			for _, diff := range directionDiffs {
 				coord, valid := getGardenCoordIfValid(lines, int(prevCoord.rowIdx)+int(diff.row),
 					int(prevCoord.colIdx)+int(diff.col))
 				if valid {
-					curCoords[coord] = true
+					curCoords.Add(coord)
 
 					curDistance, alreadyVisited := minDistanceByCoord[coord]
 					minDistance := step
@@ -131,6 +123,46 @@ func main() {
 	fmt.Printf("Total visited tiles: %d\n", uint(visitedTiles))
 }
 
+func findStart(lines []string) (Coord, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return Coord{}, errors.New("Garden is empty")
+	}
+	if len(lines) > math.MaxUint8+1 || len(lines[0]) > math.MaxUint8+1 {
+		return Coord{}, fmt.Errorf("Garden %dx%d is too big", len(lines), len(lines[0]))
+	}
+
+	var startCoord *Coord
+	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return Coord{}, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1,
+				len(line), len(lines[0]))
+		}
+
+		for colIdx, r := range line {
+			switch string(r) {
+			case charStart:
+				if startCoord != nil {
+					return Coord{}, fmt.Errorf("Second starting coord found at %d:%d", rowIdx+1,
+						colIdx+1)
+				}
+				startCoord = &Coord{
+					rowIdx: uint8(rowIdx),
+					colIdx: uint8(colIdx),
+				}
+			case charGarden, charRock:
+			default:
+				return Coord{}, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1, colIdx+1)
+			}
+		}
+	}
+
+	if startCoord == nil {
+		return Coord{}, errors.New("Starting coord isn't found")
+	}
+
+	return *startCoord, nil
+}
+
 func getGardenCoordIfValid(lines []string, rowIdx, colIdx int) (Coord, bool) {
 	if rowIdx < 0 || colIdx < 0 || rowIdx >= len(lines) {
 		return Coord{}, false
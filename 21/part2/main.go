@@ -1,10 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/grid"
 )
 
 const (
@@ -15,43 +16,21 @@ const (
 	targetStep = 26_501_365
 )
 
-type Coord struct {
-	rowIdx, colIdx int
-}
-
-type Diff struct {
-	row, col int8
-}
-
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	var startCoord *Coord
-	for rowIdx, line := range lines {
-		if startIdx := strings.Index(line, charStart); startIdx != -1 {
-			startCoord = &Coord{
-				rowIdx: rowIdx,
-				colIdx: startIdx,
-			}
-			break
-		}
-	}
-	if startCoord == nil {
-		panic(fmt.Errorf("Starting coord isn't found"))
-	}
-	fmt.Printf("Start is detected at coord %d:%d\n", startCoord.rowIdx+1, startCoord.colIdx+1)
+	startCoord, err := findStart(lines)
+	util.PanicOnError(err)
+	fmt.Printf("Start is detected at coord %d:%d\n", startCoord.Row+1, startCoord.Col+1)
 
-	prevCoords := map[Coord]bool{
-		*startCoord: true,
-	}
+	tile, err := grid.FromLines(lines)
+	util.PanicOnError(err)
+	garden := grid.NewTiled(tile)
 
-	directionDiffs := []Diff{
-		{0, 1},  // right
-		{1, 0},  // down
-		{0, -1}, // left
-		{-1, 0}, // up
-	}
+	prevCoords := util.NewSet(startCoord)
 
 	fieldSize := uint(len(lines))
 	initialFieldSteps := fieldSize / 2
@@ -68,13 +47,11 @@ func main() {
 	}
 
 	for step := uint(1); step <= thirdFieldSteps; step++ {
-		curCoords := make(map[Coord]bool)
+		curCoords := util.NewSet[grid.Point]()
 		for prevCoord := range prevCoords {
-			for _, diff := range directionDiffs {
-				coord, valid := getGardenCoordIfValid(lines, prevCoord.rowIdx+int(diff.row),
-					prevCoord.colIdx+int(diff.col))
-				if valid {
-					curCoords[coord] = true
+			for _, coord := range grid.Neighbours[byte](garden, prevCoord) {
+				if garden.At(coord) != charRock[0] {
+					curCoords.Add(coord)
 				}
 			}
 		}
@@ -99,44 +76,41 @@ func main() {
 		(targetStep-initialFieldSteps)/fieldSize+1))
 }
 
-func getGardenCoordIfValid(lines []string, rowIdx, colIdx int) (Coord, bool) {
-	linesLen := uint(len(lines))
-
-	normalizedRowIdx := normalizeIdx(rowIdx, linesLen)
-
-	line := lines[normalizedRowIdx]
-	colsLen := uint(len(line))
-
-	normalizedColIdx := normalizeIdx(colIdx, colsLen)
-
-	charAtCoord := line[normalizedColIdx : normalizedColIdx+1]
-	if charAtCoord != charStart && charAtCoord != charGarden {
-		return Coord{}, false
+func findStart(lines []string) (grid.Point, error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return grid.Point{}, errors.New("Garden is empty")
 	}
 
-	return Coord{
-		rowIdx: rowIdx,
-		colIdx: colIdx,
-	}, true
-}
+	var startCoord *grid.Point
+	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return grid.Point{}, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1,
+				len(line), len(lines[0]))
+		}
 
-func normalizeIdx(idx int, idxTotal uint) int {
-	normalizedIdx := idx
-	if abs(normalizedIdx) >= int(idxTotal) {
-		normalizedIdx %= int(idxTotal)
-	}
-	if normalizedIdx < 0 {
-		normalizedIdx += int(idxTotal)
+		for colIdx, r := range line {
+			switch string(r) {
+			case charStart:
+				if startCoord != nil {
+					return grid.Point{}, fmt.Errorf("Second starting coord found at %d:%d", rowIdx+1,
+						colIdx+1)
+				}
+				startCoord = &grid.Point{
+					Row: rowIdx,
+					Col: colIdx,
+				}
+			case charGarden, charRock:
+			default:
+				return grid.Point{}, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1, colIdx+1)
+			}
+		}
 	}
 
-	return normalizedIdx
-}
-
-func abs(i int) int {
-	if i < 0 {
-		return -i
+	if startCoord == nil {
+		return grid.Point{}, errors.New("Starting coord isn't found")
 	}
-	return i
+
+	return *startCoord, nil
 }
 
 func predictNthValue(vals []uint, targetValIdx uint) uint {
@@ -1,68 +1,75 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"maps"
+	"math"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/geom"
 )
 
-type End struct {
-	x, y, z uint16
-}
-
 type Brick struct {
-	id                  string
-	lowerEnd, higherEnd End
+	id  string
+	box geom.Box3
 }
 
-func (b Brick) getProjection() Projection {
-	minX, maxX := min(b.lowerEnd.x, b.higherEnd.x), max(b.lowerEnd.x, b.higherEnd.x)
-	minY, maxY := min(b.lowerEnd.y, b.higherEnd.y), max(b.lowerEnd.y, b.higherEnd.y)
+func (b Brick) getProjection() geom.Box2 {
+	return b.box.Project(geom.PlaneXY)
+}
 
-	return Projection{
-		fromX: minX,
-		toX:   maxX,
-		fromY: minY,
-		toY:   maxY,
+func parseBrick(lineIdx int, line string) (Brick, error) {
+	if lineIdx > math.MaxUint16 {
+		return Brick{}, fmt.Errorf("Line %d: Too many bricks", lineIdx+1)
 	}
-}
 
-type Projection struct {
-	fromX, toX, fromY, toY uint16
-}
+	endStrs := strings.Split(line, "~")
+	if len(endStrs) != 2 {
+		return Brick{}, fmt.Errorf("Line %d has %d ends", lineIdx+1, len(endStrs))
+	}
 
-func (p Projection) overlapsWith(other Projection) bool {
-	noOverlap := p.fromX > other.toX || // this is to the right of other
-		p.toX < other.fromX || // this is to the left of other
-		p.fromY > other.toY || // this is above of other
-		p.toY < other.fromY // this is below of other
+	var ends []geom.Vec3
+	for _, endStr := range endStrs {
+		coordStrs := strings.Split(endStr, ",")
+		if len(coordStrs) != 3 {
+			return Brick{}, fmt.Errorf("Line %d: End %s has %d coords", lineIdx+1, endStr,
+				len(coordStrs))
+		}
 
-	return !noOverlap
-}
+		var coords []int
+		for _, coordStr := range coordStrs {
+			coord, err := strconv.ParseUint(coordStr, 10, 16)
+			if err != nil {
+				return Brick{}, errors.Join(fmt.Errorf("Line %d: Failed to parse <%s> as uint16",
+					lineIdx+1, coordStr), err)
+			}
+			coords = append(coords, int(coord))
+		}
 
-func newBrick(lineIdx int, end1, end2 End) Brick {
-	lowerEnd, higherEnd := end1, end2
-	if lowerEnd.z > higherEnd.z {
-		lowerEnd, higherEnd = higherEnd, lowerEnd
+		ends = append(ends, geom.Vec3{
+			X: coords[0],
+			Y: coords[1],
+			Z: coords[2],
+		})
 	}
 
+	return newBrick(lineIdx, ends[0], ends[1])
+}
+
+func newBrick(lineIdx int, end1, end2 geom.Vec3) (Brick, error) {
 	brick := Brick{
-		id:        toStringId(uint16(lineIdx)),
-		lowerEnd:  lowerEnd,
-		higherEnd: higherEnd,
+		id:  toStringId(uint16(lineIdx)),
+		box: geom.NewBox3(end1, end2),
 	}
-	if brick.lowerEnd.z == 0 || brick.higherEnd.z == 0 {
-		panic(fmt.Errorf("Block on line %d in on ground level", lineIdx+1))
+	if brick.box.Min.Z == 0 {
+		return Brick{}, fmt.Errorf("Block on line %d in on ground level", lineIdx+1)
 	}
 
-	axesState := []bool{
-		brick.lowerEnd.x != brick.higherEnd.x,
-		brick.lowerEnd.y != brick.higherEnd.y,
-		brick.lowerEnd.z != brick.higherEnd.z,
-	}
+	size := brick.box.Size()
+	axesState := []bool{size.X > 1, size.Y > 1, size.Z > 1}
 
 	var differentAxesCount uint8
 	for _, axisIsDifferent := range axesState {
@@ -71,49 +78,21 @@ func newBrick(lineIdx int, end1, end2 End) Brick {
 		}
 	}
 	if differentAxesCount > 1 {
-		panic(fmt.Errorf("Block on line %d has more than 1 coordinate different: %v", lineIdx+1,
-			brick))
+		return Brick{}, fmt.Errorf("Block on line %d has more than 1 coordinate different: %v",
+			lineIdx+1, brick)
 	}
 
-	return brick
+	return brick, nil
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	brickById := make(map[string]Brick)
-	brickIdsByLowerEndZ := make(map[uint16]map[string]bool)
-	brickIdsByHigherEndZ := make(map[uint16]map[string]bool)
-	var maxZ uint16
-
-	for lineIdx, line := range lines {
-		endStrs := strings.Split(line, "~")
-		if len(endStrs) != 2 {
-			panic(fmt.Errorf("Line %d has %d ends", lineIdx+1, len(endStrs)))
-		}
-		end1CoordStrs := strings.Split(endStrs[0], ",")
-		end2CoordStrs := strings.Split(endStrs[1], ",")
-
-		brick := newBrick(lineIdx, End{
-			x: uint16(util.ParseUintOrPanic(end1CoordStrs[0])),
-			y: uint16(util.ParseUintOrPanic(end1CoordStrs[1])),
-			z: uint16(util.ParseUintOrPanic(end1CoordStrs[2])),
-		},
-			End{
-				x: uint16(util.ParseUintOrPanic(end2CoordStrs[0])),
-				y: uint16(util.ParseUintOrPanic(end2CoordStrs[1])),
-				z: uint16(util.ParseUintOrPanic(end2CoordStrs[2])),
-			},
-		)
-
-		brickById[brick.id] = brick
-
-		addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.lowerEnd.z, brick.id)
-		addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.higherEnd.z, brick.id)
-
-		maxZ = max(maxZ, brick.higherEnd.z)
-	}
+	brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ, err := parseSnapshot(lines)
+	util.PanicOnError(err)
 
 	fmt.Printf("Parsed %d bricks:\n", len(brickById))
 	fmt.Println(formatBricksMap(brickById, func(b1, b2 Brick) int {
@@ -124,18 +103,49 @@ func main() {
 
 	fmt.Println("After gravity is applied:")
 	fmt.Println(formatBricksMap(brickById, func(b1, b2 Brick) int {
-		return int(b1.lowerEnd.z) - int(b2.lowerEnd.z)
+		return b1.box.Min.Z - b2.box.Min.Z
 	}, supportingBricksById))
 
-	theOnlySupportingBrickIds := make(map[string]bool)
+	theOnlySupportingBrickIds := util.NewSet[string]()
 	for _, brickIds := range supportingBricksById {
 		if len(brickIds) == 1 {
-			maps.Copy(theOnlySupportingBrickIds, brickIds)
+			theOnlySupportingBrickIds.Add(brickIds.Slice()...)
 		}
 	}
 	fmt.Println("Bricks safe to disintegrate:", len(brickById)-len(theOnlySupportingBrickIds))
 }
 
+// parseSnapshot returns bricks by their ids, ids of bricks by Z of their lower and higher ends and
+// max Z of all the bricks
+func parseSnapshot(lines []string) (
+	map[string]Brick,
+	map[int]util.Set[string],
+	map[int]util.Set[string],
+	int,
+	error,
+) {
+	brickById := make(map[string]Brick)
+	brickIdsByLowerEndZ := make(map[int]util.Set[string])
+	brickIdsByHigherEndZ := make(map[int]util.Set[string])
+	var maxZ int
+
+	for lineIdx, line := range lines {
+		brick, err := parseBrick(lineIdx, line)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+
+		brickById[brick.id] = brick
+
+		addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.box.Min.Z, brick.id)
+		addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.box.Max.Z, brick.id)
+
+		maxZ = max(maxZ, brick.box.Max.Z)
+	}
+
+	return brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ, nil
+}
+
 /*
 0  -> A
 1  -> B
@@ -160,21 +170,21 @@ func toStringId(toConvert uint16) string {
 	return s
 }
 
-func addBrickIdToLevelMap(bricksByLevelMap map[uint16]map[string]bool, level uint16, brickId string) {
+func addBrickIdToLevelMap(bricksByLevelMap map[int]util.Set[string], level int, brickId string) {
 	levelBricks := bricksByLevelMap[level]
 	if levelBricks == nil {
-		levelBricks = make(map[string]bool)
+		levelBricks = util.NewSet[string]()
 	}
-	levelBricks[brickId] = true
+	levelBricks.Add(brickId)
 	bricksByLevelMap[level] = levelBricks
 }
 
-func removeBrickIdFromLevelMap(bricksByLevelMap map[uint16]map[string]bool, level uint16, brickId string) {
+func removeBrickIdFromLevelMap(bricksByLevelMap map[int]util.Set[string], level int, brickId string) {
 	levelBricks := bricksByLevelMap[level]
 	if levelBricks == nil {
 		return
 	}
-	delete(levelBricks, brickId)
+	levelBricks.Remove(brickId)
 
 	if len(levelBricks) == 0 {
 		levelBricks = nil
@@ -184,13 +194,13 @@ func removeBrickIdFromLevelMap(bricksByLevelMap map[uint16]map[string]bool, leve
 
 func applyGravity(
 	brickById map[string]Brick,
-	brickIdsByLowerEndZ, brickIdsByHigherEndZ map[uint16]map[string]bool,
-	maxZ uint16,
-) map[string]map[string]bool {
-	supportingBricksById := make(map[string]map[string]bool)
+	brickIdsByLowerEndZ, brickIdsByHigherEndZ map[int]util.Set[string],
+	maxZ int,
+) map[string]util.Set[string] {
+	supportingBricksById := make(map[string]util.Set[string])
 
 	// over all levels
-	for z := uint16(2); z <= maxZ; z++ {
+	for z := 2; z <= maxZ; z++ {
 		brickIds := brickIdsByLowerEndZ[z]
 
 		// over all bricks on the current level
@@ -199,15 +209,15 @@ func applyGravity(
 			prj := brick.getProjection()
 
 			// over lower levels
-			for zDiff := uint16(1); brick.lowerEnd.z-zDiff > 0; zDiff++ {
-				targetZ := brick.lowerEnd.z - zDiff
+			for zDiff := 1; brick.box.Min.Z-zDiff > 0; zDiff++ {
+				targetZ := brick.box.Min.Z - zDiff
 				brickIdsOnThisZ := brickIdsByHigherEndZ[targetZ]
 
-				supportingBrickIds := make(map[string]bool)
+				supportingBrickIds := util.NewSet[string]()
 				for lowerBrickId := range brickIdsOnThisZ {
 					lowerBrick := brickById[lowerBrickId]
-					if prj.overlapsWith(lowerBrick.getProjection()) {
-						supportingBrickIds[lowerBrickId] = true
+					if prj.Overlaps(lowerBrick.getProjection()) {
+						supportingBrickIds.Add(lowerBrickId)
 					}
 				}
 
@@ -215,14 +225,13 @@ func applyGravity(
 				if len(supportingBrickIds) > 0 {
 					// supporting brick is more than 1 level lower; apply gravity!
 					if zDiff > 1 {
-						removeBrickIdFromLevelMap(brickIdsByLowerEndZ, brick.lowerEnd.z, brickId)
-						removeBrickIdFromLevelMap(brickIdsByHigherEndZ, brick.higherEnd.z, brickId)
+						removeBrickIdFromLevelMap(brickIdsByLowerEndZ, brick.box.Min.Z, brickId)
+						removeBrickIdFromLevelMap(brickIdsByHigherEndZ, brick.box.Max.Z, brickId)
 
-						brick.lowerEnd.z = brick.lowerEnd.z - (zDiff - 1)
-						brick.higherEnd.z = brick.higherEnd.z - (zDiff - 1)
+						brick.box = brick.box.Translate(geom.Vec3{Z: -(zDiff - 1)})
 
-						addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.lowerEnd.z, brickId)
-						addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.higherEnd.z, brickId)
+						addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.box.Min.Z, brickId)
+						addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.box.Max.Z, brickId)
 
 						fmt.Printf("Gravity moved brick %s %d levels below\n", brick.id, zDiff-1)
 					}
@@ -234,17 +243,16 @@ func applyGravity(
 			}
 
 			// lowest brick is still in the air; apply gravity!
-			if len(supportingBricksById[brickId]) == 0 && brick.lowerEnd.z > 1 {
-				levelDiff := brick.lowerEnd.z - 1
+			if len(supportingBricksById[brickId]) == 0 && brick.box.Min.Z > 1 {
+				levelDiff := brick.box.Min.Z - 1
 
-				removeBrickIdFromLevelMap(brickIdsByLowerEndZ, brick.lowerEnd.z, brickId)
-				removeBrickIdFromLevelMap(brickIdsByHigherEndZ, brick.higherEnd.z, brickId)
+				removeBrickIdFromLevelMap(brickIdsByLowerEndZ, brick.box.Min.Z, brickId)
+				removeBrickIdFromLevelMap(brickIdsByHigherEndZ, brick.box.Max.Z, brickId)
 				
-				brick.lowerEnd.z = brick.lowerEnd.z - levelDiff
-				brick.higherEnd.z = brick.higherEnd.z - levelDiff
+				brick.box = brick.box.Translate(geom.Vec3{Z: -levelDiff})
 
-				addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.lowerEnd.z, brickId)
-				addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.higherEnd.z, brickId)
+				addBrickIdToLevelMap(brickIdsByLowerEndZ, brick.box.Min.Z, brickId)
+				addBrickIdToLevelMap(brickIdsByHigherEndZ, brick.box.Max.Z, brickId)
 
 				fmt.Printf("Gravity moved brick %s %d level below - to ground\n", brick.id, levelDiff)
 			}
@@ -259,18 +267,19 @@ func applyGravity(
 func formatBricksMap(
 	brickById map[string]Brick,
 	comparator func(b1, b2 Brick) int,
-	supportingBricksById map[string]map[string]bool,
+	supportingBricksById map[string]util.Set[string],
 ) string {
-	var bricks []Brick
-	for _, brick := range brickById {
-		bricks = append(bricks, brick)
+	// bricks the comparator considers equal are kept in the order of their ids
+	bricks := make([]Brick, 0, len(brickById))
+	for _, brickId := range util.MapKeysToSortedSlice(brickById) {
+		bricks = append(bricks, brickById[brickId])
 	}
-	slices.SortFunc(bricks, comparator)
-	
+	slices.SortStableFunc(bricks, comparator)
+
 	return formatBricks(bricks, supportingBricksById)
 }
 
-func formatBricks(bricks []Brick, supportingBricksById map[string]map[string]bool) string {
+func formatBricks(bricks []Brick, supportingBricksById map[string]util.Set[string]) string {
 	var formatted []string
 	for _, b := range bricks {
 		formatted = append(formatted, formatBrick(b, supportingBricksById[b.id]))
@@ -278,14 +287,9 @@ func formatBricks(bricks []Brick, supportingBricksById map[string]map[string]boo
 	return strings.Join(formatted, "\n")
 }
 
-func formatBrick(b Brick, supportingBrickIds map[string]bool) string {
-	var supportingBrickIdsSl []string
-	for brickId := range supportingBrickIds {
-		supportingBrickIdsSl = append(supportingBrickIdsSl, brickId)
-	}
-	slices.Sort(supportingBrickIdsSl)
-	supportingBrickIdsStr := strings.Join(supportingBrickIdsSl, ",")
+func formatBrick(b Brick, supportingBrickIds util.Set[string]) string {
+	supportingBrickIdsStr := strings.Join(util.SortedSetItems(supportingBrickIds), ",")
 
-	return fmt.Sprintf("[%s]%d:%d:%d~%d:%d:%d^%s", b.id, b.lowerEnd.x, b.lowerEnd.y, b.lowerEnd.z,
-		b.higherEnd.x, b.higherEnd.y, b.higherEnd.z, supportingBrickIdsStr)
+	return fmt.Sprintf("[%s]%d:%d:%d~%d:%d:%d^%s", b.id, b.box.Min.X, b.box.Min.Y, b.box.Min.Z,
+		b.box.Max.X, b.box.Max.Y, b.box.Max.Z, supportingBrickIdsStr)
 }
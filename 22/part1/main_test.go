@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
 )
 
 func TestToStringId(t *testing.T) {
@@ -24,22 +27,75 @@ func TestToStringId(t *testing.T) {
 	}
 }
 
-func TestOverlapsWith(t *testing.T) {
-	basePrj := Projection{1, 1, 1, 1}
+func TestProjectionsOverlap(t *testing.T) {
+	baseBrick, err := parseBrick(0, "1,1,8~1,1,9")
+	if err != nil {
+		t.Fatal(err)
+	}
 	inputs := []struct {
-		other Projection
-		want  bool
+		line string
+		want bool
 	}{
-		{Projection{2, 2, 1, 1}, false},
-		{Projection{1, 1, 2, 2}, false},
-		{Projection{2, 2, 2, 2}, false},
-		{Projection{1, 2, 1, 2}, true},
+		{"2,1,1~2,2,1", false},
+		{"1,2,1~1,2,1", false},
+		{"2,2,1~2,2,1", false},
+		{"1,1,1~1,1,2", true},
+		{"0,1,6~2,1,6", true},
 	}
 
 	for i, input := range inputs {
-		got := basePrj.overlapsWith(input.other)
+		brick, err := parseBrick(i+1, input.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := baseBrick.getProjection().Overlaps(brick.getProjection())
 		if got != input.want {
-			t.Errorf("prj #%d. wanted %t, got %t", i, input.want, got)
+			t.Errorf("brick %s. wanted %t, got %t", input.line, input.want, got)
 		}
 	}
 }
+
+func FuzzParseBrick(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			brick, err := parseBrick(lineIdx, line)
+			if err != nil {
+				return
+			}
+
+			// a brick is a line of cubes along a single axis above the ground
+			size := brick.box.Size()
+			if brick.box.Min.Z == 0 || size.X < 1 || size.Y < 1 || size.Z < 1 ||
+				(size.X > 1 && size.Y > 1) || (size.X > 1 && size.Z > 1) ||
+				(size.Y > 1 && size.Z > 1) {
+				t.Fatalf("Line %q is parsed as brick %v", line, brick.box)
+			}
+
+			// ends may be listed in any order
+			min, max := brick.box.Min, brick.box.Max
+			for _, formatted := range []string{
+				fmt.Sprintf("%d,%d,%d~%d,%d,%d", min.X, min.Y, min.Z, max.X, max.Y, max.Z),
+				fmt.Sprintf("%d,%d,%d~%d,%d,%d", max.X, max.Y, max.Z, min.X, min.Y, min.Z),
+			} {
+				reparsed, err := parseBrick(lineIdx, formatted)
+				if err != nil || reparsed != brick {
+					t.Errorf("Line %q is parsed as %v, but %q as %v, %v", line, brick,
+						formatted, reparsed, err)
+				}
+			}
+		})
+}
+
+func TestFormatBricksMapGolden(t *testing.T) {
+	brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ, err := parseSnapshot(
+		testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	supportingBricksById := applyGravity(brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ)
+	testutil.AssertGolden(t, "testdata/bricks-after-gravity.golden", formatBricksMap(brickById,
+		func(b1, b2 Brick) int {
+			return b1.box.Min.Z - b2.box.Min.Z
+		}, supportingBricksById))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseBrick(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			brick, err := parseBrick(lineIdx, line)
+			if err != nil {
+				return
+			}
+
+			// a brick is a line of cubes along a single axis above the ground
+			size := brick.box.Size()
+			if brick.box.Min.Z == 0 || size.X < 1 || size.Y < 1 || size.Z < 1 ||
+				(size.X > 1 && size.Y > 1) || (size.X > 1 && size.Z > 1) ||
+				(size.Y > 1 && size.Z > 1) {
+				t.Fatalf("Line %q is parsed as brick %v", line, brick.box)
+			}
+
+			// ends may be listed in any order
+			min, max := brick.box.Min, brick.box.Max
+			for _, formatted := range []string{
+				fmt.Sprintf("%d,%d,%d~%d,%d,%d", min.X, min.Y, min.Z, max.X, max.Y, max.Z),
+				fmt.Sprintf("%d,%d,%d~%d,%d,%d", max.X, max.Y, max.Z, min.X, min.Y, min.Z),
+			} {
+				reparsed, err := parseBrick(lineIdx, formatted)
+				if err != nil || reparsed != brick {
+					t.Errorf("Line %q is parsed as %v, but %q as %v, %v", line, brick,
+						formatted, reparsed, err)
+				}
+			}
+		})
+}
+
+func TestFormatBricksMapGolden(t *testing.T) {
+	brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ, err := parseSnapshot(
+		testutil.ReadSampleLines(t, "../sample.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	supportingBricksById := applyGravity(brickById, brickIdsByLowerEndZ, brickIdsByHigherEndZ, maxZ)
+	testutil.AssertGolden(t, "testdata/bricks-after-gravity.golden", formatBricksMap(brickById,
+		func(b1, b2 Brick) int {
+			return b1.box.Min.Z - b2.box.Min.Z
+		}, supportingBricksById))
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"errors"
 	"fmt"
-	"maps"
+	"math"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
@@ -33,12 +33,7 @@ var (
 	directionLeft  = DirectionDiff{0, -1}
 	allDirections  = []DirectionDiff{directionUp, directionRight, directionDown, directionLeft}
 
-	slopeChars = map[string]bool{
-		charSlopeUp:    true,
-		charSlopeDown:  true,
-		charSlopeRight: true,
-		charSlopeLeft:  true,
-	}
+	slopeChars           = util.NewSet(charSlopeUp, charSlopeDown, charSlopeRight, charSlopeLeft)
 	directionBySlopeChar = map[string]DirectionDiff{
 		charSlopeUp:    directionUp,
 		charSlopeDown:  directionDown,
@@ -48,41 +43,68 @@ var (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	startCoord, endCoord, err := findStartAndEnd(lines)
+	util.PanicOnError(err)
+
+	path, err := getLongestPathToEnd(lines, startCoord, endCoord, util.NewSet[Coord]())
+	if err != nil {
+		fmt.Println("Path to end isn't found:", err.Error())
+	} else {
+		fmt.Printf("Longest path is %d steps long\n", len(path)-1)
+	}
+}
+
+func findStartAndEnd(lines []string) (Coord, Coord, error) {
 	rowsTotal := len(lines)
 
 	if rowsTotal < 2 {
-		panic(fmt.Errorf("Input has too little lines: %d", rowsTotal))
+		return Coord{}, Coord{}, fmt.Errorf("Input has too little lines: %d", rowsTotal)
+	}
+	if rowsTotal > math.MaxUint8+1 || len(lines[0]) > math.MaxUint8+1 {
+		return Coord{}, Coord{}, fmt.Errorf("Map %dx%d is too big", rowsTotal, len(lines[0]))
+	}
+
+	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return Coord{}, Coord{}, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1,
+				len(line), len(lines[0]))
+		}
+
+		for colIdx, r := range line {
+			char := string(r)
+			if char != charPath && char != charForest && !slopeChars.Contains(char) {
+				return Coord{}, Coord{}, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1,
+					colIdx+1)
+			}
+		}
 	}
 
 	startCoordColIdx := strings.Index(lines[0], charPath)
 	if startCoordColIdx == -1 {
-		panic(errors.New("Start corrd isn't found"))
+		return Coord{}, Coord{}, errors.New("Start corrd isn't found")
 	}
 	startCoord := Coord{0, uint8(startCoordColIdx)}
 
-	lastRowIdx := uint8(rowsTotal-1)
+	lastRowIdx := uint8(rowsTotal - 1)
 	endCoordColIdx := strings.Index(lines[lastRowIdx], charPath)
 	if endCoordColIdx == -1 {
-		panic(errors.New("Start corrd isn't found"))
+		return Coord{}, Coord{}, errors.New("End corrd isn't found")
 	}
 	endCoord := Coord{lastRowIdx, uint8(endCoordColIdx)}
 
-	path, err := getLongestPathToEnd(lines, startCoord, endCoord, map[Coord]bool{})
-	if err != nil {
-		fmt.Println("Path to end isn't found:", err.Error())
-	} else {
-		fmt.Printf("Longest path is %d steps long\n", len(path)-1)
-	}
+	return startCoord, endCoord, nil
 }
 
 func getLongestPathToEnd(
 	lines []string,
 	startCoord, endCoord Coord,
-	visitedCoords map[Coord]bool,
-) (map[Coord]bool, error) {
+	visitedCoords util.Set[Coord],
+) (util.Set[Coord], error) {
 	currentCoord := startCoord
 	nextCoords := getNextValidSteps(lines, currentCoord, visitedCoords)
 
@@ -92,7 +114,7 @@ func getLongestPathToEnd(
 		// fmt.Printf("Step %d:%d -> %d:%d\n", currentCoord.rowIdx+1, currentCoord.colIdx+1,
 		// 	nextCoord.rowIdx+1, nextCoord.colIdx+1)
 
-		visitedCoords[currentCoord] = true
+		visitedCoords.Add(currentCoord)
 		currentCoord = nextCoord
 
 		nextCoords = getNextValidSteps(lines, currentCoord, visitedCoords)
@@ -101,16 +123,16 @@ func getLongestPathToEnd(
 	// either expected path end or dead end
 	if len(nextCoords) == 0 {
 		if currentCoord == endCoord {
-			visitedCoords[currentCoord] = true
+			visitedCoords.Add(currentCoord)
 			return visitedCoords, nil
 		}
 		return nil, fmt.Errorf("Dead end at %d:%d", currentCoord.rowIdx+1, currentCoord.colIdx+1)
 	}
 
 	// crossing met
-	var longestPath map[Coord]bool
+	var longestPath util.Set[Coord]
 	for _, nextStep := range nextCoords {
-		visitedCoordsCopy := maps.Clone(visitedCoords)
+		visitedCoordsCopy := visitedCoords.Clone()
 		path, err := getLongestPathToEnd(lines, nextStep, endCoord, visitedCoordsCopy)
 		if err == nil {
 			// fmt.Printf("End coord reached. Path length - %d, longest so far - %d\n", len(path), 
@@ -128,13 +150,13 @@ func getLongestPathToEnd(
 	return nil, fmt.Errorf("No ways from crossing %d:%d", currentCoord.rowIdx+1, currentCoord.colIdx+1)
 }
 
-func getNextValidSteps(lines []string, coord Coord, visitedCoords map[Coord]bool) []Coord {
+func getNextValidSteps(lines []string, coord Coord, visitedCoords util.Set[Coord]) []Coord {
 	currentCoordChar := lines[coord.rowIdx][coord.colIdx : coord.colIdx+1]
 
 	switch {
 	case charForest == currentCoordChar:
 		panic(fmt.Errorf("I am in the forest at %d:%d", coord.rowIdx+1, coord.colIdx+1))
-	case slopeChars[currentCoordChar]:
+	case slopeChars.Contains(currentCoordChar):
 		direction := directionBySlopeChar[currentCoordChar]
 		nextCoord, isValid := getCoordIfValid(lines, int16(coord.rowIdx)+int16(direction.rowiDff),
 			int16(coord.colIdx)+int16(direction.colDiff), visitedCoords)
@@ -162,7 +184,7 @@ func getNextValidSteps(lines []string, coord Coord, visitedCoords map[Coord]bool
 func getCoordIfValid(
 	lines []string,
 	rowIdx, colIdx int16,
-	visitedCoords map[Coord]bool,
+	visitedCoords util.Set[Coord],
 ) (coord Coord, isValid bool) {
 	if rowIdx < 0 || colIdx < 0 {
 		return Coord{}, false
@@ -180,5 +202,5 @@ func getCoordIfValid(
 	}
 	coord = Coord{uint8(rowIdx), uint8(colIdx)}
 
-	return coord, !visitedCoords[coord]
+	return coord, !visitedCoords.Contains(coord)
 }
@@ -3,7 +3,7 @@ package main
 import (
 	"errors"
 	"fmt"
-	"maps"
+	"math"
 	"slices"
 	"strings"
 
@@ -34,12 +34,7 @@ var (
 	directionLeft  = DirectionDiff{0, -1}
 	allDirections  = []DirectionDiff{directionUp, directionRight, directionDown, directionLeft}
 
-	slopeChars = map[string]bool{
-		charSlopeUp:    true,
-		charSlopeDown:  true,
-		charSlopeRight: true,
-		charSlopeLeft:  true,
-	}
+	slopeChars           = util.NewSet(charSlopeUp, charSlopeDown, charSlopeRight, charSlopeLeft)
 	directionBySlopeChar = map[string]DirectionDiff{
 		charSlopeUp:    directionUp,
 		charSlopeDown:  directionDown,
@@ -49,49 +44,77 @@ var (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
+	startCoord, endCoord, err := findStartAndEnd(lines)
+	util.PanicOnError(err)
+
+	crossings := getCrossings(lines, startCoord)
+	fmt.Println("Detected crossings:", formatCoordsMap(crossings))
+
+	graph := buildGraph(lines, crossings, startCoord, endCoord)
+	fmt.Println("Graph:")
+	printGraph(graph)
+
+	path, err := getLongestPathToEnd(graph, indexNodes(graph), startCoord, endCoord, []Coord{},
+		&util.Bitset{})
+	if err != nil {
+		fmt.Println("Path to end isn't found:", err.Error())
+	} else {
+		fmt.Printf("Longest path contains %d nodes: %v\n", len(path), formatCoords(path))
+		fmt.Println("Length:", computePathLength(graph, path))
+	}
+}
+
+func findStartAndEnd(lines []string) (Coord, Coord, error) {
 	rowsTotal := len(lines)
 
 	if rowsTotal < 2 {
-		panic(fmt.Errorf("Input has too little lines: %d", rowsTotal))
+		return Coord{}, Coord{}, fmt.Errorf("Input has too little lines: %d", rowsTotal)
+	}
+	if rowsTotal > math.MaxUint8+1 || len(lines[0]) > math.MaxUint8+1 {
+		return Coord{}, Coord{}, fmt.Errorf("Map %dx%d is too big", rowsTotal, len(lines[0]))
+	}
+
+	for rowIdx, line := range lines {
+		if len(line) != len(lines[0]) {
+			return Coord{}, Coord{}, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1,
+				len(line), len(lines[0]))
+		}
+
+		for colIdx, r := range line {
+			char := string(r)
+			if char != charPath && char != charForest && !slopeChars.Contains(char) {
+				return Coord{}, Coord{}, fmt.Errorf("Unexpected char %q at %d:%d", r, rowIdx+1,
+					colIdx+1)
+			}
+		}
 	}
 
 	startCoordColIdx := strings.Index(lines[0], charPath)
 	if startCoordColIdx == -1 {
-		panic(errors.New("Start corrd isn't found"))
+		return Coord{}, Coord{}, errors.New("Start corrd isn't found")
 	}
 	startCoord := Coord{0, uint8(startCoordColIdx)}
 
 	lastRowIdx := uint8(rowsTotal - 1)
 	endCoordColIdx := strings.Index(lines[lastRowIdx], charPath)
 	if endCoordColIdx == -1 {
-		panic(errors.New("Start corrd isn't found"))
+		return Coord{}, Coord{}, errors.New("End corrd isn't found")
 	}
 	endCoord := Coord{lastRowIdx, uint8(endCoordColIdx)}
 
-	crossings := getCrossings(lines, startCoord)
-	fmt.Println("Detected crossings:", formatCoordsMap(crossings))
-
-	graph := buildGraph(lines, crossings, startCoord, endCoord)
-	fmt.Println("Graph:")
-	printGraph(graph)
-
-	path, err := getLongestPathToEnd(graph, startCoord, endCoord, []Coord{})
-	if err != nil {
-		fmt.Println("Path to end isn't found:", err.Error())
-	} else {
-		fmt.Printf("Longest path contains %d nodes: %v\n", len(path), formatCoords(path))
-		fmt.Println("Length:", computePathLength(graph, path))
-	}
+	return startCoord, endCoord, nil
 }
 
-func getCrossings(lines []string, startCoord Coord) map[Coord]bool {
-	crossings := map[Coord]bool{}
+func getCrossings(lines []string, startCoord Coord) util.Set[Coord] {
+	crossings := util.NewSet[Coord]()
 	coordsToVisit := []Coord{startCoord}
-	visitedCoords := map[Coord]bool{}
-	emptyCoordsSet := map[Coord]bool{}
+	visitedCoords := util.NewSet[Coord]()
+	emptyCoordsSet := util.NewSet[Coord]()
 
 	for len(coordsToVisit) > 0 {
 		coord := coordsToVisit[0]
@@ -99,39 +122,39 @@ func getCrossings(lines []string, startCoord Coord) map[Coord]bool {
 
 		nextAvailableSteps := getNextValidSteps(lines, coord, emptyCoordsSet)
 		if len(nextAvailableSteps) > 2 {
-			crossings[coord] = true
+			crossings.Add(coord)
 		}
 
 		nextValidSteps := getNextValidSteps(lines, coord, visitedCoords)
 		coordsToVisit = append(coordsToVisit, nextValidSteps...)
-		visitedCoords[coord] = true
+		visitedCoords.Add(coord)
 	}
 
 	return crossings
 }
 
-func buildGraph(lines []string, crossings map[Coord]bool, startCoord, endCoord Coord) map[Coord]map[Coord]uint {
+func buildGraph(lines []string, crossings util.Set[Coord], startCoord, endCoord Coord) map[Coord]map[Coord]uint {
 	graph := map[Coord]map[Coord]uint{}
 
-	nodes := maps.Clone(crossings)
-	nodes[startCoord] = true
-	nodes[endCoord] = true
+	nodes := crossings.Clone()
+	nodes.Add(startCoord)
+	nodes.Add(endCoord)
 
 	for node := range nodes {
 		graph[node] = map[Coord]uint{}
 
-		stepsFromCrossing := getNextValidSteps(lines, node, map[Coord]bool{})
+		stepsFromCrossing := getNextValidSteps(lines, node, util.NewSet[Coord]())
 
 		for _, step := range stepsFromCrossing {
 			stepsToClosestCrossing := uint(1)
 			currentCoord := step
-			visitedCoords := map[Coord]bool{node: true}
+			visitedCoords := util.NewSet(node)
 			nextSteps := getNextValidSteps(lines, currentCoord, visitedCoords)
 
 			for len(nextSteps) == 1 {
 				stepsToClosestCrossing++
 
-				visitedCoords[currentCoord] = true
+				visitedCoords.Add(currentCoord)
 
 				currentCoord = nextSteps[0]
 				nextSteps = getNextValidSteps(lines, currentCoord, visitedCoords)
@@ -149,11 +172,8 @@ func buildGraph(lines []string, crossings map[Coord]bool, startCoord, endCoord C
 	return graph
 }
 
-func formatCoordsMap(coords map[Coord]bool) []string {
-	coordsSl := make([]Coord, 0, len(coords))
-	for coord := range coords {
-		coordsSl = append(coordsSl, coord)
-	}
+func formatCoordsMap(coords util.Set[Coord]) []string {
+	coordsSl := coords.Slice()
 	sortCoords(coordsSl)
 
 	return formatCoords(coordsSl)
@@ -195,13 +215,13 @@ func printGraph(graph map[Coord]map[Coord]uint) {
 	}
 }
 
-func getNextValidSteps(lines []string, coord Coord, visitedCoords map[Coord]bool) []Coord {
+func getNextValidSteps(lines []string, coord Coord, visitedCoords util.Set[Coord]) []Coord {
 	currentCoordChar := lines[coord.rowIdx][coord.colIdx : coord.colIdx+1]
 
 	switch {
 	case charForest == currentCoordChar:
 		panic(fmt.Errorf("I am in the forest at %d:%d", coord.rowIdx+1, coord.colIdx+1))
-	case charPath == currentCoordChar, slopeChars[currentCoordChar]:
+	case charPath == currentCoordChar, slopeChars.Contains(currentCoordChar):
 		var nextValidSteps []Coord
 		for _, direction := range allDirections {
 			nextCoord, isValid := getCoordIfValid(lines, int16(coord.rowIdx)+int16(direction.rowiDff),
@@ -219,7 +239,7 @@ func getNextValidSteps(lines []string, coord Coord, visitedCoords map[Coord]bool
 func getCoordIfValid(
 	lines []string,
 	rowIdx, colIdx int16,
-	visitedCoords map[Coord]bool,
+	visitedCoords util.Set[Coord],
 ) (coord Coord, isValid bool) {
 	if rowIdx < 0 || colIdx < 0 {
 		return Coord{}, false
@@ -237,14 +257,34 @@ func getCoordIfValid(
 	}
 	coord = Coord{uint8(rowIdx), uint8(colIdx)}
 
-	return coord, !visitedCoords[coord]
+	return coord, !visitedCoords.Contains(coord)
 }
 
+// indexNodes numbers graph nodes, so sets of them fit in a bitset
+func indexNodes(graph map[Coord]map[Coord]uint) map[Coord]int {
+	nodes := util.MapKeysToSlice(graph)
+	sortCoords(nodes)
+
+	nodeIdxByCoord := make(map[Coord]int, len(nodes))
+	for idx, node := range nodes {
+		nodeIdxByCoord[node] = idx
+	}
+	return nodeIdxByCoord
+}
+
+// getLongestPathToEnd walks all paths from the current node. pathNodes has the indexes of the
+// path nodes and the current one; it's restored before return.
 func getLongestPathToEnd(
 	graph map[Coord]map[Coord]uint,
+	nodeIdxByCoord map[Coord]int,
 	currentCoord, endCoord Coord,
 	path []Coord,
+	pathNodes *util.Bitset,
 ) ([]Coord, error) {
+	currentNodeIdx := nodeIdxByCoord[currentCoord]
+	pathNodes.Set(currentNodeIdx)
+	defer pathNodes.Clear(currentNodeIdx)
+
 	var longestPath []Coord
 	var longestPathLegth uint
 	for nextCoord := range graph[currentCoord] {
@@ -261,10 +301,11 @@ func getLongestPathToEnd(
 			}
 
 		// general case
-		} else if !slices.Contains(path, nextCoord) {
+		} else if !pathNodes.Test(nodeIdxByCoord[nextCoord]) {
 			furtherPath := slices.Clone(path)
 			furtherPath = append(furtherPath, currentCoord)
-			finishedPath, err := getLongestPathToEnd(graph, nextCoord, endCoord, furtherPath)
+			finishedPath, err := getLongestPathToEnd(graph, nodeIdxByCoord, nextCoord, endCoord,
+				furtherPath, pathNodes)
 			if err == nil {
 				pathLength := computePathLength(graph, finishedPath)
 				if pathLength > longestPathLegth {
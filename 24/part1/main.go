@@ -2,15 +2,16 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/geom"
 )
 
 type Hailstone struct {
-	lineIdx                         uint16
-	startX, startY, startZ          uint
-	velocityX, velocityY, velocityZ int
+	lineIdx         uint16
+	start, velocity geom.Vec3
 }
 
 type TestArea struct {
@@ -23,22 +24,16 @@ type TestArea struct {
 var testArea = TestArea{200000000000000, 400000000000000, 200000000000000, 400000000000000}
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
 	hailstones := []Hailstone{}
-	replacer := strings.NewReplacer("@", "", ",", "")
 	for lineIdx, line := range lines {
-		fields := strings.Fields(replacer.Replace(line))
-		hailstones = append(hailstones, Hailstone{
-			lineIdx:   uint16(lineIdx),
-			startX:    util.ParseUintOrPanic(fields[0]),
-			startY:    util.ParseUintOrPanic(fields[1]),
-			startZ:    util.ParseUintOrPanic(fields[2]),
-			velocityX: util.ParseIntOrPanic(fields[3]),
-			velocityY: util.ParseIntOrPanic(fields[4]),
-			velocityZ: util.ParseIntOrPanic(fields[5]),
-		})
+		hailstone, err := parseHailstone(lineIdx, line)
+		util.PanicOnError(err)
+		hailstones = append(hailstones, hailstone)
 	}
 
 	fmt.Printf("%d hailstones are parsed\n", len(hailstones))
@@ -59,32 +54,65 @@ func main() {
 	fmt.Printf("%d hailstone paths crossed in the test area\n", crossesInTestArea)
 }
 
+func parseHailstone(lineIdx int, line string) (Hailstone, error) {
+	if lineIdx > math.MaxUint16 {
+		return Hailstone{}, fmt.Errorf("Line %d: Too many hailstones", lineIdx+1)
+	}
+
+	replacer := strings.NewReplacer("@", "", ",", "")
+	fields := strings.Fields(replacer.Replace(line))
+	if len(fields) != 6 {
+		return Hailstone{}, fmt.Errorf("Line %d: Unexpected number of fields: %d", lineIdx+1,
+			len(fields))
+	}
+
+	starts, err := util.ParseUints(fields[:3])
+	if err != nil {
+		return Hailstone{}, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+	}
+	for _, start := range starts {
+		if start > math.MaxInt {
+			return Hailstone{}, fmt.Errorf("Line %d: Position %d is too big", lineIdx+1, start)
+		}
+	}
+	velocities, err := util.ParseInts(fields[3:])
+	if err != nil {
+		return Hailstone{}, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+	}
+
+	return Hailstone{
+		lineIdx:  uint16(lineIdx),
+		start:    geom.Vec3{X: int(starts[0]), Y: int(starts[1]), Z: int(starts[2])},
+		velocity: geom.Vec3{X: velocities[0], Y: velocities[1], Z: velocities[2]},
+	}, nil
+}
+
 func pathsCrossInTestArea(stone1, stone2 Hailstone) bool {
-	time2divider := stone2.velocityX * stone1.velocityY - stone1.velocityX * stone2.velocityY
+	// paths are crossed in the XY plane, where Z of a cross product is the 2D cross product
+	time2divider := stone2.velocity.Cross(stone1.velocity).Z
 	if time2divider == 0 {
 		fmt.Printf("Stones %d and %d never cross\n", stone1.lineIdx+1, stone2.lineIdx+1)
 		return false
 	}
 
-	time2 := float64(stone1.velocityX * (int(stone2.startY) - int(stone1.startY)) -
-		stone1.velocityY * (int(stone2.startX) - int(stone1.startX))) /
-		float64(time2divider)
+	startDiff := stone2.start.Sub(stone1.start)
+	time2 := float64(stone1.velocity.Cross(startDiff).Z) / float64(time2divider)
 	if time2 < 0 {
 		fmt.Printf("Stones %d and %d crossed in point past of the second stone\n", stone1.lineIdx+1, 
 			stone2.lineIdx+1)
 		return false
 	}
 	
-	time1 := (float64(int(stone2.startX) - int(stone1.startX)) + float64(stone2.velocityX) * time2) /
-		float64(stone1.velocityX)
+	time1 := (float64(startDiff.X) + float64(stone2.velocity.X)*time2) /
+		float64(stone1.velocity.X)
 	if time1 < 0 {
 		fmt.Printf("Stones %d and %d crossed in point past of the first stone\n", stone1.lineIdx+1, 
 			stone2.lineIdx+1)
 		return false
 	}
 	
-	x0 := float64(stone2.startX) + float64(stone2.velocityX) * time2
-	y0 := float64(stone2.startY) + float64(stone2.velocityY) * time2
+	x0 := float64(stone2.start.X) + float64(stone2.velocity.X)*time2
+	y0 := float64(stone2.start.Y) + float64(stone2.velocity.Y)*time2
 	
 	withingTestArea := x0 >= testArea.fromX && x0 <= testArea.toX &&
 		y0 >= testArea.fromY && y0 <= testArea.toY
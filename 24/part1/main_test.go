@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
+)
+
+func FuzzParseHailstone(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			stone, err := parseHailstone(lineIdx, line)
+			if err != nil {
+				return
+			}
+
+			// positions of part 1 are unsigned
+			if stone.start.X < 0 || stone.start.Y < 0 || stone.start.Z < 0 {
+				t.Fatalf("Line %q is parsed with a negative position %v", line, stone.start)
+			}
+
+			// a parsed hailstone is the same when written back in the input format
+			start, velocity := stone.start, stone.velocity
+			formatted := fmt.Sprintf("%d, %d, %d @ %d, %d, %d", start.X, start.Y, start.Z,
+				velocity.X, velocity.Y, velocity.Z)
+			reparsed, err := parseHailstone(lineIdx, formatted)
+			if err != nil || reparsed != stone {
+				t.Errorf("Line %q is parsed as %v, but %q as %v, %v", line, stone, formatted,
+					reparsed, err)
+			}
+		})
+}
@@ -8,12 +8,12 @@ import (
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/geom"
 )
 
 type Hailstone struct {
-	line                            uint16
-	xStart, yStart, zStart          int
-	xVelocity, yVelocity, zVelocity int
+	line            uint16
+	start, velocity geom.Vec3
 }
 
 const (
@@ -21,6 +21,8 @@ const (
 )
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
@@ -29,27 +31,18 @@ func main() {
 	stonesByYVelocity := make(map[int][]Hailstone, len(lines))
 	stonesByZVelocity := make(map[int][]Hailstone, len(lines))
 
-	replacer := strings.NewReplacer("@", "", ",", "")
 	for lineIdx, line := range lines {
-		fields := strings.Fields(replacer.Replace(line))
-		hailstone := Hailstone{
-			line:      uint16(lineIdx),
-			xStart:    util.ParseIntOrPanic(fields[0]),
-			yStart:    util.ParseIntOrPanic(fields[1]),
-			zStart:    util.ParseIntOrPanic(fields[2]),
-			xVelocity: util.ParseIntOrPanic(fields[3]),
-			yVelocity: util.ParseIntOrPanic(fields[4]),
-			zVelocity: util.ParseIntOrPanic(fields[5]),
-		}
+		hailstone, err := parseHailstone(lineIdx, line)
+		util.PanicOnError(err)
 		hailstones = append(hailstones, hailstone)
 
-		vx := hailstone.xVelocity
+		vx := hailstone.velocity.X
 		stonesByXVelocity[vx] = append(stonesByXVelocity[vx], hailstone)
 
-		vy := hailstone.yVelocity
+		vy := hailstone.velocity.Y
 		stonesByYVelocity[vy] = append(stonesByYVelocity[vy], hailstone)
 
-		vz := hailstone.zVelocity
+		vz := hailstone.velocity.Z
 		stonesByZVelocity[vz] = append(stonesByZVelocity[vz], hailstone)
 	}
 
@@ -57,38 +50,38 @@ func main() {
 	// fmt.Println(hailstones)
 
 	rockXVelocities := detectRockVelocities(stonesByXVelocity, func(h Hailstone) int {
-		return h.xStart
+		return h.start.X
 	})
 	if len(rockXVelocities) == 0 {
 		panic("No common rock X velocities detected")
 	}
-	fmt.Println("Rock X velocities detected:", util.MapKeysToSortedSlice(rockXVelocities))
+	fmt.Println("Rock X velocities detected:", util.SortedSetItems(rockXVelocities))
 
 	rockYVelocities := detectRockVelocities(stonesByYVelocity, func(h Hailstone) int {
-		return h.yStart
+		return h.start.Y
 	})
 	if len(rockYVelocities) == 0 {
 		panic("No common rock Y velocities detected")
 	}
-	fmt.Println("Rock Y velocities detected:", util.MapKeysToSortedSlice(rockYVelocities))
+	fmt.Println("Rock Y velocities detected:", util.SortedSetItems(rockYVelocities))
 
 	rockZVelocities := detectRockVelocities(stonesByZVelocity, func(h Hailstone) int {
-		return h.zStart
+		return h.start.Z
 	})
 	if len(rockZVelocities) == 0 {
 		panic("No common rock Z velocities detected")
 	}
-	fmt.Println("Rock Z velocities detected:", util.MapKeysToSortedSlice(rockZVelocities))
+	fmt.Println("Rock Z velocities detected:", util.SortedSetItems(rockZVelocities))
 
-	var xStart, yStart, zStart int
+	var rockStart geom.Vec3
 velocityLoop:
 	for xVelocity := range rockXVelocities {
 		for yVelocity := range rockYVelocities {
 			for zVelocity := range rockZVelocities {
 				for _, hailstone := range hailstones {
 					// fmt.Printf("Checking hailstone #%d\n", hailstoneIdx+1)
-					xStart, yStart, zStart, err = findFirstMatchingStartCoordinates(hailstones,
-						hailstone, xVelocity, yVelocity, zVelocity)
+					rockStart, err = findFirstMatchingStartCoordinates(hailstones, hailstone,
+						geom.Vec3{X: xVelocity, Y: yVelocity, Z: zVelocity})
 					if err == nil {
 						break velocityLoop
 					}
@@ -100,8 +93,32 @@ velocityLoop:
 	if err != nil {
 		panic(fmt.Errorf("Failed to find matching rock start coordinates: %s", err.Error()))
 	}
-	fmt.Printf("Rock start coordinates: %d,%d,%d. Sum: %d\n", xStart, yStart, zStart,
-		xStart+yStart+zStart)
+	fmt.Printf("Rock start coordinates: %v. Sum: %d\n", rockStart,
+		rockStart.X+rockStart.Y+rockStart.Z)
+}
+
+func parseHailstone(lineIdx int, line string) (Hailstone, error) {
+	if lineIdx > math.MaxUint16 {
+		return Hailstone{}, fmt.Errorf("Line %d: Too many hailstones", lineIdx+1)
+	}
+
+	replacer := strings.NewReplacer("@", "", ",", "")
+	fields := strings.Fields(replacer.Replace(line))
+	if len(fields) != 6 {
+		return Hailstone{}, fmt.Errorf("Line %d: Unexpected number of fields: %d", lineIdx+1,
+			len(fields))
+	}
+
+	values, err := util.ParseInts(fields)
+	if err != nil {
+		return Hailstone{}, fmt.Errorf("Line %d: %w", lineIdx+1, err)
+	}
+
+	return Hailstone{
+		line:     uint16(lineIdx),
+		start:    geom.Vec3{X: values[0], Y: values[1], Z: values[2]},
+		velocity: geom.Vec3{X: values[3], Y: values[4], Z: values[5]},
+	}, nil
 }
 
 func abs(i int) int {
@@ -139,22 +156,22 @@ func primeFactors(n uint) []uint {
 	return factors
 }
 
-func getProducts(nums []uint) map[uint]bool {
+func getProducts(nums []uint) util.Set[uint] {
 	numsLen := uint(len(nums))
 	if numsLen == 0 {
-		return map[uint]bool{}
+		return util.NewSet[uint]()
 	}
 	if numsLen == 1 {
-		return map[uint]bool{nums[0]: true}
+		return util.NewSet(nums[0])
 	}
 	if numsLen == 2 {
-		return map[uint]bool{nums[0] * nums[1]: true}
+		return util.NewSet(nums[0] * nums[1])
 	}
 	if numsLen > 64 {
 		panic(fmt.Errorf("Too high number of nums: %d. Max: 64", numsLen))
 	}
 
-	products := make(map[uint]bool, numsLen)
+	products := util.NewSet[uint]()
 	maxBitMaskValue := uint(math.Pow(2, float64(numsLen)) - 1)
 	for bitMask := uint(0); bitMask <= maxBitMaskValue; bitMask++ {
 		if bits.OnesCount(bitMask) > 1 {
@@ -166,14 +183,14 @@ func getProducts(nums []uint) map[uint]bool {
 				}
 			}
 
-			products[product] = true
+			products.Add(product)
 		}
 	}
 
 	return products
 }
 
-func getFactors(n uint) map[uint]bool {
+func getFactors(n uint) util.Set[uint] {
 	primeFactors := primeFactors(n)
 
 	primeFactorsExtended := make([]uint, 0, len(primeFactors)+1)
@@ -181,17 +198,16 @@ func getFactors(n uint) map[uint]bool {
 	primeFactorsExtended = append(primeFactorsExtended, primeFactors...)
 
 	products := getProducts(primeFactorsExtended)
-	products[n] = true
+	products.Add(n)
 
 	return products
 }
 
-func getPossibleVelocities(hailstoneVelocity int, diffFactors map[uint]bool) map[int]bool {
-	possibleVelocities := make(map[int]bool, len(diffFactors))
+func getPossibleVelocities(hailstoneVelocity int, diffFactors util.Set[uint]) util.Set[int] {
+	possibleVelocities := util.NewSet[int]()
 	for factor := range diffFactors {
 		iFactor := int(factor)
-		possibleVelocities[hailstoneVelocity+iFactor] = true
-		possibleVelocities[hailstoneVelocity-iFactor] = true
+		possibleVelocities.Add(hailstoneVelocity+iFactor, hailstoneVelocity-iFactor)
 	}
 
 	return possibleVelocities
@@ -200,7 +216,7 @@ func getPossibleVelocities(hailstoneVelocity int, diffFactors map[uint]bool) map
 func detectRockVelocities(
 	stonesByVelocity map[int][]Hailstone,
 	stoneToStart func(Hailstone) int,
-) map[int]bool {
+) util.Set[int] {
 	pairCountByPossibleVelocity := make(map[int]uint)
 	stonePairWithSameVelocityCount := uint(0)
 	for velocity, stones := range stonesByVelocity {
@@ -215,21 +231,21 @@ func detectRockVelocities(
 
 		diffFactors := getFactors(startDiff)
 		// fmt.Printf("Diff factors[%d]:\n", len(diffFactors))
-		// fmt.Println(util.SetToSlice(diffFactors))
+		// fmt.Println(util.SortedSetItems(diffFactors))
 
 		possibleRockVelocities := getPossibleVelocities(velocity, diffFactors)
 		// fmt.Printf("Possible rock velocities[%d]:\n", len(possibleRockVelocities))
-		// fmt.Println(util.SetToSlice(possibleRockVelocities))
+		// fmt.Println(util.SortedSetItems(possibleRockVelocities))
 
 		for velocity := range possibleRockVelocities {
 			pairCountByPossibleVelocity[velocity]++
 		}
 	}
 
-	commonVelocities := map[int]bool{}
+	commonVelocities := util.NewSet[int]()
 	for velocity, pairCount := range pairCountByPossibleVelocity {
 		if pairCount == stonePairWithSameVelocityCount {
-			commonVelocities[velocity] = true
+			commonVelocities.Add(velocity)
 		}
 	}
 
@@ -239,15 +255,13 @@ func detectRockVelocities(
 func findFirstMatchingStartCoordinates(
 	hailstones []Hailstone,
 	baseHailstone Hailstone,
-	rockXVelocity, rockYVelocity, rockZVelocity int,
-) (xStart, yStart, zStart int, err error) {
+	rockVelocity geom.Vec3,
+) (geom.Vec3, error) {
 	hailstonesCount := uint(len(hailstones))
 	stoneLineByCollisionTime := make(map[float64]uint16)
 
 	for t := uint(0); t < simulationDuration; t++ {
-		rockXStart := baseHailstone.xStart + int(t)*(baseHailstone.xVelocity-rockXVelocity)
-		rockYStart := baseHailstone.yStart + int(t)*(baseHailstone.yVelocity-rockYVelocity)
-		rockZStart := baseHailstone.zStart + int(t)*(baseHailstone.zVelocity-rockZVelocity)
+		rockStart := baseHailstone.start.Add(baseHailstone.velocity.Sub(rockVelocity).Scale(int(t)))
 
 		clear(stoneLineByCollisionTime)
 
@@ -260,17 +274,17 @@ func findFirstMatchingStartCoordinates(
 			// movement from same coordinates; collision with all hailstones is guaranteed by the
 			// puzzle description, so current hailstone start coordinates are rock start coordinates
 			// as well!
-			if stone.xVelocity == rockXVelocity && stone.yVelocity == rockYVelocity &&
-				stone.zVelocity == rockZVelocity {
-				return stone.xStart, stone.yStart, stone.zStart, nil
+			if stone.velocity == rockVelocity {
+				return stone.start, nil
 			}
 
-			// fmt.Printf("%d. xDiff=%d yDiff=%d zDiff=%d\n", t, stone.xStart-rockXStart, stone.yStart-rockYStart,
-			// 	stone.zStart-rockZStart)
+			startDiff := stone.start.Sub(rockStart)
+			// fmt.Printf("%d. diff=%v\n", t, startDiff)
 
-			xCollisionTime := float64(stone.xStart-rockXStart) / float64(rockXVelocity-stone.xVelocity)
-			yCollisionTime := float64(stone.yStart-rockYStart) / float64(rockYVelocity-stone.yVelocity)
-			zCollisionTime := float64(stone.zStart-rockZStart) / float64(rockZVelocity-stone.zVelocity)
+			velocityDiff := rockVelocity.Sub(stone.velocity)
+			xCollisionTime := float64(startDiff.X) / float64(velocityDiff.X)
+			yCollisionTime := float64(startDiff.Y) / float64(velocityDiff.Y)
+			zCollisionTime := float64(startDiff.Z) / float64(velocityDiff.Z)
 
 			collisionTimes := []float64{xCollisionTime, yCollisionTime, zCollisionTime}
 			// fmt.Printf("t: %v times: %v\n", t, collisionTimes)
@@ -309,9 +323,9 @@ func findFirstMatchingStartCoordinates(
 		}
 
 		if uint(len(stoneLineByCollisionTime)) == hailstonesCount-1 {
-			return rockXStart, rockYStart, rockZStart, nil
+			return rockStart, nil
 		}
 	}
 
-	return 0, 0, 0, errors.New("No matching start and velocity found")
+	return geom.Vec3{}, errors.New("No matching start and velocity found")
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"slices"
 	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util/testutil"
 )
 
 func TestPrimeFactors(t *testing.T) {
@@ -65,3 +67,23 @@ func TestGetProducst(t *testing.T) {
 		})
 	}
 }
+
+func FuzzParseHailstone(f *testing.F) {
+	testutil.FuzzEachLine(f, []string{"../sample.txt"},
+		func(t *testing.T, lineIdx int, line string) {
+			stone, err := parseHailstone(lineIdx, line)
+			if err != nil {
+				return
+			}
+
+			// a parsed hailstone is the same when written back in the input format
+			start, velocity := stone.start, stone.velocity
+			formatted := fmt.Sprintf("%d, %d, %d @ %d, %d, %d", start.X, start.Y, start.Z,
+				velocity.X, velocity.Y, velocity.Z)
+			reparsed, err := parseHailstone(lineIdx, formatted)
+			if err != nil || reparsed != stone {
+				t.Errorf("Line %q is parsed as %v, but %q as %v, %v", line, stone, formatted,
+					reparsed, err)
+			}
+		})
+}
@@ -2,13 +2,12 @@ package main
 
 import (
 	"fmt"
-	"maps"
 	"math"
-	"math/big"
 	"slices"
 	"strings"
 
 	"github.com/efulmo/advent-of-code-2023/util"
+	"github.com/efulmo/advent-of-code-2023/util/unionfind"
 )
 
 const (
@@ -36,41 +35,13 @@ func newEdge(part1, part2 string) Edge {
 }
 
 func main() {
+	defer util.ReportAllocs()
+
 	lines, err := util.ReadInputFile()
 	util.PanicOnError(err)
 
-	// build edges map
-	connectedPartsByName := make(map[string]map[string]bool, len(lines))
-	for _, line := range lines {
-		lineParts := strings.Split(line, ":")
-		partName := lineParts[0]
-		connectedPartNames := strings.Fields(lineParts[1])
-		connectedPartsMap := make(map[string]bool, len(connectedPartNames))
-		for _, partName := range connectedPartNames {
-			connectedPartsMap[partName] = true
-		}
-
-		existingConnectedParts := connectedPartsByName[partName]
-		if existingConnectedParts == nil {
-			connectedPartsByName[partName] = connectedPartsMap
-		} else {
-			for partToConnect := range connectedPartsMap {
-				existingConnectedParts[partToConnect] = true
-			}
-		}
-
-		for connectedPartName := range connectedPartsMap {
-			reverseConnectedParts := connectedPartsByName[connectedPartName]
-			if reverseConnectedParts == nil {
-				reverseConnectedParts = map[string]bool{
-					partName: true,
-				}
-				connectedPartsByName[connectedPartName] = reverseConnectedParts
-			} else {
-				reverseConnectedParts[partName] = true
-			}
-		}
-	}
+	connectedPartsByName, err := parseConnections(lines)
+	util.PanicOnError(err)
 
 	fmt.Println("Parts parsed:", len(connectedPartsByName))
 	// for partName, connectedParts := range connectedPartsByName {
@@ -115,14 +86,14 @@ collectEdgeUsageStatsLoop:
 	}
 
 	// build edge stats
-	edgesByUsageCount := make(map[uint]map[Edge]bool)
+	edgesByUsageCount := make(map[uint]util.Set[Edge])
 	for edge, count := range edgeCountByEdge {
 		edges := edgesByUsageCount[count]
 		if edges == nil {
-			edges = make(map[Edge]bool)
+			edges = util.NewSet[Edge]()
 			edgesByUsageCount[count] = edges
 		}
-		edges[edge] = true
+		edges.Add(edge)
 	}
 
 	edgeCounts := util.MapKeysToSortedSlice(edgesByUsageCount)
@@ -131,38 +102,33 @@ collectEdgeUsageStatsLoop:
 	fmt.Println("Edges by usage:")
 	for i := len(edgeCounts) - 1; i >= 0; i-- {
 		count := edgeCounts[i]
-		edges := util.MapKeysToSlice(edgesByUsageCount[count])
-		slices.SortFunc(edges, compareEdgesByStringForm)
+		edges := edgesByUsageCount[count].SortedFunc(compareEdgesByStringForm)
 
 		fmt.Printf("%d: %v\n", count, edges)
 	}
 
 	// find edges to delete
-	edgesToDelete := make(map[Edge]bool)
+	edgesToDelete := util.NewSet[Edge]()
 
 selectingMiddleEdges:
 	for i := len(edgeCounts) - 1; i >= 0; i-- {
 		count := edgeCounts[i]
 		edges := edgesByUsageCount[count]
 
-		for edge := range edges {
-			edgesToDelete[edge] = true
+		for _, edge := range edges.SortedFunc(compareEdgesByStringForm) {
+			edgesToDelete.Add(edge)
 			if len(edgesToDelete) == edgeCountToDelete {
 				break selectingMiddleEdges
 			}
 		}
 	}
-	edgesToDeleteSl := util.MapKeysToSlice(edgesToDelete)
-	slices.SortFunc(edgesToDeleteSl, compareEdgesByStringForm)
-	fmt.Println("Detected middle edges to delete:", edgesToDeleteSl)
+	fmt.Println("Detected middle edges to delete:",
+		edgesToDelete.SortedFunc(compareEdgesByStringForm))
 
 	// delete middle edges
 	for edge := range edgesToDelete {
-		connectedParts := connectedPartsByName[edge.smallerPart]
-		delete(connectedParts, edge.biggerPart)
-
-		reverseConnectedParts := connectedPartsByName[edge.biggerPart]
-		delete(reverseConnectedParts, edge.smallerPart)
+		connectedPartsByName[edge.smallerPart].Remove(edge.biggerPart)
+		connectedPartsByName[edge.biggerPart].Remove(edge.smallerPart)
 	}
 
 	// detect clusters
@@ -175,27 +141,71 @@ selectingMiddleEdges:
 	for clusterId, parts := range partsByClusterId {
 		partsCount := uint(len(parts))
 		fmt.Printf("Cluster %d has %d parts\n", clusterId, partsCount)
-		fmt.Println(util.MapKeysToSortedSlice(parts))
+		fmt.Println(util.SortedSetItems(parts))
 
 		clusterSizeProduct *= partsCount
 	}
 	fmt.Println("Cluster size product:", clusterSizeProduct)
 }
 
+func parseConnections(lines []string) (map[string]util.Set[string], error) {
+	// build edges map
+	connectedPartsByName := make(map[string]util.Set[string], len(lines))
+	for lineIdx, line := range lines {
+		lineParts := strings.Split(line, ":")
+		if len(lineParts) != 2 {
+			return nil, fmt.Errorf("Line %d: Unexpected format: %s", lineIdx+1, line)
+		}
+		partName := lineParts[0]
+		if len(partName) == 0 || strings.ContainsAny(partName, " \t") {
+			return nil, fmt.Errorf("Line %d: Unexpected part name <%s>", lineIdx+1, partName)
+		}
+		connectedPartNames := strings.Fields(lineParts[1])
+		if len(connectedPartNames) == 0 {
+			return nil, fmt.Errorf("Line %d: Part %s has no connections", lineIdx+1, partName)
+		}
+		connectedPartsSet := util.NewSet[string]()
+		for _, connectedPartName := range connectedPartNames {
+			if connectedPartName == partName {
+				return nil, fmt.Errorf("Line %d: Part %s is connected to itself", lineIdx+1, partName)
+			}
+			connectedPartsSet.Add(connectedPartName)
+		}
+
+		existingConnectedParts := connectedPartsByName[partName]
+		if existingConnectedParts == nil {
+			connectedPartsByName[partName] = connectedPartsSet
+		} else {
+			existingConnectedParts.Add(connectedPartsSet.Slice()...)
+		}
+
+		for connectedPartName := range connectedPartsSet {
+			reverseConnectedParts := connectedPartsByName[connectedPartName]
+			if reverseConnectedParts == nil {
+				connectedPartsByName[connectedPartName] = util.NewSet(partName)
+			} else {
+				reverseConnectedParts.Add(partName)
+			}
+		}
+	}
+
+	return connectedPartsByName, nil
+}
+
 func compareEdgesByStringForm(e1, e2 Edge) int {
 	return strings.Compare(e1.String(), e2.String())
 }
 
 func findShortestPathDijkstra(
 	startPart, endPart string,
-	connectedPartsByName map[string]map[string]bool,
+	connectedPartsByName map[string]util.Set[string],
 ) []string {
 	if startPart == endPart {
 		return []string{startPart}
 	}
 
 	partsToVisit := []string{startPart}
-	visitedParts := map[string]bool{}
+	visitedParts := util.NewSet[string]()
 	pathFromStartToPart := map[string][]string{}
 
 	for len(partsToVisit) > 0 {
@@ -210,7 +220,7 @@ func findShortestPathDijkstra(
 
 		connectedParts := connectedPartsByName[currentPart]
 		for connectedPart := range connectedParts {
-			if visitedParts[connectedPart] {
+			if visitedParts.Contains(connectedPart) {
 				continue
 			}
 
@@ -224,60 +234,26 @@ func findShortestPathDijkstra(
 			partsToVisit = append(partsToVisit, connectedPart)
 		}
 
-		visitedParts[currentPart] = true
+		visitedParts.Add(currentPart)
 	}
 
 	return append(pathFromStartToPart[endPart], endPart)
 }
 
-func detectClusters(connectedPartsByName map[string]map[string]bool) map[uint]map[string]bool {
-	if len(connectedPartsByName) == 0 {
-		return map[uint]map[string]bool{}
-	}
-
-	partsOutsideClusters := make(map[string]bool)
-	for part := range connectedPartsByName {
-		partsOutsideClusters[part] = true
-	}
-
-	clusterIdByPart := make(map[string]uint)
-	currentClusterId := uint(1)
-
-	for len(partsOutsideClusters) > 0 {
-		firstClusterPart, _ := util.GetAnyMapKey(partsOutsideClusters)
-
-		fmt.Printf("New cluster ID=%d is detected\n", currentClusterId)
-		clusterIdByPart[firstClusterPart] = currentClusterId
-		delete(partsOutsideClusters, firstClusterPart)
-
-		partsToVisit := maps.Clone(connectedPartsByName[firstClusterPart])
-		for len(partsToVisit) > 0 {
-			part, _ := util.GetAnyMapKey(partsToVisit)
-			delete(partsToVisit, part)
-
-			partClusterId := clusterIdByPart[part]
-			if partClusterId != 0 {
-				continue
-			}
-
-			clusterIdByPart[part] = currentClusterId
-			delete(partsOutsideClusters, part)
-
-			maps.Copy(partsToVisit, connectedPartsByName[part])
+func detectClusters(connectedPartsByName map[string]util.Set[string]) map[uint]util.Set[string] {
+	clusters := unionfind.NewKeyed[string]()
+	for _, part := range util.MapKeysToSortedSlice(connectedPartsByName) {
+		clusters.Add(part)
+		for connectedPart := range connectedPartsByName[part] {
+			clusters.Union(part, connectedPart)
 		}
-
-		currentClusterId++
 	}
 
-	partsByClusterId := make(map[uint]map[string]bool)
-	for part, clusterId := range clusterIdByPart {
-		parts := partsByClusterId[clusterId]
-		if parts == nil {
-			parts = make(map[string]bool)
-		}
-
-		parts[part] = true
-		partsByClusterId[clusterId] = parts
+	partsByClusterId := make(map[uint]util.Set[string], clusters.SetsTotal())
+	for clusterIdx, parts := range clusters.Components() {
+		clusterId := uint(clusterIdx + 1)
+		fmt.Printf("New cluster ID=%d is detected\n", clusterId)
+		partsByClusterId[clusterId] = util.NewSet(parts...)
 	}
 
 	return partsByClusterId
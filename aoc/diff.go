@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/efulmo/advent-of-code-2023/gen"
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// diffInput is an input all implementations of a part are run on
+type diffInput struct {
+	path string
+	// description tells how to get the input again
+	description string
+	isGenerated bool
+}
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	inputPath := flags.String("input", "", "input file to compare implementations on. "+
+		"Generated inputs are used if not set")
+	runs := flags.Uint("runs", 10, "number of generated inputs")
+	size := flags.Uint("size", 0, "size of generated inputs. 0 means default")
+	seed := flags.Int64("seed", 0, "seed of the first generated input; every next input uses "+
+		"the next seed. 0 means a time-based one")
+	timeout := flags.Duration("timeout", time.Minute, "max time a single run may take")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc diff [flags] [year] <day> [part]")
+		fmt.Fprintln(flags.Output(), "Runs all implementations of the day parts on the same inputs "+
+			"and reports inputs they disagree on")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	year, dayAndPartArgs := parseYear(flags.Args())
+	day, part, err := parseDayAndPart(dayAndPartArgs)
+	if err != nil {
+		flags.Usage()
+		return err
+	}
+	if year != legacyLayoutYear && *inputPath == "" {
+		return fmt.Errorf("Inputs can be generated for %d puzzles only; pass an input",
+			legacyLayoutYear)
+	}
+
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+	allSolvers, err := loadSolvers(moduleRoot)
+	if err != nil {
+		return err
+	}
+
+	solversByPart := map[uint][]solver{}
+	for _, s := range findSolvers(allSolvers, year, day, part) {
+		solversByPart[s.part] = append(solversByPart[s.part], s)
+	}
+	for p, partSolvers := range solversByPart {
+		if len(partSolvers) < 2 {
+			delete(solversByPart, p)
+		}
+	}
+	if len(solversByPart) == 0 {
+		return fmt.Errorf("Day %d of %d has no parts with multiple implementations", day, year)
+	}
+
+	binaries, err := newSolverBinaries(moduleRoot)
+	if err != nil {
+		return err
+	}
+	defer binaries.close()
+
+	// all solvers are built before the first run, so build errors are reported early
+	for _, partSolvers := range solversByPart {
+		for _, s := range partSolvers {
+			if _, err := binaries.get(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	var inputs []diffInput
+	if *inputPath != "" {
+		inputs = append(inputs, diffInput{*inputPath, *inputPath, false})
+	} else {
+		inputs, err = generateDiffInputs(day, *size, *seed, *runs)
+		if err != nil {
+			return err
+		}
+	}
+
+	var disagreementsCount uint
+	for inputIdx, input := range inputs {
+		inputAgreed := true
+		for _, p := range util.MapKeysToSortedSlice(solversByPart) {
+			agreed := diffOnInput(solversByPart[p], binaries, input, *timeout)
+			inputAgreed = inputAgreed && agreed
+			if !agreed {
+				disagreementsCount++
+			}
+		}
+
+		if inputAgreed {
+			fmt.Printf("Input %d/%d: implementations agree\n", inputIdx+1, len(inputs))
+			if input.isGenerated {
+				os.Remove(input.path)
+			}
+		}
+	}
+
+	if len(inputs) > 0 && inputs[0].isGenerated {
+		// only the directory of generated inputs is removed; it's kept if some inputs are left
+		os.Remove(filepath.Dir(inputs[0].path))
+	}
+
+	if disagreementsCount > 0 {
+		return fmt.Errorf("%d disagreements found on %d inputs", disagreementsCount, len(inputs))
+	}
+	fmt.Printf("All implementations agree on %d inputs\n", len(inputs))
+	return nil
+}
+
+// diffOnInput runs all solvers on the input and reports if their answers differ or some of them
+// fail
+func diffOnInput(
+	partSolvers []solver,
+	binaries *solverBinaries,
+	input diffInput,
+	timeout time.Duration,
+) bool {
+	answers := make([]string, len(partSolvers))
+	agreed := true
+	for i, s := range partSolvers {
+		run, err := s.run(context.Background(), binaries, input.path, timeout)
+		answer := run.answer
+		if err != nil {
+			answer = "failed: " + err.Error()
+			agreed = false
+		}
+		answers[i] = answer
+		agreed = agreed && answer == answers[0]
+	}
+
+	if !agreed {
+		first := partSolvers[0]
+		fmt.Printf("Day %d part %d of %d implementations disagree on input %s\n", first.day,
+			first.part, first.year, input.description)
+		for i, s := range partSolvers {
+			fmt.Printf("  %s: %s\n", s, answers[i])
+		}
+	}
+	return agreed
+}
+
+// generateDiffInputs writes inputs generated with consecutive seeds to a new temporary directory
+func generateDiffInputs(day, size uint, seed int64, runs uint) ([]diffInput, error) {
+	generator, err := gen.Get(day)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = generator.DefaultSize
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	inputsDir, err := os.MkdirTemp("", fmt.Sprintf("aoc-diff-%02d-", day))
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]diffInput, 0, runs)
+	for i := int64(0); i < int64(runs); i++ {
+		input, err := generator.Generate(size, seed+i)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(inputsDir, fmt.Sprintf("size%d-seed%d.txt", size, seed+i))
+		if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, diffInput{
+			path:        path,
+			description: fmt.Sprintf("%s (aoc gen -size %d -seed %d %d)", path, size, seed+i, day),
+			isGenerated: true,
+		})
+	}
+
+	return inputs, nil
+}
+
+// firstYear is the year of the first Advent of Code
+const firstYear = 2015
+
+// parseYear strips the leading year argument. The legacy layout year is returned if the first
+// argument isn't a 4-digit number of an Advent of Code year, so 0012 is a day like 12 is.
+func parseYear(args []string) (uint, []string) {
+	if len(args) == 0 || len(args[0]) != 4 {
+		return legacyLayoutYear, args
+	}
+
+	year, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil || year < firstYear {
+		return legacyLayoutYear, args
+	}
+	return uint(year), args[1:]
+}
+
+// parseDayAndPart parses "<day> [part]" arguments. Part 0 means all parts.
+func parseDayAndPart(args []string) (uint, uint, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return 0, 0, errors.New("Day and an optional part are expected")
+	}
+
+	day, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid day <%s>: %w", args[0], err)
+	}
+
+	var part uint64
+	if len(args) == 2 {
+		part, err = strconv.ParseUint(strings.TrimPrefix(args[1], "part"), 10, 0)
+		if err != nil || part < 1 || part > 2 {
+			return 0, 0, fmt.Errorf("Invalid part <%s>", args[1])
+		}
+	}
+
+	return uint(day), uint(part), nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/efulmo/advent-of-code-2023/gen"
+)
+
+func runGen(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	size := flags.Uint("size", 0, "size of the input; its meaning depends on the day. 0 means default")
+	seed := flags.Int64("seed", 0, "seed of the random generator. 0 means a time-based one")
+	outputPath := flags.String("o", "", "file to write the input to instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc gen [flags] <day>")
+		flags.PrintDefaults()
+		printGenDays(flags.Output())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("Exactly one day is expected")
+	}
+
+	day, err := strconv.ParseUint(flags.Arg(0), 10, 0)
+	if err != nil {
+		return fmt.Errorf("Invalid day <%s>: %w", flags.Arg(0), err)
+	}
+	generator, err := gen.Get(uint(day))
+	if err != nil {
+		return err
+	}
+
+	if *size == 0 {
+		*size = generator.DefaultSize
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+		fmt.Fprintln(os.Stderr, "Seed:", *seed)
+	}
+
+	input, err := generator.Generate(*size, *seed)
+	if err != nil {
+		return err
+	}
+
+	if *outputPath == "" {
+		_, err = fmt.Print(input)
+		return err
+	}
+	return os.WriteFile(*outputPath, []byte(input), 0644)
+}
+
+func printGenDays(w io.Writer) {
+	fmt.Fprintln(w, "Days:")
+	for _, day := range gen.Days() {
+		generator, _ := gen.Get(day)
+		fmt.Fprintf(w, "  %2d  size is %s; default %d\n", day, generator.SizeDescription,
+			generator.DefaultSize)
+	}
+}
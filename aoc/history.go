@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultHistoryPath is relative to the module root
+var defaultHistoryPath = filepath.Join(".aoc", "history.jsonl")
+
+// historyRecord describes a single solver run. Records are stored as JSON lines.
+type historyRecord struct {
+	Time time.Time `json:"time"`
+	// Year is 0 in records made before multiple years were supported; those are of the legacy
+	// layout year
+	Year   uint   `json:"year,omitempty"`
+	Day    uint   `json:"day"`
+	Part   uint   `json:"part"`
+	Solver string `json:"solver"`
+	// Input describes where the input came from; inputs are told apart by InputHash only
+	Input     string        `json:"input"`
+	InputHash string        `json:"inputHash"`
+	Answer    string        `json:"answer,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	// AllocBytes and AllocObjects are allocations the solver reported at exit; 0 in records made
+	// before solvers reported them
+	AllocBytes   uint64 `json:"allocBytes,omitempty"`
+	AllocObjects uint64 `json:"allocObjects,omitempty"`
+	// PeakRSSKiB is the peak resident memory of the solver process. Unlike allocations, it includes
+	// memory of the Go runtime and memory the GC freed but didn't return to the system. The JSON
+	// name is kept, so records made before the field was renamed still load.
+	PeakRSSKiB int64  `json:"maxRssKiB,omitempty"`
+	Revision   string `json:"revision"`
+}
+
+// historyStore appends records to a file and reads them back
+type historyStore struct {
+	path     string
+	revision string
+
+	mu sync.Mutex
+}
+
+func newHistoryStore(moduleRoot, path string) *historyStore {
+	if path == "" {
+		path = filepath.Join(moduleRoot, defaultHistoryPath)
+	}
+	return &historyStore{path: path, revision: gitRevision(moduleRoot)}
+}
+
+// record appends the run to the store. It returns the answer previously recorded for the same
+// day part and input if it differs from the new one.
+func (h *historyStore) record(
+	s solver,
+	input, inputPath string,
+	run solverRun,
+	runErr error,
+) (changedAnswer string, err error) {
+	inputHash, err := hashFile(inputPath)
+	if err != nil {
+		return "", err
+	}
+
+	rec := historyRecord{
+		Time:         time.Now().UTC(),
+		Year:         s.year,
+		Day:          s.day,
+		Part:         s.part,
+		Solver:       s.String(),
+		Input:        input,
+		InputHash:    inputHash,
+		Answer:       run.answer,
+		Duration:     run.duration,
+		AllocBytes:   run.allocBytes,
+		AllocObjects: run.allocObjects,
+		PeakRSSKiB:   run.peakRSSKiB,
+		Revision:     h.revision,
+	}
+	if runErr != nil {
+		rec.Error = runErr.Error()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records, err := h.load()
+	if err != nil {
+		return "", err
+	}
+	if rec.Error == "" {
+		if prevAnswer := lastAnswer(records, rec.key()); prevAnswer != "" &&
+			prevAnswer != rec.Answer {
+			changedAnswer = prevAnswer
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+
+	return changedAnswer, nil
+}
+
+// load returns all records in the order they were recorded. A missing store has no records.
+func (h *historyStore) load() ([]historyRecord, error) {
+	file, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []historyRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for lineIdx := 1; scanner.Scan(); lineIdx++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var rec historyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s: Line %d: %w", h.path, lineIdx, err)
+		}
+		if rec.Year == 0 {
+			rec.Year = legacyLayoutYear
+		}
+		records = append(records, rec)
+	}
+
+	return records, scanner.Err()
+}
+
+// lastAnswer returns the last successfully recorded answer for the day part and input
+func lastAnswer(records []historyRecord, key historyKey) string {
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if rec.key() == key && rec.Error == "" {
+			return rec.Answer
+		}
+	}
+	return ""
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// gitRevision returns the short hash of HEAD with "-dirty" suffix if there are uncommitted
+// changes. An unknown revision is returned as "unknown".
+func gitRevision(moduleRoot string) string {
+	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = moduleRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "unknown"
+	}
+	revision := strings.TrimSpace(string(output))
+
+	cmd = exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = moduleRoot
+	if output, err := cmd.Output(); err == nil && len(strings.TrimSpace(string(output))) > 0 {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	historyPath := flags.String("history", "", "file runs are recorded to. "+
+		"Defaults to "+defaultHistoryPath+" in the module root")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc history [flags] [year] [day [part]]")
+		fmt.Fprintln(flags.Output(), "Shows how durations and answers of recorded runs changed "+
+			"over time")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var year, day, part uint
+	if flags.NArg() > 0 {
+		var restArgs []string
+		year, restArgs = parseYear(flags.Args())
+		if len(restArgs) > 0 {
+			var err error
+			if day, part, err = parseDayAndPart(restArgs); err != nil {
+				flags.Usage()
+				return err
+			}
+		}
+	}
+
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+	records, err := newHistoryStore(moduleRoot, *historyPath).load()
+	if err != nil {
+		return err
+	}
+
+	var filtered []historyRecord
+	for _, rec := range records {
+		if (year == 0 || rec.Year == year) && (day == 0 || rec.Day == day) &&
+			(part == 0 || rec.Part == part) {
+			filtered = append(filtered, rec)
+		}
+	}
+	if len(filtered) == 0 {
+		fmt.Println("No runs recorded")
+		return nil
+	}
+
+	changesCount := printHistory(os.Stdout, filtered)
+	if changesCount > 0 {
+		fmt.Printf("%d answer changes found\n", changesCount)
+	}
+	return nil
+}
+
+// historyKey identifies runs which are supposed to produce the same answer
+type historyKey struct {
+	year, day, part uint
+	inputHash       string
+}
+
+func (rec historyRecord) key() historyKey {
+	return historyKey{rec.Year, rec.Day, rec.Part, rec.InputHash}
+}
+
+// answerChange is a successful run whose answer differs from the previous successful one
+type answerChange struct {
+	prev, next historyRecord
+}
+
+// printHistory prints trends of every solver on every input and returns the number of answer
+// changes
+func printHistory(w io.Writer, records []historyRecord) int {
+	var keys []historyKey
+	recordsByKey := map[historyKey][]historyRecord{}
+	for _, rec := range records {
+		key := rec.key()
+		if _, found := recordsByKey[key]; !found {
+			keys = append(keys, key)
+		}
+		recordsByKey[key] = append(recordsByKey[key], rec)
+	}
+	slices.SortStableFunc(keys, func(k1, k2 historyKey) int {
+		if k1.year != k2.year {
+			return cmp.Compare(k1.year, k2.year)
+		}
+		if k1.day != k2.day {
+			return cmp.Compare(k1.day, k2.day)
+		}
+		return cmp.Compare(k1.part, k2.part)
+	})
+
+	var changesCount int
+	for _, key := range keys {
+		keyRecords := recordsByKey[key]
+		lastRec := keyRecords[len(keyRecords)-1]
+		fmt.Fprintf(w, "%d day %d part %d, input %s (%s)\n", key.year, key.day, key.part,
+			lastRec.Input, shortHash(key.inputHash))
+
+		var solverNames []string
+		recordsBySolver := map[string][]historyRecord{}
+		for _, rec := range keyRecords {
+			if _, found := recordsBySolver[rec.Solver]; !found {
+				solverNames = append(solverNames, rec.Solver)
+			}
+			recordsBySolver[rec.Solver] = append(recordsBySolver[rec.Solver], rec)
+		}
+		slices.Sort(solverNames)
+		for _, name := range solverNames {
+			fmt.Fprintf(w, "  %s: %s\n", name, formatTrend(recordsBySolver[name]))
+		}
+
+		for _, change := range findAnswerChanges(keyRecords) {
+			changesCount++
+			fmt.Fprintf(w, "  ANSWER CHANGED: %s by %s at %s -> %s by %s at %s\n",
+				change.prev.Answer, change.prev.Solver, change.prev.Revision,
+				change.next.Answer, change.next.Solver, change.next.Revision)
+		}
+	}
+
+	return changesCount
+}
+
+// formatTrend describes how runs of a single solver changed from the first to the last one
+func formatTrend(records []historyRecord) string {
+	var succeeded []historyRecord
+	for _, rec := range records {
+		if rec.Error == "" {
+			succeeded = append(succeeded, rec)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d runs", len(records))
+	if failedCount := len(records) - len(succeeded); failedCount > 0 {
+		fmt.Fprintf(&b, " (%d failed)", failedCount)
+	}
+	if len(succeeded) == 0 {
+		return b.String()
+	}
+
+	first, last := succeeded[0], succeeded[len(succeeded)-1]
+	best := slices.MinFunc(succeeded, func(r1, r2 historyRecord) int {
+		return cmp.Compare(r1.Duration, r2.Duration)
+	})
+	fmt.Fprintf(&b, ", answer %s, duration %s -> %s (best %s at %s), allocated %s -> %s, "+
+		"peak RSS %s -> %s, revisions %s -> %s", last.Answer,
+		first.Duration.Round(time.Millisecond), last.Duration.Round(time.Millisecond),
+		best.Duration.Round(time.Millisecond), best.Revision,
+		formatAllocs(first.AllocBytes, first.AllocObjects),
+		formatAllocs(last.AllocBytes, last.AllocObjects), formatKiB(first.PeakRSSKiB),
+		formatKiB(last.PeakRSSKiB), first.Revision, last.Revision)
+	return b.String()
+}
+
+// findAnswerChanges compares every successful run with the previous successful one. Failed runs
+// are not answer changes.
+func findAnswerChanges(records []historyRecord) []answerChange {
+	var changes []answerChange
+	var prev *historyRecord
+	for i := range records {
+		rec := &records[i]
+		if rec.Error != "" {
+			continue
+		}
+		if prev != nil && prev.Answer != rec.Answer {
+			changes = append(changes, answerChange{*prev, *rec})
+		}
+		prev = rec
+	}
+	return changes
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryStoreRecord(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(inputPath, []byte("1abc2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	history := &historyStore{path: filepath.Join(dir, "history", "runs.jsonl"), revision: "abc"}
+	s := legacySolver(1, 1)
+
+	runs := []struct {
+		run                solverRun
+		err                error
+		expectedPrevAnswer string
+	}{
+		{solverRun{answer: "12", duration: time.Second}, nil, ""},
+		{solverRun{answer: "12", duration: time.Millisecond, allocBytes: 4096, allocObjects: 8},
+			nil, ""},
+		// failed runs are recorded but neither change the answer nor are compared with it
+		{solverRun{}, errors.New("Timed out"), ""},
+		{solverRun{answer: "13"}, nil, "12"},
+	}
+	for i, r := range runs {
+		prevAnswer, err := history.record(s, "input.txt", inputPath, r.run, r.err)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prevAnswer != r.expectedPrevAnswer {
+			t.Errorf("Run %d: previous answer %q returned. Expected %q", i+1, prevAnswer,
+				r.expectedPrevAnswer)
+		}
+	}
+
+	records, err := history.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(runs) {
+		t.Fatalf("%d records loaded. Expected %d", len(records), len(runs))
+	}
+	if rec := records[1]; rec.Solver != "2023/01/part1/main" || rec.Answer != "12" ||
+		rec.Duration != time.Millisecond || rec.AllocBytes != 4096 || rec.AllocObjects != 8 ||
+		rec.Revision != "abc" || rec.InputHash != records[0].InputHash {
+		t.Errorf("Unexpected second record: %+v", rec)
+	}
+	if records[2].Error != "Timed out" {
+		t.Errorf("Unexpected error of the failed run: %q", records[2].Error)
+	}
+
+	changes := findAnswerChanges(records)
+	if len(changes) != 1 || changes[0].prev.Answer != "12" || changes[0].next.Answer != "13" {
+		t.Errorf("Unexpected answer changes: %+v", changes)
+	}
+}
+
+func TestHistoryStoreLoadMissing(t *testing.T) {
+	history := &historyStore{path: filepath.Join(t.TempDir(), "missing.jsonl")}
+	records, err := history.load()
+	if err != nil || len(records) != 0 {
+		t.Errorf("Records %v and error %v are loaded from a missing store", records, err)
+	}
+}
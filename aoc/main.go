@@ -0,0 +1,67 @@
+// Command aoc bundles tools working with all the days at once.
+//
+// Usage:
+//
+//	aoc <command> [arguments]
+//
+// Commands:
+//
+//	run      run a puzzle part on an input and record the run
+//	history  show recorded runs and answer changes
+//	gen      generate a random puzzle input
+//	diff     compare implementations of the same puzzle part
+//	serve    start a web dashboard for running solvers
+//
+// Puzzles of 2023 live in the top-level DD/partN directories. Puzzles of other years live in
+// YYYY/DD/partN directories and are found automatically; their sample answers are listed in
+// YYYY/sample-answers.txt. Commands taking a day accept an optional year before it, which
+// defaults to 2023. All years share the util packages.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name, description string
+	run               func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run a puzzle part on an input and record the run", runRun},
+	{"history", "show recorded runs and answer changes", runHistory},
+	{"gen", "generate a random puzzle input", runGen},
+	{"diff", "compare implementations of the same puzzle part", runDiff},
+	{"serve", "start a web dashboard for running solvers", runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
+	printUsage()
+	os.Exit(2)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.description)
+	}
+}
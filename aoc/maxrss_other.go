@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// maxRSSKiB isn't supported on this platform
+func maxRSSKiB(state *os.ProcessState) int64 {
+	return 0
+}
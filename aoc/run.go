@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// defaultInputFile is looked for in the day directory if no input is passed
+const defaultInputFile = "input.txt"
+
+func runRun(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	impl := flags.String("impl", "", "name of the implementation to run, e.g. geometric. "+
+		"All implementations are run if not set")
+	historyPath := flags.String("history", "", "file runs are recorded to. "+
+		"Defaults to "+defaultHistoryPath+" in the module root")
+	timeout := flags.Duration("timeout", 10*time.Minute, "max time a single run may take")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc run [flags] [year] <day> <part> [input]")
+		fmt.Fprintf(flags.Output(), "Runs implementations of the day part on the input and "+
+			"records the runs to the history. The year defaults to %d and the input to "+
+			"%s in the day directory\n", legacyLayoutYear, defaultInputFile)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	year, restArgs := parseYear(flags.Args())
+	if len(restArgs) < 2 || len(restArgs) > 3 {
+		flags.Usage()
+		return errors.New("Day, part and an optional input are expected")
+	}
+	day, part, err := parseDayAndPart(restArgs[:2])
+	if err != nil {
+		flags.Usage()
+		return err
+	}
+	if part == 0 {
+		flags.Usage()
+		return errors.New("Part is expected")
+	}
+
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+	// the default input is described relative to the module root, so it's the same wherever aoc
+	// is run from
+	input := filepath.Join(dayDir(year, day), defaultInputFile)
+	inputPath := filepath.Join(moduleRoot, input)
+	if len(restArgs) == 3 {
+		input, inputPath = restArgs[2], restArgs[2]
+	}
+	allSolvers, err := loadSolvers(moduleRoot)
+	if err != nil {
+		return err
+	}
+
+	var toRun []solver
+	for _, s := range findSolvers(allSolvers, year, day, part) {
+		if *impl == "" || s.name == *impl {
+			toRun = append(toRun, s)
+		}
+	}
+	if len(toRun) == 0 {
+		return fmt.Errorf("No implementation of day %d part %d of %d found", day, part, year)
+	}
+
+	binaries, err := newSolverBinaries(moduleRoot)
+	if err != nil {
+		return err
+	}
+	defer binaries.close()
+	history := newHistoryStore(moduleRoot, *historyPath)
+
+	var failedCount int
+	for _, s := range toRun {
+		run, runErr := s.run(context.Background(), binaries, inputPath, *timeout)
+		if runErr != nil {
+			failedCount++
+			fmt.Printf("%s: failed after %s: %s\n", s, run.duration.Round(time.Millisecond), runErr)
+		} else {
+			fmt.Printf("%s: %s in %s, allocated %s, peak RSS %s\n", s, run.answer,
+				run.duration.Round(time.Millisecond), formatAllocs(run.allocBytes, run.allocObjects),
+				formatKiB(run.peakRSSKiB))
+		}
+
+		prevAnswer, err := history.record(s, input, inputPath, run, runErr)
+		if err != nil {
+			return fmt.Errorf("Failed to record the run: %w", err)
+		}
+		if prevAnswer != "" {
+			fmt.Printf("%s: ANSWER CHANGED: %s was recorded before for the same input\n", s,
+				prevAnswer)
+		}
+	}
+
+	if failedCount > 0 {
+		return fmt.Errorf("%d of %d runs failed", failedCount, len(toRun))
+	}
+	return nil
+}
+
+// formatAllocs formats allocated bytes and objects; 0 bytes are unknown
+func formatAllocs(bytes, objects uint64) string {
+	switch {
+	case bytes == 0:
+		return "unknown"
+	case bytes < 1<<20:
+		return fmt.Sprintf("%.1f KiB in %d objects", float64(bytes)/(1<<10), objects)
+	default:
+		return fmt.Sprintf("%.1f MiB in %d objects", float64(bytes)/(1<<20), objects)
+	}
+}
+
+func formatKiB(kib int64) string {
+	switch {
+	case kib <= 0:
+		return "unknown"
+	case kib < 1024:
+		return fmt.Sprintf("%d KiB", kib)
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(kib)/1024)
+	}
+}
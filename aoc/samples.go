@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const sampleAnswersFile = "sample-answers.txt"
+
+// sample is an input with a known answer listed in sample-answers.txt
+type sample struct {
+	year, day, part uint
+	name            string
+	// path is relative to the module root
+	path   string
+	answer string
+}
+
+var (
+	sampleKeyRegexp     = regexp.MustCompile(`^(\d\d)/part([12])(?:/([\w-]+))?$`)
+	integerAnswerRegexp = regexp.MustCompile(`^-?\d+$`)
+)
+
+// loadSamples parses sample-answers.txt of the legacy layout in the module root and the ones in
+// YYYY directories
+func loadSamples(moduleRoot string) ([]sample, error) {
+	samples, err := loadYearSamples(moduleRoot, "", legacyLayoutYear)
+	if err != nil {
+		return nil, err
+	}
+
+	answerFiles, err := filepath.Glob(filepath.Join(moduleRoot, "[0-9][0-9][0-9][0-9]",
+		sampleAnswersFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, answersFile := range answerFiles {
+		yearDir := filepath.Base(filepath.Dir(answersFile))
+		yearSamples, err := loadYearSamples(moduleRoot, yearDir, util.ParseUintOrPanic(yearDir))
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, yearSamples...)
+	}
+
+	return samples, nil
+}
+
+// loadYearSamples parses sample-answers.txt in the year directory. Lines look like
+// "DD/partN[/name]: answer", where the name defaults to "sample" and the input is
+// DD/partN/name.txt or DD/name.txt. Answers which aren't integers can't be checked automatically
+// and are skipped.
+func loadYearSamples(moduleRoot, yearDir string, year uint) ([]sample, error) {
+	answersFile := filepath.Join(yearDir, sampleAnswersFile)
+	data, err := os.ReadFile(filepath.Join(moduleRoot, answersFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []sample
+	for lineIdx, line := range strings.Split(string(data), "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		key, answer, found := strings.Cut(line, ":")
+		match := sampleKeyRegexp.FindStringSubmatch(key)
+		if !found || match == nil {
+			return nil, fmt.Errorf("%s: Line %d: Unexpected format: %s", answersFile, lineIdx+1,
+				line)
+		}
+		answer = strings.TrimSpace(answer)
+		if !integerAnswerRegexp.MatchString(answer) {
+			continue
+		}
+
+		s := sample{
+			year:   year,
+			day:    util.ParseUintOrPanic(match[1]),
+			part:   util.ParseUintOrPanic(match[2]),
+			name:   match[3],
+			answer: answer,
+		}
+		if s.name == "" {
+			s.name = "sample"
+		}
+
+		s.path, err = findSampleFile(moduleRoot, filepath.Join(yearDir, match[1]),
+			"part"+match[2], s.name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: Line %d: %w", answersFile, lineIdx+1, err)
+		}
+		samples = append(samples, s)
+	}
+
+	return samples, nil
+}
+
+func findSampleFile(moduleRoot, dayDir, partDir, name string) (string, error) {
+	candidates := []string{
+		filepath.Join(dayDir, partDir, name+".txt"),
+		filepath.Join(dayDir, name+".txt"),
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(filepath.Join(moduleRoot, path)); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("None of sample files %v exists", candidates)
+}
+
+func findSamples(samples []sample, year, day, part uint) []sample {
+	var found []sample
+	for _, s := range samples {
+		if s.year == year && s.day == day && s.part == part {
+			found = append(found, s)
+		}
+	}
+	return found
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLoadSamples(t *testing.T) {
+	samples, err := loadSamples("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allSolvers, err := loadSolvers("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range samples {
+		if len(findSolvers(allSolvers, s.year, s.day, s.part)) == 0 {
+			t.Errorf("Sample %s has no solvers", s.path)
+		}
+	}
+
+	day8Part1Samples := findSamples(samples, legacyLayoutYear, 8, 1)
+	if len(day8Part1Samples) != 2 {
+		t.Fatalf("%d samples found for day 8 part 1. Expected 2", len(day8Part1Samples))
+	}
+	if s := day8Part1Samples[1]; s.path != "08/part1/sample2.txt" || s.answer != "6" {
+		t.Errorf("Unexpected second sample of day 8 part 1: %+v", s)
+	}
+
+	// the common sample of the day is used when the part has none
+	day2Part2Samples := findSamples(samples, legacyLayoutYear, 2, 2)
+	if len(day2Part2Samples) != 1 || day2Part2Samples[0].path != "02/sample.txt" {
+		t.Errorf("Unexpected samples of day 2 part 2: %+v", day2Part2Samples)
+	}
+}
@@ -0,0 +1,651 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// maxShownOutputLength limits the solver output shown on a page; some solvers print a lot of
+// progress
+const maxShownOutputLength = 1 << 20
+
+// minGridRows lines of at least minGridCols characters without spaces and of the same length make
+// a grid rendering in the output, like the maps many days print
+const minGridRows, minGridCols = 3, 3
+
+// maxColouredGridCells limits grids whose cells are coloured by their characters; bigger ones are
+// shown as they are to keep pages light
+const maxColouredGridCells = 1 << 16
+
+// gridColoursCount is the number of colours of grid cells. Characters rarer than the ones having
+// their own colours share the last one.
+const gridColoursCount = 6
+
+// maxUploadMemory is the part of an upload kept in memory; the rest is stored in temporary files
+const maxUploadMemory = 32 << 20
+
+type server struct {
+	moduleRoot string
+	binaries   *solverBinaries
+	history    *historyStore
+	solvers    []solver
+	samples    []sample
+	timeout    time.Duration
+	// verifyTimeout limits a request verifying samples, which may run every solver
+	verifyTimeout time.Duration
+	// maxUploadSize limits the body of a run request with an uploaded input
+	maxUploadSize int64
+
+	mu                 sync.Mutex
+	lastRunByName      map[string]runView
+	sampleChecksByName map[string][]sampleCheckView
+}
+
+// runView and other views have exported fields only, so templates can access them
+
+type runView struct {
+	Input           string
+	Answer          string
+	Error           string
+	Duration        time.Duration
+	Output          string
+	OutputTruncated bool
+	// OutputBlocks is the output split into text and grid renderings
+	OutputBlocks []outputBlock
+	FinishedAt   time.Time
+}
+
+// outputBlock is a part of the output: text or a grid rendering
+type outputBlock struct {
+	Text   string
+	IsGrid bool
+	// GridRows are rows of a grid split into runs of cells of the same colour. It's empty for text
+	// and grids too big to colour.
+	GridRows [][]cellRun
+}
+
+type cellRun struct {
+	Chars  string
+	Colour int
+}
+
+type sampleCheckView struct {
+	Path     string
+	Expected string
+	Run      runView
+	Passed   bool
+}
+
+type solverView struct {
+	Name      string
+	Dir       string
+	Year      uint
+	Day, Part uint
+	// SamplesCount is the number of samples with known answers
+	SamplesCount       int
+	SamplesPassedCount int
+	SamplesChecked     bool
+	SampleChecks       []sampleCheckView
+	LastRun            *runView
+	Inputs             []string
+}
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8023", "address to listen on")
+	timeout := flags.Duration("timeout", time.Minute, "max time a single run may take")
+	verifyTimeout := flags.Duration("verify-timeout", 10*time.Minute,
+		"max time verifying samples of one or all solvers may take")
+	maxUploadSize := flags.Int64("max-upload-size", 256<<20, "max size of an uploaded input in bytes")
+	historyPath := flags.String("history", "", "file runs are recorded to. "+
+		"Defaults to "+defaultHistoryPath+" in the module root")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc serve [flags]")
+		fmt.Fprintln(flags.Output(), "Starts a web dashboard to run solvers and check them on samples")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return errors.New("No arguments are expected")
+	}
+
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+	allSolvers, err := loadSolvers(moduleRoot)
+	if err != nil {
+		return err
+	}
+	samples, err := loadSamples(moduleRoot)
+	if err != nil {
+		return err
+	}
+	binaries, err := newSolverBinaries(moduleRoot)
+	if err != nil {
+		return err
+	}
+	defer binaries.close()
+
+	s := &server{
+		moduleRoot:         moduleRoot,
+		binaries:           binaries,
+		history:            newHistoryStore(moduleRoot, *historyPath),
+		solvers:            allSolvers,
+		samples:            samples,
+		timeout:            *timeout,
+		verifyTimeout:      *verifyTimeout,
+		maxUploadSize:      *maxUploadSize,
+		lastRunByName:      map[string]runView{},
+		sampleChecksByName: map[string][]sampleCheckView{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/solver", s.handleSolver)
+	mux.HandleFunc("/run", s.handleRun)
+	mux.HandleFunc("/verify", s.handleVerify)
+
+	log.Printf("Serving dashboard on http://%s", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	views := make([]solverView, 0, len(s.solvers))
+	for _, solver := range s.solvers {
+		views = append(views, s.solverView(solver))
+	}
+	s.render(w, indexTemplate, views)
+}
+
+func (s *server) handleSolver(w http.ResponseWriter, r *http.Request) {
+	solver, found := s.findSolverByName(r.URL.Query().Get("name"))
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	view := s.solverView(solver)
+	var err error
+	if view.Inputs, err = s.listInputs(solver.year, solver.day); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, solverTemplate, view)
+}
+
+// handleRun runs the solver on a file from the repository or an uploaded one
+func (s *server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil &&
+		!errors.Is(err, http.ErrNotMultipart) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("Upload exceeds %d bytes", maxBytesErr.Limit),
+				http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	solver, found := s.findSolverByName(r.FormValue("name"))
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	inputPath, inputDescription, cleanUp, err := s.receiveInput(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer cleanUp()
+
+	run, err := solver.run(r.Context(), s.binaries, inputPath, s.timeout)
+	s.recordRun(solver, inputDescription, inputPath, run, err)
+	view := newRunView(inputDescription, run, err)
+
+	s.mu.Lock()
+	s.lastRunByName[solver.String()] = view
+	s.mu.Unlock()
+
+	http.Redirect(w, r, solverURL(solver), http.StatusSeeOther)
+}
+
+// handleVerify runs the solver or all the solvers if no name is passed on their samples. Runs stop
+// once the request is cancelled or takes the verify timeout; checks done by then are kept.
+func (s *server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	toVerify := s.solvers
+	redirectURL := "/"
+	if name := r.FormValue("name"); name != "" {
+		selected, found := s.findSolverByName(name)
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		toVerify = []solver{selected}
+		redirectURL = solverURL(selected)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(r.Context(), s.verifyTimeout,
+		fmt.Errorf("Verification takes longer than %s", s.verifyTimeout))
+	defer cancel()
+
+	for _, solver := range toVerify {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var checks []sampleCheckView
+		for _, smpl := range findSamples(s.samples, solver.year, solver.day, solver.part) {
+			if ctx.Err() != nil {
+				break
+			}
+
+			samplePath := filepath.Join(s.moduleRoot, smpl.path)
+			run, err := solver.run(ctx, s.binaries, samplePath, s.timeout)
+			s.recordRun(solver, smpl.path, samplePath, run, err)
+			checks = append(checks, sampleCheckView{
+				Path:     smpl.path,
+				Expected: smpl.answer,
+				Run:      newRunView(smpl.path, run, err),
+				Passed:   err == nil && run.answer == smpl.answer,
+			})
+		}
+
+		s.mu.Lock()
+		s.sampleChecksByName[solver.String()] = checks
+		s.mu.Unlock()
+	}
+
+	if ctx.Err() != nil {
+		http.Error(w, context.Cause(ctx).Error(), http.StatusGatewayTimeout)
+		return
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// recordRun appends the run to the history. Failing to record doesn't fail the request.
+func (s *server) recordRun(solver solver, input, inputPath string, run solverRun, runErr error) {
+	prevAnswer, err := s.history.record(solver, input, inputPath, run, runErr)
+	if err != nil {
+		log.Printf("Failed to record the run of %s: %s", solver, err)
+	} else if prevAnswer != "" {
+		log.Printf("%s: answer on %s changed from %s to %s", solver, input, prevAnswer, run.answer)
+	}
+}
+
+// receiveInput returns the path of the input file to run a solver on. An uploaded file is
+// preferred over a path in the repository; paths out of it aren't accepted, so the dashboard
+// doesn't expose other files. cleanUp removes the uploaded file.
+func (s *server) receiveInput(r *http.Request) (string, string, func(), error) {
+	noCleanUp := func() {}
+
+	file, header, err := r.FormFile("upload")
+	if err == nil {
+		defer file.Close()
+
+		tmpFile, err := os.CreateTemp("", "aoc-upload-")
+		if err != nil {
+			return "", "", noCleanUp, err
+		}
+		defer tmpFile.Close()
+		cleanUp := func() {
+			os.Remove(tmpFile.Name())
+		}
+
+		if _, err := io.Copy(tmpFile, file); err != nil {
+			cleanUp()
+			return "", "", noCleanUp, err
+		}
+		return tmpFile.Name(), "uploaded " + header.Filename, cleanUp, nil
+	}
+	// a form without files may be URL-encoded
+	if !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart) {
+		return "", "", noCleanUp, err
+	}
+
+	path := strings.TrimSpace(r.FormValue("path"))
+	if path == "" {
+		return "", "", noCleanUp, errors.New("Neither a file is uploaded nor a path is entered")
+	}
+	if !filepath.IsLocal(path) {
+		return "", "", noCleanUp, fmt.Errorf("Path <%s> is out of the repository", path)
+	}
+	fullPath := filepath.Join(s.moduleRoot, path)
+	if _, err := os.Stat(fullPath); err != nil {
+		return "", "", noCleanUp, err
+	}
+	return fullPath, path, noCleanUp, nil
+}
+
+// listInputs returns paths of text files in the directory of the day
+func (s *server) listInputs(year, day uint) ([]string, error) {
+	var inputs []string
+	err := filepath.WalkDir(filepath.Join(s.moduleRoot, dayDir(year, day)), func(path string,
+		d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".txt") {
+			relPath, err := filepath.Rel(s.moduleRoot, path)
+			if err != nil {
+				return err
+			}
+			inputs = append(inputs, relPath)
+		}
+		return nil
+	})
+	return inputs, err
+}
+
+func (s *server) solverView(solver solver) solverView {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	view := solverView{
+		Name:         solver.String(),
+		Dir:          solver.dir,
+		Year:         solver.year,
+		Day:          solver.day,
+		Part:         solver.part,
+		SamplesCount: len(findSamples(s.samples, solver.year, solver.day, solver.part)),
+	}
+
+	checks, checked := s.sampleChecksByName[solver.String()]
+	view.SamplesChecked = checked
+	view.SampleChecks = checks
+	for _, check := range checks {
+		if check.Passed {
+			view.SamplesPassedCount++
+		}
+	}
+
+	if lastRun, found := s.lastRunByName[solver.String()]; found {
+		view.LastRun = &lastRun
+	}
+
+	return view
+}
+
+func (s *server) render(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Failed to render %s: %s", tmpl.Name(), err.Error())
+	}
+}
+
+func newRunView(input string, run solverRun, err error) runView {
+	view := runView{
+		Input:      input,
+		Answer:     run.answer,
+		Duration:   run.duration.Round(time.Millisecond),
+		Output:     run.output,
+		FinishedAt: time.Now(),
+	}
+	if err != nil {
+		view.Error = err.Error()
+	}
+	if len(view.Output) > maxShownOutputLength {
+		view.Output = view.Output[len(view.Output)-maxShownOutputLength:]
+		view.OutputTruncated = true
+	}
+	view.OutputBlocks = splitOutput(view.Output)
+	return view
+}
+
+// splitOutput finds grid renderings in the output. Lines between them are text blocks.
+func splitOutput(output string) []outputBlock {
+	lines := strings.SplitAfter(output, "\n")
+
+	var blocks []outputBlock
+	var text strings.Builder
+	for lineIdx := 0; lineIdx < len(lines); {
+		width := gridLineWidth(lines[lineIdx])
+		endIdx := lineIdx
+		for endIdx < len(lines) && width > 0 && gridLineWidth(lines[endIdx]) == width {
+			endIdx++
+		}
+
+		if endIdx-lineIdx < minGridRows {
+			text.WriteString(lines[lineIdx])
+			lineIdx++
+			continue
+		}
+
+		if text.Len() > 0 {
+			blocks = append(blocks, outputBlock{Text: text.String()})
+			text.Reset()
+		}
+		blocks = append(blocks, newGridBlock(lines[lineIdx:endIdx], width))
+		lineIdx = endIdx
+	}
+	if text.Len() > 0 {
+		blocks = append(blocks, outputBlock{Text: text.String()})
+	}
+	return blocks
+}
+
+// gridLineWidth returns the number of cells of a grid row or 0 if the line isn't one
+func gridLineWidth(line string) int {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < minGridCols || strings.ContainsAny(line, " \t") {
+		return 0
+	}
+	return utf8.RuneCountInString(line)
+}
+
+// newGridBlock colours cells of the rows by their characters. The most frequent character is
+// the background, so it gets the first colour, which is the faintest one.
+func newGridBlock(rows []string, width int) outputBlock {
+	block := outputBlock{Text: strings.Join(rows, ""), IsGrid: true}
+	if width*len(rows) > maxColouredGridCells {
+		return block
+	}
+
+	countByChar := make(map[rune]int)
+	for _, row := range rows {
+		for _, char := range strings.TrimRight(row, "\r\n") {
+			countByChar[char]++
+		}
+	}
+	chars := util.MapKeysToSlice(countByChar)
+	slices.SortFunc(chars, func(c1, c2 rune) int {
+		if countByChar[c1] != countByChar[c2] {
+			return countByChar[c2] - countByChar[c1]
+		}
+		return int(c1 - c2)
+	})
+	colourByChar := make(map[rune]int, len(chars))
+	for charIdx, char := range chars {
+		colourByChar[char] = min(charIdx, gridColoursCount-1)
+	}
+
+	for _, row := range rows {
+		var runs []cellRun
+		for _, char := range strings.TrimRight(row, "\r\n") {
+			colour := colourByChar[char]
+			if len(runs) > 0 && runs[len(runs)-1].Colour == colour {
+				runs[len(runs)-1].Chars += string(char)
+			} else {
+				runs = append(runs, cellRun{string(char), colour})
+			}
+		}
+		block.GridRows = append(block.GridRows, runs)
+	}
+	return block
+}
+
+func (s *server) findSolverByName(name string) (solver, bool) {
+	for _, candidate := range s.solvers {
+		if candidate.String() == name {
+			return candidate, true
+		}
+	}
+	return solver{}, false
+}
+
+func solverURL(s solver) string {
+	return "/solver?name=" + url.QueryEscape(s.String())
+}
+
+const pageHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Advent of Code</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+.output { background: #f4f4f4; padding: 1em; overflow: auto; max-height: 40em; }
+.output pre { margin: 0; }
+.output pre.grid { margin: 0.5em 0; line-height: 1.1; font-weight: bold; }
+.grid .c0 { color: #bbb; }
+.grid .c1 { color: #222; }
+.grid .c2 { color: #c00; }
+.grid .c3 { color: #06c; }
+.grid .c4 { color: #080; }
+.grid .c5 { color: #a0a; }
+.passed { color: #080; }
+.failed { color: #c00; }
+</style>
+</head>
+<body>
+`
+
+const pageFooter = `</body>
+</html>
+`
+
+var indexTemplate = template.Must(template.New("index").Parse(pageHeader + `
+<h1>Advent of Code</h1>
+<form method="post" action="/verify">
+<button type="submit">Verify all samples</button>
+</form>
+<p></p>
+<table>
+<tr><th>Year</th><th>Day</th><th>Part</th><th>Implementation</th><th>Samples</th><th>Last run</th></tr>
+{{range .}}
+<tr>
+<td>{{.Year}}</td>
+<td>{{.Day}}</td>
+<td>{{.Part}}</td>
+<td><a href="/solver?name={{.Name}}">{{.Name}}</a></td>
+<td>{{template "samples" .}}</td>
+<td>{{with .LastRun}}{{template "runSummary" .}}{{else}}-{{end}}</td>
+</tr>
+{{end}}
+</table>
+` + pageFooter + `
+{{define "samples"}}
+{{- if eq .SamplesCount 0}}no samples
+{{- else if not .SamplesChecked}}{{.SamplesCount}} not checked
+{{- else if eq .SamplesPassedCount .SamplesCount}}<span class="passed">{{.SamplesPassedCount}}/{{.SamplesCount}} passed</span>
+{{- else}}<span class="failed">{{.SamplesPassedCount}}/{{.SamplesCount}} passed</span>
+{{- end}}
+{{- end}}
+{{define "runSummary"}}
+{{- if .Error}}<span class="failed">failed</span>{{else}}{{.Answer}}{{end}} in {{.Duration}}
+{{- end}}
+`))
+
+var solverTemplate = template.Must(template.Must(indexTemplate.Clone()).New("solver").Parse(
+	pageHeader + `
+<p><a href="/">All solvers</a></p>
+<h1>{{.Name}}</h1>
+<p>Source: {{.Dir}}</p>
+
+<h2>Samples</h2>
+<p>{{template "samples" .}}</p>
+{{if .SamplesCount}}
+<form method="post" action="/verify">
+<input type="hidden" name="name" value="{{.Name}}">
+<button type="submit">Verify samples</button>
+</form>
+{{end}}
+{{if .SampleChecks}}
+<table>
+<tr><th>Sample</th><th>Expected</th><th>Answer</th><th>Time</th></tr>
+{{range .SampleChecks}}
+<tr>
+<td>{{.Path}}</td>
+<td>{{.Expected}}</td>
+<td class="{{if .Passed}}passed{{else}}failed{{end}}">{{if .Run.Error}}{{.Run.Error}}{{else}}{{.Run.Answer}}{{end}}</td>
+<td>{{.Run.Duration}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+
+<h2>Run</h2>
+<form method="post" action="/run" enctype="multipart/form-data">
+<input type="hidden" name="name" value="{{.Name}}">
+<p>
+<label>Input in the repository:
+<input type="text" name="path" list="inputs" size="50">
+</label>
+<datalist id="inputs">
+{{range .Inputs}}<option value="{{.}}">{{end}}
+</datalist>
+</p>
+<p><label>Or upload a file: <input type="file" name="upload"></label></p>
+<button type="submit">Run</button>
+</form>
+
+{{with .LastRun}}
+<h2>Last run</h2>
+<table>
+<tr><th>Input</th><td>{{.Input}}</td></tr>
+<tr><th>Answer</th><td>{{if .Error}}<span class="failed">{{.Error}}</span>{{else}}{{.Answer}}{{end}}</td></tr>
+<tr><th>Time</th><td>{{.Duration}}</td></tr>
+<tr><th>Finished at</th><td>{{.FinishedAt.Format "15:04:05"}}</td></tr>
+</table>
+<h3>Output{{if .OutputTruncated}} (last {{len .Output}} bytes){{end}}</h3>
+<div class="output">
+{{- range .OutputBlocks}}
+{{- if not .IsGrid}}<pre>{{.Text}}</pre>
+{{- else if not .GridRows}}<pre class="grid">{{.Text}}</pre>
+{{- else}}<pre class="grid">{{range .GridRows}}{{range .}}<span class="c{{.Colour}}">{{.Chars}}</span>{{end}}
+{{end}}</pre>
+{{- end}}
+{{- end}}
+</div>
+{{end}}
+` + pageFooter))
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves day 1 part 1 of the repository. Its sample is listed twice, with the right
+// answer and a wrong one.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+
+	binaries, err := newSolverBinaries("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(binaries.close)
+
+	return &server{
+		moduleRoot: "..",
+		binaries:   binaries,
+		history:    newHistoryStore("..", filepath.Join(t.TempDir(), "history.jsonl")),
+		solvers:    []solver{legacySolver(1, 1)},
+		samples: []sample{
+			{legacyLayoutYear, 1, 1, "sample", "01/part1/sample.txt", "142"},
+			{legacyLayoutYear, 1, 1, "wrong", "01/part1/sample.txt", "143"},
+		},
+		timeout:            time.Minute,
+		verifyTimeout:      time.Minute,
+		maxUploadSize:      1 << 10,
+		lastRunByName:      map[string]runView{},
+		sampleChecksByName: map[string][]sampleCheckView{},
+	}
+}
+
+func postForm(handler http.HandlerFunc, target string,
+	values url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func postUpload(t *testing.T, s *server,
+	name, fileName, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("name", name); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("upload", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/run", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.handleRun(rec, req)
+	return rec
+}
+
+func TestHandleRun(t *testing.T) {
+	s := newTestServer(t)
+	name := legacySolver(1, 1).String()
+
+	tests := []struct {
+		name           string
+		send           func() *httptest.ResponseRecorder
+		expectedStatus int
+		// expectedInput and expectedAnswer are of a successful run; expectedError is the
+		// response body of a failed request
+		expectedInput  string
+		expectedAnswer string
+		expectedError  string
+	}{
+		{
+			"upload",
+			func() *httptest.ResponseRecorder {
+				return postUpload(t, s, name, "mine.txt", "1abc2\nx7y")
+			},
+			http.StatusSeeOther, "uploaded mine.txt", "89", "",
+		},
+		{
+			"path",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {name}, "path": {"01/part1/sample.txt"}})
+			},
+			http.StatusSeeOther, "01/part1/sample.txt", "142", "",
+		},
+		{
+			"missing input",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run", url.Values{"name": {name}})
+			},
+			http.StatusBadRequest, "", "", "Neither a file is uploaded nor a path is entered",
+		},
+		{
+			"missing file",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {name}, "path": {"01/part1/absent.txt"}})
+			},
+			http.StatusBadRequest, "", "", "no such file or directory",
+		},
+		{
+			"absolute path",
+			func() *httptest.ResponseRecorder {
+				absPath, err := filepath.Abs("../01/part1/sample.txt")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {name}, "path": {absPath}})
+			},
+			http.StatusBadRequest, "", "", "is out of the repository",
+		},
+		{
+			"path out of the repository",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {name}, "path": {"01/../../go.mod"}})
+			},
+			http.StatusBadRequest, "", "", "is out of the repository",
+		},
+		{
+			"oversized upload",
+			func() *httptest.ResponseRecorder {
+				return postUpload(t, s, name, "big.txt", strings.Repeat("1abc2\n", 1<<10))
+			},
+			http.StatusRequestEntityTooLarge, "", "", "Upload exceeds 1024 bytes",
+		},
+		{
+			"unknown day",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {"2023/26/part1/main"}, "path": {"01/part1/sample.txt"}})
+			},
+			http.StatusNotFound, "", "", "404 page not found",
+		},
+		{
+			"unknown part",
+			func() *httptest.ResponseRecorder {
+				return postForm(s.handleRun, "/run",
+					url.Values{"name": {"2023/01/part3/main"}, "path": {"01/part1/sample.txt"}})
+			},
+			http.StatusNotFound, "", "", "404 page not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s.lastRunByName = map[string]runView{}
+
+			rec := test.send()
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("Status %d: %s. Expected %d", rec.Code, rec.Body.String(),
+					test.expectedStatus)
+			}
+
+			lastRun, found := s.lastRunByName[name]
+			if test.expectedStatus != http.StatusSeeOther {
+				if !strings.Contains(rec.Body.String(), test.expectedError) {
+					t.Errorf("Response %q. Expected %q", rec.Body.String(), test.expectedError)
+				}
+				if found {
+					t.Errorf("Solver is run: %+v", lastRun)
+				}
+				return
+			}
+
+			location := rec.Header().Get("Location")
+			if location != solverURL(legacySolver(1, 1)) {
+				t.Errorf("Redirected to %s", location)
+			}
+			if !found {
+				t.Fatal("No run is recorded")
+			}
+			if lastRun.Error != "" || lastRun.Input != test.expectedInput ||
+				lastRun.Answer != test.expectedAnswer {
+				t.Errorf("Run %+v. Expected answer %s on %s", lastRun, test.expectedAnswer,
+					test.expectedInput)
+			}
+		})
+	}
+}
+
+func TestHandleVerify(t *testing.T) {
+	s := newTestServer(t)
+	name := legacySolver(1, 1).String()
+
+	rec := postForm(s.handleVerify, "/verify", url.Values{"name": {name}})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("Status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	checks := s.sampleChecksByName[name]
+	if len(checks) != 2 {
+		t.Fatalf("%d samples are checked. Expected 2", len(checks))
+	}
+	if check := checks[0]; !check.Passed || check.Run.Answer != "142" {
+		t.Errorf("Sample with the right answer: %+v", check)
+	}
+	if check := checks[1]; check.Passed || check.Run.Answer != "142" || check.Expected != "143" {
+		t.Errorf("Sample with a wrong answer: %+v", check)
+	}
+
+	view := s.solverView(legacySolver(1, 1))
+	if !view.SamplesChecked || view.SamplesPassedCount != 1 || view.SamplesCount != 2 {
+		t.Errorf("Unexpected view: %+v", view)
+	}
+
+	rec = postForm(s.handleVerify, "/verify", url.Values{"name": {"2023/01/part3/main"}})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status %d for unknown part. Expected %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleVerifyStopsOnTimeout(t *testing.T) {
+	s := newTestServer(t)
+	s.verifyTimeout = time.Nanosecond
+	name := legacySolver(1, 1).String()
+
+	rec := postForm(s.handleVerify, "/verify", url.Values{"name": {name}})
+	if rec.Code != http.StatusGatewayTimeout ||
+		!strings.Contains(rec.Body.String(), "Verification takes longer than 1ns") {
+		t.Errorf("Status %d: %s. Expected %d", rec.Code, rec.Body.String(),
+			http.StatusGatewayTimeout)
+	}
+	if checks, found := s.sampleChecksByName[name]; found {
+		t.Errorf("Samples are checked: %+v", checks)
+	}
+}
+
+func TestSplitOutput(t *testing.T) {
+	output := "Reading input\nTilted platform:\nO.#\n.O.\n..O\nLoad: 6\nab\ncd\nef\n"
+
+	blocks := splitOutput(output)
+	if len(blocks) != 3 || blocks[0].Text != "Reading input\nTilted platform:\n" ||
+		blocks[2].Text != "Load: 6\nab\ncd\nef\n" || blocks[0].IsGrid || blocks[2].IsGrid {
+		t.Fatalf("Unexpected blocks: %+v", blocks)
+	}
+
+	// dots are the background, then colours go from the most frequent character
+	grid := blocks[1]
+	expectedRows := [][]cellRun{
+		{{"O", 1}, {".", 0}, {"#", 2}},
+		{{".", 0}, {"O", 1}, {".", 0}},
+		{{"..", 0}, {"O", 1}},
+	}
+	if !grid.IsGrid || grid.Text != "O.#\n.O.\n..O\n" ||
+		!slices.EqualFunc(grid.GridRows, expectedRows, slices.Equal[[]cellRun]) {
+		t.Errorf("Grid %+v. Expected rows %v", grid, expectedRows)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// legacyLayoutYear is the year of the puzzles in the top-level DD/partN directories. Puzzles of
+// other years live in YYYY/DD/partN directories.
+const legacyLayoutYear = 2023
+
+// solver is an implementation of a puzzle part. A part may have several implementations, which
+// must give the same answers.
+type solver struct {
+	year, day, part uint
+	// name tells implementations of the same part apart
+	name string
+	// dir is the directory of the main package relative to the module root
+	dir string
+	// answerRegexp extracts the answer from the output into its first group. Nil means the last
+	// integer of the last non-empty line is the answer.
+	answerRegexp *regexp.Regexp
+}
+
+// answerRegexpsByDir are answer regexps of solvers printing more after the answer by their
+// directories
+var answerRegexpsByDir = map[string]*regexp.Regexp{
+	"10/part2": regexp.MustCompile(`(?m)^(\d+) enclosed tiles found`),
+}
+
+var lastIntegerRegexp = regexp.MustCompile(`(-?\d+)\D*$`)
+
+func (s solver) String() string {
+	return fmt.Sprintf("%d/%02d/part%d/%s", s.year, s.day, s.part, s.name)
+}
+
+// loadSolvers returns the solvers of both layouts. A part of the legacy layout year may be in
+// either of them, but not in both.
+func loadSolvers(moduleRoot string) ([]solver, error) {
+	legacySolvers, err := discoverSolvers(moduleRoot, true)
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := discoverSolvers(moduleRoot, false)
+	if err != nil {
+		return nil, err
+	}
+
+	all := legacySolvers
+	for _, s := range discovered {
+		legacyDuplicates := findSolvers(legacySolvers, s.year, s.day, s.part)
+		if len(legacyDuplicates) > 0 {
+			return nil, fmt.Errorf("Day %d part %d of %d is found in both layouts", s.day, s.part,
+				s.year)
+		}
+		all = append(all, s)
+	}
+	return all, nil
+}
+
+// discoverSolvers looks for DD/partN directories of the legacy layout or YYYY/DD/partN ones. A
+// main package in the part directory is the "main" implementation; main packages in its
+// subdirectories are named after them. Answers are expected in the last line of the output unless
+// answerRegexpsByDir has the directory.
+func discoverSolvers(moduleRoot string, legacy bool) ([]solver, error) {
+	pattern := filepath.Join(moduleRoot, "[0-9][0-9][0-9][0-9]", "[0-9][0-9]", "part[12]")
+	if legacy {
+		pattern = filepath.Join(moduleRoot, "[0-9][0-9]", "part[12]")
+	}
+	partDirs, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []solver
+	for _, partDir := range partDirs {
+		relPartDir, err := filepath.Rel(moduleRoot, partDir)
+		if err != nil {
+			return nil, err
+		}
+		dirs := strings.Split(relPartDir, string(filepath.Separator))
+		s := solver{year: legacyLayoutYear}
+		if !legacy {
+			s.year = util.ParseUintOrPanic(dirs[0])
+			dirs = dirs[1:]
+		}
+		s.day = util.ParseUintOrPanic(dirs[0])
+		s.part = util.ParseUintOrPanic(strings.TrimPrefix(dirs[1], "part"))
+
+		if isMainPackageDir(partDir) {
+			s.name, s.dir = "main", filepath.ToSlash(relPartDir)
+			s.answerRegexp = answerRegexpsByDir[s.dir]
+			found = append(found, s)
+		}
+
+		entries, err := os.ReadDir(partDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && isMainPackageDir(filepath.Join(partDir, entry.Name())) {
+				s.name = entry.Name()
+				s.dir = filepath.ToSlash(filepath.Join(relPartDir, entry.Name()))
+				s.answerRegexp = answerRegexpsByDir[s.dir]
+				found = append(found, s)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func isMainPackageDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "main.go"))
+	return err == nil
+}
+
+// findSolvers returns implementations of the day part. Part 0 means all parts of the day.
+func findSolvers(all []solver, year, day, part uint) []solver {
+	var found []solver
+	for _, s := range all {
+		if s.year == year && s.day == day && (part == 0 || s.part == part) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// dayDir returns the directory of the day relative to the module root
+func dayDir(year, day uint) string {
+	if year == legacyLayoutYear {
+		return fmt.Sprintf("%02d", day)
+	}
+	return fmt.Sprintf("%d/%02d", year, day)
+}
+
+// findModuleRoot looks for the directory containing go.mod starting from the working directory
+func findModuleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return "", errors.New("Module root isn't found; run aoc inside the repository")
+		}
+		dir = parentDir
+	}
+}
+
+// solverBinaries builds solvers on demand and keeps the binaries till it's closed
+type solverBinaries struct {
+	moduleRoot, binDir string
+
+	mu         sync.Mutex
+	pathByName map[string]string
+}
+
+func newSolverBinaries(moduleRoot string) (*solverBinaries, error) {
+	binDir, err := os.MkdirTemp("", "aoc-bin-")
+	if err != nil {
+		return nil, err
+	}
+	return &solverBinaries{moduleRoot: moduleRoot, binDir: binDir, pathByName: map[string]string{}},
+		nil
+}
+
+// get returns the path of the solver binary building it if needed
+func (b *solverBinaries) get(s solver) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if path, found := b.pathByName[s.String()]; found {
+		return path, nil
+	}
+
+	binPath := filepath.Join(b.binDir, fmt.Sprintf("%d-%02d-part%d-%s", s.year, s.day, s.part,
+		s.name))
+	cmd := exec.Command("go", "build", "-o", binPath, "./"+s.dir)
+	cmd.Dir = b.moduleRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Failed to build %s: %w\n%s", s, err, output)
+	}
+
+	b.pathByName[s.String()] = binPath
+	return binPath, nil
+}
+
+func (b *solverBinaries) close() {
+	os.RemoveAll(b.binDir)
+}
+
+// solverRun is the result of running a solver on an input
+type solverRun struct {
+	answer string
+	// output is the stdout of the solver followed by its stderr
+	output   string
+	duration time.Duration
+	// allocBytes and allocObjects are allocations reported by the solver; 0 if unknown
+	allocBytes, allocObjects uint64
+	// peakRSSKiB is the peak resident memory of the process; 0 if unknown
+	peakRSSKiB int64
+}
+
+// run runs the solver on the input file. The solver is killed once it takes the timeout or the
+// parent context is done. The output and the duration are returned even if the solver fails.
+func (s solver) run(
+	parent context.Context,
+	binaries *solverBinaries,
+	inputPath string,
+	timeout time.Duration,
+) (solverRun, error) {
+	binPath, err := binaries.get(s)
+	if err != nil {
+		return solverRun{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binPath, inputPath)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	cmd.Env = append(os.Environ(), util.AllocsReportEnvVar+"=1")
+
+	startTime := time.Now()
+	err = cmd.Run()
+	run := solverRun{duration: time.Since(startTime)}
+	solverStderr := run.parseAllocsReport(stderr.String())
+	run.output = stdout.String() + solverStderr
+	if cmd.ProcessState != nil {
+		run.peakRSSKiB = maxRSSKiB(cmd.ProcessState)
+	}
+
+	if err != nil {
+		if parent.Err() != nil {
+			return run, context.Cause(parent)
+		}
+		if ctx.Err() != nil {
+			return run, fmt.Errorf("Timed out after %s", timeout)
+		}
+		return run, fmt.Errorf("%w: %s", err, firstLine(solverStderr))
+	}
+
+	run.answer, err = s.extractAnswer(stdout.String())
+	return run, err
+}
+
+// parseAllocsReport takes allocations from the line of util.ReportAllocs in stderr. The rest of
+// stderr is returned.
+func (run *solverRun) parseAllocsReport(stderr string) string {
+	lines := strings.SplitAfter(stderr, "\n")
+	for lineIdx, line := range lines {
+		if !strings.HasPrefix(line, util.AllocsReportPrefix) {
+			continue
+		}
+		if _, err := fmt.Sscanf(line, util.AllocsReportFormat, &run.allocBytes,
+			&run.allocObjects); err != nil {
+			continue
+		}
+		return strings.Join(slices.Delete(lines, lineIdx, lineIdx+1), "")
+	}
+	return stderr
+}
+
+func (s solver) extractAnswer(output string) (string, error) {
+	if s.answerRegexp != nil {
+		match := s.answerRegexp.FindStringSubmatch(output)
+		if match == nil {
+			return "", fmt.Errorf("No answer matching %s found in the output", s.answerRegexp)
+		}
+		return match[1], nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	match := lastIntegerRegexp.FindStringSubmatch(lines[len(lines)-1])
+	if match == nil {
+		return "", fmt.Errorf("No answer found in the last output line: %s", lines[len(lines)-1])
+	}
+	return match[1], nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
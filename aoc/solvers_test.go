@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+func TestLoadSolvers(t *testing.T) {
+	solvers, err := loadSolvers("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every day has 2 parts but the last one, and day 21 part 2 has the geometric implementation
+	names := util.NewSet[string]()
+	for _, s := range solvers {
+		if names.Contains(s.String()) {
+			t.Errorf("Solver %s is found twice", s)
+		}
+		names.Add(s.String())
+	}
+	if len(solvers) != 2*25-1+1 || !names.Contains("2023/21/part2/geometric") {
+		t.Errorf("Solvers %v are found", solvers)
+	}
+
+	if s := legacySolver(10, 2); s.answerRegexp == nil {
+		t.Errorf("Solver %s has no answer regexp", s)
+	}
+}
+
+func TestExtractAnswer(t *testing.T) {
+	tests := []struct {
+		solver solver
+		output string
+		answer string
+	}{
+		{legacySolver(1, 1), "Reading input\nTotal sum: 142\n", "142"},
+		{legacySolver(9, 2), "Next values sum: -178\n\n", "-178"},
+		{legacySolver(24, 2), "Rock start coordinates: 24,13,10. Sum: 47\n", "47"},
+		{legacySolver(10, 2), "Path\n4 enclosed tiles found: 7:3, 7:4\n", "4"},
+	}
+
+	for _, test := range tests {
+		answer, err := test.solver.extractAnswer(test.output)
+		if err != nil {
+			t.Errorf("%s: %s", test.solver, err.Error())
+		} else if answer != test.answer {
+			t.Errorf("%s: Answer %s extracted. Expected %s", test.solver, answer, test.answer)
+		}
+	}
+
+	if _, err := legacySolver(1, 1).extractAnswer("No answer\n"); err == nil {
+		t.Error("No error for output without answer")
+	}
+}
+
+func TestParseAllocsReport(t *testing.T) {
+	var run solverRun
+	stderr := run.parseAllocsReport("Warning\naoc-allocs: bytes=2048 objects=16\npanic: x\n")
+	if stderr != "Warning\npanic: x\n" || run.allocBytes != 2048 || run.allocObjects != 16 {
+		t.Errorf("Stderr %q and allocations %+v are parsed", stderr, run)
+	}
+
+	run = solverRun{}
+	if stderr := run.parseAllocsReport("aoc-allocs: none\n"); stderr != "aoc-allocs: none\n" ||
+		run.allocBytes != 0 {
+		t.Errorf("Stderr %q and allocations %+v are parsed from a malformed report", stderr, run)
+	}
+}
+
+func TestRunReportsAllocs(t *testing.T) {
+	binaries, err := newSolverBinaries("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer binaries.close()
+
+	run, err := legacySolver(1, 1).run(context.Background(), binaries, "../01/part1/sample.txt",
+		time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.answer != "142" || run.allocBytes == 0 || run.allocObjects == 0 ||
+		strings.Contains(run.output, util.AllocsReportPrefix) {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+}
+
+func TestDiscoverSolvers(t *testing.T) {
+	moduleRoot := t.TempDir()
+	for _, dir := range []string{"2024/01/part1", "2024/01/part2", "2024/01/part2/fast",
+		"2024/02/part1"} {
+		writeTestFile(t, filepath.Join(moduleRoot, dir, "main.go"), "package main")
+	}
+	// directories out of the layout and without main packages are ignored
+	writeTestFile(t, filepath.Join(moduleRoot, "2024/02/part2/testdata/sample.txt"), "")
+	writeTestFile(t, filepath.Join(moduleRoot, "tools/01/part1/main.go"), "package main")
+
+	solvers, err := discoverSolvers(moduleRoot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range solvers {
+		names = append(names, s.String())
+	}
+	expectedNames := []string{"2024/01/part1/main", "2024/01/part2/main", "2024/01/part2/fast",
+		"2024/02/part1/main"}
+	if !slices.Equal(names, expectedNames) {
+		t.Errorf("Solvers %v are discovered. Expected %v", names, expectedNames)
+	}
+	if s := solvers[2]; s.year != 2024 || s.day != 1 || s.part != 2 ||
+		s.dir != "2024/01/part2/fast" {
+		t.Errorf("Unexpected solver: %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(moduleRoot, "03/part1/main.go"), "package main")
+	writeTestFile(t, filepath.Join(moduleRoot, "10/part2/main.go"), "package main")
+	solvers, err = loadSolvers(moduleRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names = nil
+	for _, s := range solvers {
+		names = append(names, s.String())
+	}
+	expectedNames = append([]string{"2023/03/part1/main", "2023/10/part2/main"}, expectedNames...)
+	if !slices.Equal(names, expectedNames) {
+		t.Errorf("Solvers %v are loaded. Expected %v", names, expectedNames)
+	}
+	if solvers[0].dir != "03/part1" || solvers[0].answerRegexp != nil ||
+		solvers[1].answerRegexp == nil {
+		t.Errorf("Unexpected legacy solvers: %+v", solvers[:2])
+	}
+
+	writeTestFile(t, filepath.Join(moduleRoot, "2023/03/part1/main.go"), "package main")
+	if _, err := loadSolvers(moduleRoot); err == nil {
+		t.Error("Part in both layouts is loaded")
+	}
+}
+
+func TestParseYear(t *testing.T) {
+	tests := []struct {
+		args     []string
+		year     uint
+		restArgs []string
+	}{
+		{[]string{"2024", "17", "2"}, 2024, []string{"17", "2"}},
+		{[]string{"17", "2"}, legacyLayoutYear, []string{"17", "2"}},
+		{[]string{"0012"}, legacyLayoutYear, []string{"0012"}},
+		{[]string{"1999", "2"}, legacyLayoutYear, []string{"1999", "2"}},
+		{nil, legacyLayoutYear, nil},
+	}
+
+	for _, test := range tests {
+		year, restArgs := parseYear(test.args)
+		if year != test.year || !slices.Equal(restArgs, test.restArgs) {
+			t.Errorf("%v: Year %d and args %v parsed. Expected %d and %v", test.args, year,
+				restArgs, test.year, test.restArgs)
+		}
+	}
+}
+
+// legacySolver returns the main implementation of the part in the legacy layout of the repository
+func legacySolver(day, part uint) solver {
+	solvers, err := discoverSolvers("..", true)
+	util.PanicOnError(err)
+	return findSolvers(solvers, legacyLayoutYear, day, part)[0]
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
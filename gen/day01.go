@@ -0,0 +1,55 @@
+package gen
+
+import (
+	"math/rand"
+	"strings"
+)
+
+var (
+	spelledDigits = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+	// overlapping spelled digits, which are easy to miss
+	overlappingDigits = []string{"oneight", "twone", "threeight", "fiveight", "sevenine", "eightwo",
+		"eighthree", "nineight"}
+)
+
+func generateDay01(rng *rand.Rand, size uint) string {
+	lines := make([]string, 0, size)
+	for i := uint(0); i < size; i++ {
+		var tokens []string
+		hasDigit := false
+
+		tokensCount := randRange(rng, 1, 8)
+		for j := 0; j < tokensCount; j++ {
+			switch rng.Intn(5) {
+			case 0:
+				tokens = append(tokens, string(byte('1'+rng.Intn(9))))
+				hasDigit = true
+			case 1:
+				tokens = append(tokens, randomItem(rng, spelledDigits))
+			case 2:
+				tokens = append(tokens, randomItem(rng, overlappingDigits))
+			default:
+				tokens = append(tokens, randomLetters(rng, randRange(rng, 1, 5)))
+			}
+		}
+
+		// part 1 expects at least one digit in every line
+		if !hasDigit {
+			digitIdx := rng.Intn(len(tokens) + 1)
+			digit := string(byte('1' + rng.Intn(9)))
+			tokens = append(tokens[:digitIdx], append([]string{digit}, tokens[digitIdx:]...)...)
+		}
+
+		lines = append(lines, strings.Join(tokens, ""))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func randomLetters(rng *rand.Rand, count int) string {
+	letters := make([]byte, 0, count)
+	for i := 0; i < count; i++ {
+		letters = append(letters, byte('a'+rng.Intn(26)))
+	}
+	return string(letters)
+}
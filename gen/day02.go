@@ -0,0 +1,33 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var cubeColors = []string{"red", "green", "blue"}
+
+func generateDay02(rng *rand.Rand, size uint) string {
+	lines := make([]string, 0, size)
+	for gameID := uint(1); gameID <= size; gameID++ {
+		roundsCount := randRange(rng, 1, 6)
+		rounds := make([]string, 0, roundsCount)
+
+		for i := 0; i < roundsCount; i++ {
+			colors := append([]string{}, cubeColors...)
+			shuffle(rng, colors)
+			colors = colors[:randRange(rng, 1, len(colors))]
+
+			cubes := make([]string, 0, len(colors))
+			for _, color := range colors {
+				cubes = append(cubes, fmt.Sprintf("%d %s", randRange(rng, 1, 20), color))
+			}
+			rounds = append(rounds, strings.Join(cubes, ", "))
+		}
+
+		lines = append(lines, fmt.Sprintf("Game %d: %s", gameID, strings.Join(rounds, "; ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const schematicSymbols = "*#+$/=%@&-"
+
+func generateDay03(rng *rand.Rand, size uint) string {
+	grid := randomGrid(size, func() byte {
+		return '.'
+	})
+
+	for _, row := range grid {
+		for colIdx := 0; colIdx < len(row); colIdx++ {
+			if rng.Intn(6) != 0 {
+				continue
+			}
+
+			number := fmt.Sprint(randRange(rng, 1, 999))
+			if colIdx+len(number) > len(row) {
+				break
+			}
+
+			copy(row[colIdx:], number)
+			// keep at least one char between numbers
+			colIdx += len(number)
+		}
+	}
+
+	for _, row := range grid {
+		for colIdx, char := range row {
+			if char == '.' && rng.Intn(12) == 0 {
+				// gears are the most interesting symbols, so they are picked more often
+				if rng.Intn(2) == 0 {
+					row[colIdx] = '*'
+				} else {
+					row[colIdx] = schematicSymbols[rng.Intn(len(schematicSymbols))]
+				}
+			}
+		}
+	}
+
+	return formatGrid(grid)
+}
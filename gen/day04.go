@@ -0,0 +1,59 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	winningNumbersCount = 10
+	cardNumbersCount    = 25
+	maxCardNumber       = 99
+
+	// cards having more copies don't win anything to keep the total count of cards reasonable
+	maxCopiesOfWinningCard = 1_000_000
+)
+
+func generateDay04(rng *rand.Rand, size uint) string {
+	idWidth := len(fmt.Sprint(size))
+	copies := make([]uint, size)
+	lines := make([]string, 0, size)
+
+	for cardIdx := uint(0); cardIdx < size; cardIdx++ {
+		copies[cardIdx]++
+
+		var matches uint
+		if rng.Intn(2) == 0 && copies[cardIdx] <= maxCopiesOfWinningCard {
+			matches = uint(randRange(rng, 1, winningNumbersCount))
+			// cards never make you win cards past the end of the table
+			matches = min(matches, size-cardIdx-1)
+		}
+		for i := cardIdx + 1; i <= cardIdx+matches; i++ {
+			copies[i] += copies[cardIdx]
+		}
+
+		numbers := rng.Perm(maxCardNumber)
+		for i := range numbers {
+			numbers[i]++
+		}
+		winningNumbers := numbers[:winningNumbersCount]
+		cardNumbers := append([]int{}, winningNumbers[:matches]...)
+		cardNumbers = append(cardNumbers,
+			numbers[winningNumbersCount:winningNumbersCount+cardNumbersCount-int(matches)]...)
+		shuffle(rng, cardNumbers)
+
+		lines = append(lines, fmt.Sprintf("Card %*d: %s | %s", idWidth, cardIdx+1,
+			formatCardNumbers(winningNumbers), formatCardNumbers(cardNumbers)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatCardNumbers(numbers []int) string {
+	strs := make([]string, 0, len(numbers))
+	for _, num := range numbers {
+		strs = append(strs, fmt.Sprintf("%2d", num))
+	}
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+var almanacMapLabels = []string{"seed-to-soil", "soil-to-fertilizer", "fertilizer-to-water",
+	"water-to-light", "light-to-temperature", "temperature-to-humidity", "humidity-to-location"}
+
+const almanacAvgRangeLength = 1000
+
+// generateDay05 generates maps which are bijections of [0, size*almanacAvgRangeLength). Every map
+// splits the interval into size ranges and shuffles them.
+func generateDay05(rng *rand.Rand, size uint) string {
+	universeSize := size * almanacAvgRangeLength
+
+	seedRangesCount := uint(randRange(rng, 1, int(min(size, 5))))
+	seedRangeMaxLength := universeSize / 2 / seedRangesCount
+	var seeds []string
+	for i := uint(0); i < seedRangesCount; i++ {
+		length := uint(randRange(rng, 1, int(seedRangeMaxLength)))
+		start := uint(rng.Intn(int(universeSize - length + 1)))
+		seeds = append(seeds, fmt.Sprint(start), fmt.Sprint(length))
+	}
+
+	sections := []string{"seeds: " + strings.Join(seeds, " ")}
+	for _, label := range almanacMapLabels {
+		sections = append(sections, generateAlmanacMap(rng, label, size, universeSize))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func generateAlmanacMap(rng *rand.Rand, label string, rangesCount, universeSize uint) string {
+	// split the universe into ranges by random cut points
+	cutPoints := util.NewSet[uint](0, universeSize)
+	for uint(len(cutPoints)) < rangesCount+1 {
+		cutPoints.Add(uint(randRange(rng, 1, int(universeSize-1))))
+	}
+	cuts := util.SortedSetItems(cutPoints)
+
+	type sourceRange struct {
+		start, length uint
+	}
+	ranges := make([]sourceRange, 0, rangesCount)
+	for i := 0; i < len(cuts)-1; i++ {
+		ranges = append(ranges, sourceRange{cuts[i], cuts[i+1] - cuts[i]})
+	}
+
+	destOrder := rng.Perm(len(ranges))
+	lines := []string{label + " map:"}
+	var destStart uint
+	for _, rangeIdx := range destOrder {
+		r := ranges[rangeIdx]
+		// identity ranges are sometimes omitted, as they are the default mapping
+		if r.start != destStart || rng.Intn(2) == 0 {
+			lines = append(lines, fmt.Sprintf("%d %d %d", destStart, r.start, r.length))
+		}
+		destStart += r.length
+	}
+	shuffle(rng, lines[1:])
+
+	return strings.Join(lines, "\n")
+}
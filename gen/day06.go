@@ -0,0 +1,26 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// generateDay06 keeps times two-digit and distances four-digit, so the concatenated race of part 2
+// is winnable and fits uint64
+func generateDay06(rng *rand.Rand, size uint) string {
+	var times, distances []string
+	for i := uint(0); i < size; i++ {
+		time := randRange(rng, 64, 99)
+		maxDistance := (time / 2) * (time - time/2)
+		// the record may be just one less than the max distance, so only one way to win exists
+		distance := randRange(rng, 1000, maxDistance-1)
+
+		width := len(fmt.Sprint(distance))
+		times = append(times, fmt.Sprintf("%*d", width, time))
+		distances = append(distances, fmt.Sprint(distance))
+	}
+
+	return fmt.Sprintf("Time:      %s\nDistance:  %s", strings.Join(times, "   "),
+		strings.Join(distances, "   "))
+}
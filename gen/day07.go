@@ -0,0 +1,47 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	camelCards      = "23456789TJQKA"
+	camelJoker      = 'J'
+	handSize        = 5
+	maxHandBid      = 1000
+	jokerHandsRatio = 10
+)
+
+func generateDay07(rng *rand.Rand, size uint) string {
+	hands := util.NewSet[string]()
+	lines := make([]string, 0, size)
+
+	for uint(len(lines)) < size {
+		hand := make([]byte, handSize)
+		for i := range hand {
+			hand[i] = camelCards[rng.Intn(len(camelCards))]
+		}
+
+		// hands made mostly of jokers are rare in uniformly random hands
+		if rng.Intn(jokerHandsRatio) == 0 {
+			jokersCount := randRange(rng, 3, handSize)
+			for _, i := range rng.Perm(handSize)[:jokersCount] {
+				hand[i] = camelJoker
+			}
+		}
+
+		handStr := string(hand)
+		if hands.Contains(handStr) {
+			continue
+		}
+		hands.Add(handStr)
+
+		lines = append(lines, fmt.Sprintf("%s %d", handStr, randRange(rng, 1, maxHandBid)))
+	}
+
+	return strings.Join(lines, "\n")
+}
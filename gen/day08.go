@@ -0,0 +1,72 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	networkNodeLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	networkStartNode   = "AAA"
+	networkFinishNode  = "ZZZ"
+)
+
+// generateDay08 builds a cycle for every ghost. Cycle length is a product of the commands count and
+// a distinct prime, and the only finish node of the cycle is its last node, so ghosts meet finish
+// nodes periodically. Only the side a command selects leads along the cycle; the other side leads
+// to a random node. The first ghost starts at AAA and finishes at ZZZ.
+func generateDay08(rng *rand.Rand, size uint) string {
+	commandsCount := randRange(rng, 5, 30)
+	commands := make([]byte, commandsCount)
+	for i := range commands {
+		commands[i] = "LR"[rng.Intn(2)]
+	}
+
+	primes := primesBetween(11, 61)
+	shuffle(rng, primes)
+
+	var nodesCount uint
+	for _, prime := range primes[:size] {
+		nodesCount += prime * uint(commandsCount)
+	}
+	names := uniqueNames(rng, nodesCount, networkNodeLetters, 3, func(name string) bool {
+		return !strings.HasSuffix(name, "A") && !strings.HasSuffix(name, "Z")
+	})
+	startNames := uniqueNames(rng, size, networkNodeLetters, 2, func(name string) bool {
+		return name != "AA" && name != "ZZ"
+	})
+
+	var lines []string
+	addNode := func(name, left, right string) {
+		lines = append(lines, fmt.Sprintf("%s = (%s, %s)", name, left, right))
+	}
+
+	for ghostIdx, prime := range primes[:size] {
+		cycleLength := int(prime) * commandsCount
+		cycle := names[:cycleLength]
+		names = names[cycleLength:]
+
+		startName := startNames[ghostIdx] + "A"
+		finishName := startNames[ghostIdx] + "Z"
+		if ghostIdx == 0 {
+			startName, finishName = networkStartNode, networkFinishNode
+		}
+		cycle[cycleLength-1] = finishName
+
+		addNode(startName, cycle[0], cycle[0])
+		for nodeIdx, name := range cycle {
+			next := cycle[(nodeIdx+1)%cycleLength]
+			other := randomItem(rng, cycle)
+			// node with index i is reached after i+1 steps
+			if commands[(nodeIdx+1)%commandsCount] == 'L' {
+				addNode(name, next, other)
+			} else {
+				addNode(name, other, next)
+			}
+		}
+	}
+	shuffle(rng, lines)
+
+	return string(commands) + "\n\n" + strings.Join(lines, "\n")
+}
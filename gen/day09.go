@@ -0,0 +1,41 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const historyLength = 21
+
+// generateDay09 builds every history bottom-up from a constant row of differences, so it is
+// a polynomial sequence the extrapolation works for
+func generateDay09(rng *rand.Rand, size uint) string {
+	lines := make([]string, 0, size)
+	for i := uint(0); i < size; i++ {
+		degree := randRange(rng, 0, 10)
+
+		row := make([]int, historyLength-degree)
+		constant := randRange(rng, -5, 5)
+		for j := range row {
+			row[j] = constant
+		}
+
+		for level := degree - 1; level >= 0; level-- {
+			upperRow := make([]int, 0, historyLength-level)
+			upperRow = append(upperRow, randRange(rng, -20, 20))
+			for _, diff := range row {
+				upperRow = append(upperRow, upperRow[len(upperRow)-1]+diff)
+			}
+			row = upperRow
+		}
+
+		values := make([]string, 0, len(row))
+		for _, value := range row {
+			values = append(values, fmt.Sprint(value))
+		}
+		lines = append(lines, strings.Join(values, " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
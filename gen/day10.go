@@ -0,0 +1,67 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	pipeJunkChars = "|-LJ7F."
+	pipeStartChar = 'S'
+)
+
+// pipeCharByDirections maps directions to the previous and the next tiles of the loop to a pipe
+var pipeCharByDirections = map[[2]point]byte{}
+
+func init() {
+	up, right, down, left := point{-1, 0}, point{0, 1}, point{1, 0}, point{0, -1}
+	for char, dirs := range map[byte][2]point{
+		'|': {up, down},
+		'-': {left, right},
+		'L': {up, right},
+		'J': {up, left},
+		'7': {down, left},
+		'F': {down, right},
+	} {
+		pipeCharByDirections[dirs] = char
+		pipeCharByDirections[[2]point{dirs[1], dirs[0]}] = char
+	}
+}
+
+// generateDay10 lays the loop along the boundary of a random polyomino, so the loop never crosses
+// or touches itself
+func generateDay10(rng *rand.Rand, size uint) string {
+	side := int(size)
+	cellsSide := side - 1
+	loop := polyominoBoundary(randomPolyomino(rng, cellsSide, cellsSide,
+		randRange(rng, 1, cellsSide*cellsSide*2/3)))
+
+	field := randomGrid(size, func() byte {
+		return pipeJunkChars[rng.Intn(len(pipeJunkChars))]
+	})
+
+	loopTiles := util.NewSet(loop...)
+	for i, tile := range loop {
+		prev := loop[(i+len(loop)-1)%len(loop)]
+		next := loop[(i+1)%len(loop)]
+		dirs := [2]point{
+			{prev.row - tile.row, prev.col - tile.col},
+			{next.row - tile.row, next.col - tile.col},
+		}
+		field[tile.row][tile.col] = pipeCharByDirections[dirs]
+	}
+
+	// junk next to the start must not look connected to it
+	start := randomItem(rng, loop)
+	field[start.row][start.col] = pipeStartChar
+	for i := 1; i < len(neighbourDiffs); i += 2 {
+		neighbour := point{start.row + neighbourDiffs[i].row, start.col + neighbourDiffs[i].col}
+		if neighbour.row >= 0 && neighbour.row < side && neighbour.col >= 0 && neighbour.col < side &&
+			!loopTiles.Contains(neighbour) {
+			field[neighbour.row][neighbour.col] = '.'
+		}
+	}
+
+	return formatGrid(field)
+}
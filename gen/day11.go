@@ -0,0 +1,42 @@
+package gen
+
+import (
+	"math/rand"
+)
+
+const (
+	galaxyChar      = '#'
+	emptySpaceChar  = '.'
+	emptyLinesRatio = 10
+)
+
+// generateDay11 fills about a tenth of rows and columns with empty space only, so there is space
+// to expand
+func generateDay11(rng *rand.Rand, size uint) string {
+	galaxyPercent := randRange(rng, 2, 5)
+	image := randomGrid(size, func() byte {
+		if rng.Intn(100) < galaxyPercent {
+			return galaxyChar
+		}
+		return emptySpaceChar
+	})
+
+	for i := range image {
+		if rng.Intn(emptyLinesRatio) == 0 {
+			for j := range image {
+				image[i][j] = emptySpaceChar
+			}
+		}
+		if rng.Intn(emptyLinesRatio) == 0 {
+			for j := range image {
+				image[j][i] = emptySpaceChar
+			}
+		}
+	}
+
+	// at least one pair of galaxies is needed
+	image[0][0] = galaxyChar
+	image[size-1][size-1] = galaxyChar
+
+	return formatGrid(image)
+}
@@ -0,0 +1,56 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	maxRecordLength   = 20
+	springOperational = '.'
+	springDamaged     = '#'
+	springUnknown     = '?'
+)
+
+// generateDay12 builds every record from a known row of springs, so at least one arrangement
+// always matches the checksum
+func generateDay12(rng *rand.Rand, size uint) string {
+	lines := make([]string, 0, size)
+	for len(lines) < cap(lines) {
+		length := randRange(rng, 1, maxRecordLength)
+		row := make([]byte, length)
+		for i := range row {
+			row[i] = springOperational
+		}
+
+		var groups []string
+		for i := rng.Intn(min(length, 3)); i < length; {
+			groupLength := randRange(rng, 1, min(length-i, 6))
+			for j := i; j < i+groupLength; j++ {
+				row[j] = springDamaged
+			}
+			groups = append(groups, fmt.Sprint(groupLength))
+			i += groupLength + randRange(rng, 1, 4)
+		}
+
+		unknownPercent := randRange(rng, 30, 70)
+		for i := range row {
+			if rng.Intn(100) < unknownPercent {
+				row[i] = springUnknown
+			}
+		}
+		// long runs of unknown springs have the most arrangements
+		if rng.Intn(4) == 0 {
+			from := rng.Intn(length)
+			to := randRange(rng, from, length-1)
+			for i := from; i <= to; i++ {
+				row[i] = springUnknown
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s", row, strings.Join(groups, ",")))
+	}
+
+	return strings.Join(lines, "\n")
+}
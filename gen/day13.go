@@ -0,0 +1,155 @@
+package gen
+
+import (
+	"math/rand"
+	"strings"
+)
+
+const (
+	patternAsh  = '.'
+	patternRock = '#'
+)
+
+type mirrorLine struct {
+	isVertical bool
+	// idx is the number of rows or columns above or to the left of the line
+	idx int
+}
+
+// generateDay13 builds patterns symmetric against a horizontal and a vertical line and then breaks
+// the vertical symmetry by a single smudge. A pattern is accepted only when its mirror line and
+// the mirror line revealed by fixing the smudge are both unambiguous.
+func generateDay13(rng *rand.Rand, size uint) string {
+	patterns := make([]string, 0, size)
+	for len(patterns) < cap(patterns) {
+		pattern, oldLine, newLine := randomSmudgedPattern(rng)
+		if !hasUnambiguousMirrors(pattern, oldLine, newLine) {
+			continue
+		}
+
+		if rng.Intn(2) == 0 {
+			pattern = transposeGrid(pattern)
+		}
+		patterns = append(patterns, formatGrid(pattern))
+	}
+
+	return strings.Join(patterns, "\n\n")
+}
+
+func randomSmudgedPattern(rng *rand.Rand) ([][]byte, mirrorLine, mirrorLine) {
+	rowsCount, colsCount := randRange(rng, 5, 17), randRange(rng, 5, 17)
+	pattern := make([][]byte, rowsCount)
+	for rowIdx := range pattern {
+		pattern[rowIdx] = make([]byte, colsCount)
+		for colIdx := range pattern[rowIdx] {
+			pattern[rowIdx][colIdx] = "#."[rng.Intn(2)]
+		}
+	}
+
+	// some rows must stay out of the horizontal reflection to hide the smudge there
+	rowsAbove := randRange(rng, 1, rowsCount-1)
+	for rowsAbove*2 == rowsCount {
+		rowsAbove = randRange(rng, 1, rowsCount-1)
+	}
+	colsToLeft := randRange(rng, 1, colsCount-1)
+
+	for rowIdx := 0; rowIdx < rowsAbove; rowIdx++ {
+		if mirrorRowIdx := rowsAbove*2 - 1 - rowIdx; mirrorRowIdx < rowsCount {
+			copy(pattern[mirrorRowIdx], pattern[rowIdx])
+		}
+	}
+	for colIdx := 0; colIdx < colsToLeft; colIdx++ {
+		if mirrorColIdx := colsToLeft*2 - 1 - colIdx; mirrorColIdx < colsCount {
+			for _, row := range pattern {
+				row[mirrorColIdx] = row[colIdx]
+			}
+		}
+	}
+
+	var freeRows []int
+	for rowIdx := range pattern {
+		mirrorRowIdx := rowsAbove*2 - 1 - rowIdx
+		if mirrorRowIdx < 0 || mirrorRowIdx >= rowsCount {
+			freeRows = append(freeRows, rowIdx)
+		}
+	}
+	reflectedColsCount := min(colsToLeft, colsCount-colsToLeft) * 2
+	smudgeColIdx := colsToLeft - reflectedColsCount/2 + rng.Intn(reflectedColsCount)
+	flipPatternCell(pattern, randomItem(rng, freeRows), smudgeColIdx)
+
+	return pattern, mirrorLine{false, rowsAbove}, mirrorLine{true, colsToLeft}
+}
+
+// hasUnambiguousMirrors checks that oldLine is the only mirror line of the pattern and newLine is
+// the only new mirror line any single smudge fix reveals
+func hasUnambiguousMirrors(pattern [][]byte, oldLine, newLine mirrorLine) bool {
+	lines := findMirrorLines(pattern)
+	if len(lines) != 1 || lines[0] != oldLine {
+		return false
+	}
+
+	newLineFound := false
+	for rowIdx, row := range pattern {
+		for colIdx := range row {
+			flipPatternCell(pattern, rowIdx, colIdx)
+			lines := findMirrorLines(pattern)
+			flipPatternCell(pattern, rowIdx, colIdx)
+
+			for _, line := range lines {
+				if line == newLine {
+					newLineFound = true
+				} else if line != oldLine {
+					return false
+				}
+			}
+		}
+	}
+
+	return newLineFound
+}
+
+func findMirrorLines(pattern [][]byte) []mirrorLine {
+	var lines []mirrorLine
+	for rowsAbove := 1; rowsAbove < len(pattern); rowsAbove++ {
+		if isMirrorLine(pattern, rowsAbove) {
+			lines = append(lines, mirrorLine{false, rowsAbove})
+		}
+	}
+
+	transposed := transposeGrid(pattern)
+	for colsToLeft := 1; colsToLeft < len(transposed); colsToLeft++ {
+		if isMirrorLine(transposed, colsToLeft) {
+			lines = append(lines, mirrorLine{true, colsToLeft})
+		}
+	}
+
+	return lines
+}
+
+func isMirrorLine(pattern [][]byte, rowsAbove int) bool {
+	for i := 0; i < rowsAbove && rowsAbove+i < len(pattern); i++ {
+		if string(pattern[rowsAbove-1-i]) != string(pattern[rowsAbove+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func flipPatternCell(pattern [][]byte, rowIdx, colIdx int) {
+	if pattern[rowIdx][colIdx] == patternAsh {
+		pattern[rowIdx][colIdx] = patternRock
+	} else {
+		pattern[rowIdx][colIdx] = patternAsh
+	}
+}
+
+func transposeGrid(grid [][]byte) [][]byte {
+	transposed := make([][]byte, len(grid[0]))
+	for colIdx := range transposed {
+		transposed[colIdx] = make([]byte, len(grid))
+		for rowIdx := range grid {
+			transposed[colIdx][rowIdx] = grid[rowIdx][colIdx]
+		}
+	}
+	return transposed
+}
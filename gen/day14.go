@@ -0,0 +1,18 @@
+package gen
+
+import (
+	"math/rand"
+)
+
+func generateDay14(rng *rand.Rand, size uint) string {
+	return formatGrid(randomGrid(size, func() byte {
+		switch n := rng.Intn(100); {
+		case n < 25:
+			return 'O'
+		case n < 40:
+			return '#'
+		default:
+			return '.'
+		}
+	}))
+}
@@ -0,0 +1,27 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// generateDay15 reuses a limited set of labels, so lenses get replaced and removed often
+func generateDay15(rng *rand.Rand, size uint) string {
+	var labels []string
+	for i := uint(0); i < size/3+1; i++ {
+		labels = append(labels, randomLetters(rng, randRange(rng, 1, 6)))
+	}
+
+	steps := make([]string, 0, size)
+	for len(steps) < cap(steps) {
+		label := randomItem(rng, labels)
+		if rng.Intn(10) < 3 {
+			steps = append(steps, label+"-")
+		} else {
+			steps = append(steps, fmt.Sprintf("%s=%d", label, randRange(rng, 1, 9)))
+		}
+	}
+
+	return strings.Join(steps, ",")
+}
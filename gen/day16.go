@@ -0,0 +1,16 @@
+package gen
+
+import (
+	"math/rand"
+)
+
+const contraptionDevices = `/\-|`
+
+func generateDay16(rng *rand.Rand, size uint) string {
+	return formatGrid(randomGrid(size, func() byte {
+		if rng.Intn(100) < 88 {
+			return '.'
+		}
+		return contraptionDevices[rng.Intn(len(contraptionDevices))]
+	}))
+}
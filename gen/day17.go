@@ -0,0 +1,11 @@
+package gen
+
+import (
+	"math/rand"
+)
+
+func generateDay17(rng *rand.Rand, size uint) string {
+	return formatGrid(randomGrid(size, func() byte {
+		return byte('1' + rng.Intn(9))
+	}))
+}
@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const maxDigLengthHex = 0xFFFFF
+
+// digDirections is ordered by the direction digit of the hex colour code
+var digDirections = []struct {
+	name string
+	diff point
+}{
+	{"R", point{0, 1}},
+	{"D", point{1, 0}},
+	{"L", point{0, -1}},
+	{"U", point{-1, 0}},
+}
+
+// generateDay18 digs along the boundary of a random polyomino. Its coordinates are stretched
+// differently for the plan and for the hex codes, so the lagoons of both parts are simple polygons
+// of different scale.
+func generateDay18(rng *rand.Rand, size uint) string {
+	side := int(size)
+	corners := polygonCorners(polyominoBoundary(randomPolyomino(rng, side, side,
+		randRange(rng, 1, max(1, side*side*2/3)))))
+
+	planCoords := randomCoordsStretch(rng, side+1, 10)
+	hexCoords := randomCoordsStretch(rng, side+1, maxDigLengthHex/(side+1))
+
+	lines := make([]string, 0, len(corners))
+	for i, from := range corners {
+		to := corners[(i+1)%len(corners)]
+		directionIdx := digDirectionIdx(from, to)
+
+		planLength := planCoords[to.row] - planCoords[from.row] + planCoords[to.col] -
+			planCoords[from.col]
+		hexLength := hexCoords[to.row] - hexCoords[from.row] + hexCoords[to.col] - hexCoords[from.col]
+		lines = append(lines, fmt.Sprintf("%s %d (#%05x%d)", digDirections[directionIdx].name,
+			abs(planLength), abs(hexLength), directionIdx))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// polygonCorners drops the boundary points lying in the middle of straight edges. The first
+// returned point is a corner too.
+func polygonCorners(boundary []point) []point {
+	var corners []point
+	for i, p := range boundary {
+		prev := boundary[(i+len(boundary)-1)%len(boundary)]
+		next := boundary[(i+1)%len(boundary)]
+		if digDirectionIdx(prev, p) != digDirectionIdx(p, next) {
+			corners = append(corners, p)
+		}
+	}
+	return corners
+}
+
+func digDirectionIdx(from, to point) int {
+	for idx, direction := range digDirections {
+		rowDiff, colDiff := to.row-from.row, to.col-from.col
+		if sign(rowDiff) == direction.diff.row && sign(colDiff) == direction.diff.col {
+			return idx
+		}
+	}
+	panic(fmt.Errorf("Points %v and %v aren't on a straight line", from, to))
+}
+
+// randomCoordsStretch maps count consecutive coordinates to increasing ones with random gaps of up
+// to maxGap between them
+func randomCoordsStretch(rng *rand.Rand, count, maxGap int) []int {
+	coords := make([]int, count)
+	for i := 1; i < count; i++ {
+		coords[i] = coords[i-1] + randRange(rng, 1, maxGap)
+	}
+	return coords
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	workflowNameLetters = "abcdefghijklmnopqrstuvwxyz"
+	partsCount          = 200
+	maxRatingValue      = 4000
+	ratingCategories    = "xmas"
+)
+
+// generateDay19 builds workflows as a tree rooted at the "in" workflow, so no part ever gets into
+// a cycle
+func generateDay19(rng *rand.Rand, size uint) string {
+	names := uniqueNames(rng, size-1, workflowNameLetters, 3, anyName)
+	queue := []string{"in"}
+
+	var workflows []string
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		pickTarget := func() string {
+			if len(names) > 0 && rng.Intn(10) < 6 {
+				child := names[0]
+				names = names[1:]
+				queue = append(queue, child)
+				return child
+			}
+			return randomItem(rng, []string{"A", "R"})
+		}
+
+		rulesCount := randRange(rng, 1, 4)
+		rules := make([]string, 0, rulesCount+1)
+		for i := 0; i < rulesCount; i++ {
+			rules = append(rules, fmt.Sprintf("%c%c%d:%s", ratingCategories[rng.Intn(len(ratingCategories))],
+				"<>"[rng.Intn(2)], randRange(rng, 1, maxRatingValue), pickTarget()))
+		}
+		rules = append(rules, pickTarget())
+
+		workflows = append(workflows, fmt.Sprintf("%s{%s}", name, strings.Join(rules, ",")))
+	}
+	shuffle(rng, workflows)
+
+	parts := make([]string, 0, partsCount)
+	for len(parts) < cap(parts) {
+		parts = append(parts, fmt.Sprintf("{x=%d,m=%d,a=%d,s=%d}", randRange(rng, 1, maxRatingValue),
+			randRange(rng, 1, maxRatingValue), randRange(rng, 1, maxRatingValue),
+			randRange(rng, 1, maxRatingValue)))
+	}
+
+	return strings.Join(workflows, "\n") + "\n\n" + strings.Join(parts, "\n")
+}
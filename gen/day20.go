@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	counterBitsCount  = 12
+	moduleNameLetters = "abcdefghijklmnopqrstuvwxyz"
+)
+
+// generateDay20 wires modules the way the original inputs do. Every counter is a chain of
+// flip-flops and a conjunction, which resets the chain and sends a high pulse to the final
+// conjunction after a prime number of button presses. The final conjunction feeds rx, so rx gets a
+// low pulse after the product of the primes.
+func generateDay20(rng *rand.Rand, size uint) string {
+	primes := primesBetween(1<<(counterBitsCount-1)+1, 1<<counterBitsCount-1)
+	shuffle(rng, primes)
+
+	names := uniqueNames(rng, size*(counterBitsCount+2)+1, moduleNameLetters, 2,
+		func(name string) bool {
+			return name != "rx"
+		})
+	takeName := func() string {
+		name := names[0]
+		names = names[1:]
+		return name
+	}
+
+	var lines []string
+	var broadcasterOutputs []string
+	finalName := takeName()
+
+	for _, period := range primes[:size] {
+		bitNames := make([]string, counterBitsCount)
+		for i := range bitNames {
+			bitNames[i] = takeName()
+		}
+		resetterName, inverterName := takeName(), takeName()
+
+		resetterOutputs := []string{bitNames[0]}
+		for i, bitName := range bitNames {
+			var outputs []string
+			if i < counterBitsCount-1 {
+				outputs = append(outputs, bitNames[i+1])
+			}
+
+			if period&(1<<i) != 0 {
+				outputs = append(outputs, resetterName)
+			} else {
+				resetterOutputs = append(resetterOutputs, bitName)
+			}
+			shuffle(rng, outputs)
+
+			lines = append(lines, fmt.Sprintf("%%%s -> %s", bitName, strings.Join(outputs, ", ")))
+		}
+		resetterOutputs = append(resetterOutputs, inverterName)
+		shuffle(rng, resetterOutputs)
+
+		lines = append(lines,
+			fmt.Sprintf("&%s -> %s", resetterName, strings.Join(resetterOutputs, ", ")),
+			fmt.Sprintf("&%s -> %s", inverterName, finalName))
+		broadcasterOutputs = append(broadcasterOutputs, bitNames[0])
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("broadcaster -> %s", strings.Join(broadcasterOutputs, ", ")),
+		fmt.Sprintf("&%s -> rx", finalName))
+	shuffle(rng, lines)
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	gardenPlot  = '.'
+	gardenRock  = '#'
+	gardenStart = 'S'
+)
+
+// generateDay21 keeps the row and the column of the start and the garden border free of rocks,
+// like the original input does. Plots unreachable from the start are filled with rocks.
+func generateDay21(rng *rand.Rand, size uint) string {
+	side := int(size | 1)
+	center := side / 2
+
+	garden := randomGrid(uint(side), func() byte {
+		if rng.Intn(10) == 0 {
+			return gardenRock
+		}
+		return gardenPlot
+	})
+	for i := 0; i < side; i++ {
+		garden[center][i], garden[i][center] = gardenPlot, gardenPlot
+		garden[0][i], garden[side-1][i] = gardenPlot, gardenPlot
+		garden[i][0], garden[i][side-1] = gardenPlot, gardenPlot
+	}
+
+	reachable := util.NewSet(point{center, center})
+	queue := []point{{center, center}}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		for i := 1; i < len(neighbourDiffs); i += 2 {
+			next := point{p.row + neighbourDiffs[i].row, p.col + neighbourDiffs[i].col}
+			if next.row >= 0 && next.row < side && next.col >= 0 && next.col < side &&
+				garden[next.row][next.col] == gardenPlot && !reachable.Contains(next) {
+				reachable.Add(next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	for rowIdx, row := range garden {
+		for colIdx := range row {
+			if !reachable.Contains(point{rowIdx, colIdx}) {
+				row[colIdx] = gardenRock
+			}
+		}
+	}
+	garden[center][center] = gardenStart
+
+	return formatGrid(garden)
+}
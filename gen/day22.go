@@ -0,0 +1,64 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	bricksAreaSide  = 10
+	maxBrickLength  = 4
+	bricksPerZLevel = 4
+)
+
+type cube struct {
+	x, y, z int
+}
+
+// generateDay22 places bricks in a 10x10 column without overlaps, so the snapshot is valid
+func generateDay22(rng *rand.Rand, size uint) string {
+	occupied := util.NewSet[cube]()
+	maxZ := int(size)/bricksPerZLevel + 1
+
+	lines := make([]string, 0, size)
+	for len(lines) < cap(lines) {
+		from := cube{rng.Intn(bricksAreaSide), rng.Intn(bricksAreaSide), randRange(rng, 1, maxZ)}
+		to := from
+		length := randRange(rng, 1, maxBrickLength)
+		switch rng.Intn(3) {
+		case 0:
+			to.x = min(from.x+length-1, bricksAreaSide-1)
+		case 1:
+			to.y = min(from.y+length-1, bricksAreaSide-1)
+		default:
+			to.z = from.z + length - 1
+		}
+
+		var cubes []cube
+		for x := from.x; x <= to.x; x++ {
+			for y := from.y; y <= to.y; y++ {
+				for z := from.z; z <= to.z; z++ {
+					cubes = append(cubes, cube{x, y, z})
+				}
+			}
+		}
+		isFree := true
+		for _, c := range cubes {
+			isFree = isFree && !occupied.Contains(c)
+		}
+		if !isFree {
+			continue
+		}
+		for _, c := range cubes {
+			occupied.Add(c)
+		}
+
+		lines = append(lines, fmt.Sprintf("%d,%d,%d~%d,%d,%d", from.x, from.y, from.z, to.x, to.y,
+			to.z))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,154 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	trailPath       = '.'
+	trailForest     = '#'
+	trailSlopeRight = '>'
+	trailSlopeDown  = 'v'
+)
+
+type trailEdge struct {
+	from, to point
+}
+
+// generateDay23 connects a lattice of junctions by corridors leading right or down, the way the
+// original input does. Slopes around junctions point away from the start, so there are no cycles
+// downhill. Some corridors are dropped while every junction stays passable.
+func generateDay23(rng *rand.Rand, size uint) string {
+	junctionsSide := int(size)
+	rowIdxs := randomJunctionIdxs(rng, junctionsSide)
+	colIdxs := randomJunctionIdxs(rng, junctionsSide)
+	rowsCount := rowIdxs[junctionsSide-1] + randRange(rng, 4, 6)
+	colsCount := colIdxs[junctionsSide-1] + randRange(rng, 4, 6)
+
+	trails := make([][]byte, rowsCount)
+	for rowIdx := range trails {
+		trails[rowIdx] = make([]byte, colsCount)
+		for colIdx := range trails[rowIdx] {
+			trails[rowIdx][colIdx] = trailForest
+		}
+	}
+
+	var edges []trailEdge
+	for i := 0; i < junctionsSide; i++ {
+		for j := 0; j < junctionsSide; j++ {
+			if j < junctionsSide-1 {
+				edges = append(edges, trailEdge{point{i, j}, point{i, j + 1}})
+			}
+			if i < junctionsSide-1 {
+				edges = append(edges, trailEdge{point{i, j}, point{i + 1, j}})
+			}
+		}
+	}
+	shuffle(rng, edges)
+	for i := 0; i < len(edges); i++ {
+		if rng.Intn(4) != 0 {
+			continue
+		}
+
+		edgesLeft := append(append([]trailEdge{}, edges[:i]...), edges[i+1:]...)
+		if areJunctionsPassable(edgesLeft, junctionsSide) {
+			edges = edgesLeft
+			i--
+		}
+	}
+
+	first := point{rowIdxs[0], colIdxs[0]}
+	last := point{rowIdxs[junctionsSide-1], colIdxs[junctionsSide-1]}
+	start := point{0, 1}
+	end := point{rowsCount - 1, colsCount - 2}
+
+	carveTrail(trails, start, point{first.row, start.col})
+	carveTrail(trails, point{first.row, start.col}, first)
+	trails[first.row][first.col-1] = trailSlopeRight
+	carveTrail(trails, last, point{last.row, end.col})
+	carveTrail(trails, point{last.row, end.col}, end)
+	trails[last.row][last.col+1] = trailSlopeRight
+
+	for _, edge := range edges {
+		from := point{rowIdxs[edge.from.row], colIdxs[edge.from.col]}
+		to := point{rowIdxs[edge.to.row], colIdxs[edge.to.col]}
+		carveBumpyTrail(rng, trails, from, to)
+
+		slope := byte(trailSlopeRight)
+		diff := point{0, 1}
+		if from.col == to.col {
+			slope, diff = trailSlopeDown, point{1, 0}
+		}
+		trails[from.row+diff.row][from.col+diff.col] = slope
+		trails[to.row-diff.row][to.col-diff.col] = slope
+	}
+
+	return formatGrid(trails)
+}
+
+// randomJunctionIdxs returns increasing row or column indexes of junctions with gaps from 8 to 12
+// between them
+func randomJunctionIdxs(rng *rand.Rand, count int) []int {
+	idxs := []int{randRange(rng, 3, 5)}
+	for len(idxs) < count {
+		idxs = append(idxs, idxs[len(idxs)-1]+randRange(rng, 8, 12))
+	}
+	return idxs
+}
+
+// areJunctionsPassable checks that every junction but the first one has a corridor leading in and
+// every junction but the last one has a corridor leading out. As corridors lead right or down only,
+// the end is reachable from every junction then.
+func areJunctionsPassable(edges []trailEdge, junctionsSide int) bool {
+	hasIn := util.NewSet(point{0, 0})
+	hasOut := util.NewSet(point{junctionsSide - 1, junctionsSide - 1})
+	for _, edge := range edges {
+		hasOut.Add(edge.from)
+		hasIn.Add(edge.to)
+	}
+	return len(hasIn) == junctionsSide*junctionsSide && len(hasOut) == junctionsSide*junctionsSide
+}
+
+// carveTrail carves a straight trail between 2 points
+func carveTrail(trails [][]byte, from, to point) {
+	rowDiff, colDiff := sign(to.row-from.row), sign(to.col-from.col)
+	for p := from; ; p = (point{p.row + rowDiff, p.col + colDiff}) {
+		trails[p.row][p.col] = trailPath
+		if p == to {
+			return
+		}
+	}
+}
+
+// carveBumpyTrail carves a trail between 2 junctions, which sometimes takes a detour of 1 or 2
+// tiles aside in the middle
+func carveBumpyTrail(rng *rand.Rand, trails [][]byte, from, to point) {
+	if rng.Intn(2) == 0 {
+		carveTrail(trails, from, to)
+		return
+	}
+
+	// points of the trail are addressed by the offset along the trail and the offset aside it
+	along, aside := point{0, 1}, point{1, 0}
+	length := to.col - from.col
+	if from.col == to.col {
+		along, aside = aside, along
+		length = to.row - from.row
+	}
+	at := func(alongOffset, asideOffset int) point {
+		return point{from.row + along.row*alongOffset + aside.row*asideOffset,
+			from.col + along.col*alongOffset + aside.col*asideOffset}
+	}
+
+	bumpFrom := randRange(rng, 3, length-5)
+	bumpTo := randRange(rng, bumpFrom+2, length-3)
+	depth := randRange(rng, 1, 2) * randomItem(rng, []int{-1, 1})
+
+	carveTrail(trails, from, at(bumpFrom, 0))
+	carveTrail(trails, at(bumpFrom, 0), at(bumpFrom, depth))
+	carveTrail(trails, at(bumpFrom, depth), at(bumpTo, depth))
+	carveTrail(trails, at(bumpTo, depth), at(bumpTo, 0))
+	carveTrail(trails, at(bumpTo, 0), to)
+}
@@ -0,0 +1,129 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	minRockStart          = 250_000_000_000_000
+	maxRockStart          = 300_000_000_000_000
+	maxHailstoneSpeed     = 300
+	maxCollisionTime      = 400_000_000_000
+	maxFirstCollisionTime = 999
+	minPairTimeDiff       = 1_000_000
+	maxPairTimeDiff       = 10_000_000
+)
+
+type hailstone struct {
+	position, velocity [3]int
+	collisionTime      int
+}
+
+// generateDay24 throws a rock first and then places hailstones on its way. Like in the original
+// input, some pairs of hailstones share a velocity component, while their distance along that
+// axis has few prime factors. The first collision happens soon after the throw.
+func generateDay24(rng *rand.Rand, size uint) string {
+	var rockPosition, rockVelocity [3]int
+	for axis := range rockPosition {
+		rockPosition[axis] = randRange(rng, minRockStart, maxRockStart)
+		rockVelocity[axis] = randRange(rng, -maxHailstoneSpeed, maxHailstoneSpeed)
+	}
+
+	// stones 2i and 2i+1 form a pair for i < pairsCount
+	pairsCount := min(int(size)/2, max(6, int(size)/10))
+	stones := make([]hailstone, size)
+	collisionTimes := util.NewSet[int]()
+	for i := range stones {
+		for {
+			var time int
+			switch {
+			case i == 0:
+				time = randRange(rng, 1, maxFirstCollisionTime)
+			case i%2 == 1 && i/2 < pairsCount:
+				time = stones[i-1].collisionTime + randomPrime(rng, minPairTimeDiff, maxPairTimeDiff)
+			default:
+				time = randRange(rng, maxFirstCollisionTime+1, maxCollisionTime)
+			}
+
+			if !collisionTimes.Contains(time) {
+				collisionTimes.Add(time)
+				stones[i].collisionTime = time
+				break
+			}
+		}
+	}
+
+	for axis := range rockVelocity {
+		assignHailstoneVelocities(rng, stones, axis, rockVelocity[axis], pairsCount)
+	}
+
+	lines := make([]string, 0, size)
+	for _, stone := range stones {
+		for axis := range stone.position {
+			stone.position[axis] = rockPosition[axis] +
+				(rockVelocity[axis]-stone.velocity[axis])*stone.collisionTime
+		}
+		lines = append(lines, fmt.Sprintf("%d, %d, %d @ %d, %d, %d", stone.position[0],
+			stone.position[1], stone.position[2], stone.velocity[0], stone.velocity[1],
+			stone.velocity[2]))
+	}
+	shuffle(rng, lines)
+
+	return strings.Join(lines, "\n")
+}
+
+// assignHailstoneVelocities sets velocity components along the axis. Every pair shares it with
+// probability 1/2; pair i always shares it along axis i%3. Other stones either have a unique
+// velocity component or share it with at least 2 more stones, so no other pairs appear.
+// Velocities close to the rock one are skipped.
+func assignHailstoneVelocities(
+	rng *rand.Rand,
+	stones []hailstone,
+	axis, rockVelocity, pairsCount int,
+) {
+	var velocities []int
+	for v := -maxHailstoneSpeed; v <= maxHailstoneSpeed; v++ {
+		if abs(v-rockVelocity) > 1 {
+			velocities = append(velocities, v)
+		}
+	}
+	shuffle(rng, velocities)
+
+	var otherStoneIdxs []int
+	for i := 0; i < len(stones); i++ {
+		pairIdx := i / 2
+		if pairIdx >= pairsCount {
+			otherStoneIdxs = append(otherStoneIdxs, i)
+		} else if pairIdx%3 == axis || rng.Intn(2) == 0 {
+			stones[i].velocity[axis] = velocities[0]
+			stones[i+1].velocity[axis] = velocities[0]
+			velocities = velocities[1:]
+			i++
+		} else {
+			otherStoneIdxs = append(otherStoneIdxs, i, i+1)
+			i++
+		}
+	}
+
+	// groups of 3 or more stones are required when there are not enough unique velocities
+	groupsCount := len(otherStoneIdxs)
+	if groupsCount > len(velocities) {
+		groupsCount = len(otherStoneIdxs) / 3
+	}
+	for i, stoneIdx := range otherStoneIdxs {
+		stones[stoneIdx].velocity[axis] = velocities[i%groupsCount]
+	}
+}
+
+// randomPrime returns a random prime in [from, to]
+func randomPrime(rng *rand.Rand, from, to int) int {
+	for {
+		if n := randRange(rng, from, to); isPrime(uint(n)) {
+			return n
+		}
+	}
+}
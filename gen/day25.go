@@ -0,0 +1,79 @@
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+const (
+	componentNameLetters = "abcdefghijklmnopqrstuvwxyz"
+	minClusterSize       = 7
+	minComponentDegree   = 6
+	wiresToCutCount      = 3
+)
+
+// generateDay25 builds 2 well-connected clusters of components and links them by exactly 3 wires
+// with distinct ends, so cutting these wires is the only way to split the components in 2 groups
+func generateDay25(rng *rand.Rand, size uint) string {
+	names := uniqueNames(rng, size, componentNameLetters, 3, anyName)
+	firstClusterSize := randRange(rng, minClusterSize, int(size)-minClusterSize)
+	clusters := [][]string{names[:firstClusterSize], names[firstClusterSize:]}
+
+	connections := map[string]util.Set[string]{}
+	connect := func(name1, name2 string) {
+		if connections[name1] == nil {
+			connections[name1] = util.NewSet[string]()
+		}
+		if connections[name2] == nil {
+			connections[name2] = util.NewSet[string]()
+		}
+		connections[name1].Add(name2)
+		connections[name2].Add(name1)
+	}
+
+	for _, cluster := range clusters {
+		// the ring keeps the cluster connected
+		for i, name := range cluster {
+			connect(name, cluster[(i+1)%len(cluster)])
+		}
+		for _, name := range cluster {
+			for len(connections[name]) < minComponentDegree {
+				if other := randomItem(rng, cluster); other != name {
+					connect(name, other)
+				}
+			}
+		}
+	}
+
+	for i, from := range clusters[0][:wiresToCutCount] {
+		connect(from, clusters[1][i])
+	}
+
+	// every wire is listed once on the line of one of its components
+	wiresByName := map[string][]string{}
+	for _, name := range names {
+		for _, connectedName := range util.SortedSetItems(connections[name]) {
+			if name < connectedName {
+				if rng.Intn(2) == 0 {
+					wiresByName[name] = append(wiresByName[name], connectedName)
+				} else {
+					wiresByName[connectedName] = append(wiresByName[connectedName], name)
+				}
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(wiresByName))
+	for _, name := range names {
+		if wires := wiresByName[name]; len(wires) > 0 {
+			shuffle(rng, wires)
+			lines = append(lines, fmt.Sprintf("%s: %s", name, strings.Join(wires, " ")))
+		}
+	}
+	shuffle(rng, lines)
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+// Package gen generates random but valid puzzle inputs, so the solvers can be stress tested on
+// inputs of any size and on edge cases the samples don't cover.
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+type Generator struct {
+	// SizeDescription explains what the size of a generated input means for the day
+	SizeDescription string
+	// DefaultSize is used when no size is requested explicitly
+	DefaultSize uint
+	// MinSize and MaxSize limit sizes the solvers are able to handle. MaxSize 0 means no limit.
+	MinSize, MaxSize uint
+
+	generate func(rng *rand.Rand, size uint) string
+}
+
+var generatorByDay = map[uint]Generator{
+	1:  {"number of lines", 1000, 1, 0, generateDay01},
+	2:  {"number of games", 100, 1, 0, generateDay02},
+	3:  {"side of the schematic", 140, 1, 0, generateDay03},
+	4:  {"number of cards", 200, 1, 255, generateDay04},
+	5:  {"number of rules in every map", 30, 1, 0, generateDay05},
+	6:  {"number of races", 4, 1, 4, generateDay06},
+	7:  {"number of hands", 1000, 1, 100_000, generateDay07},
+	8:  {"number of ghosts", 6, 2, 6, generateDay08},
+	9:  {"number of histories", 200, 1, 0, generateDay09},
+	10: {"side of the pipe field", 140, 3, 0, generateDay10},
+	11: {"side of the image", 140, 2, 0, generateDay11},
+	12: {"number of records", 1000, 1, 0, generateDay12},
+	13: {"number of patterns", 100, 1, 0, generateDay13},
+	14: {"side of the platform", 100, 1, 0, generateDay14},
+	15: {"number of steps", 4000, 1, 0, generateDay15},
+	16: {"side of the contraption", 110, 1, 0, generateDay16},
+	17: {"side of the heat loss map", 141, 5, 255, generateDay17},
+	18: {"side of the area the lagoon is dug in", 30, 1, 100, generateDay18},
+	19: {"max number of workflows", 500, 1, 10_000, generateDay19},
+	20: {"number of counters feeding the rx module", 4, 1, 8, generateDay20},
+	21: {"side of the garden; even sides are increased by 1", 131, 5, 255, generateDay21},
+	22: {"number of bricks", 1200, 1, 5000, generateDay22},
+	23: {"side of the trail junctions lattice", 5, 1, 6, generateDay23},
+	24: {"number of hailstones", 300, 6, 1000, generateDay24},
+	25: {"number of components", 300, 14, 17_000, generateDay25},
+}
+
+func Days() []uint {
+	return util.MapKeysToSortedSlice(generatorByDay)
+}
+
+func Get(day uint) (Generator, error) {
+	g, found := generatorByDay[day]
+	if !found {
+		return Generator{}, fmt.Errorf("No input generator for day %d", day)
+	}
+	return g, nil
+}
+
+// Generate returns an input of the given size. Same size and seed always produce the same input.
+func (g Generator) Generate(size uint, seed int64) (string, error) {
+	if size < g.MinSize || (g.MaxSize != 0 && size > g.MaxSize) {
+		return "", fmt.Errorf("Size %d is out of range [%d, %s]", size, g.MinSize, g.maxSizeStr())
+	}
+
+	return g.generate(rand.New(rand.NewSource(seed)), size), nil
+}
+
+func (g Generator) maxSizeStr() string {
+	if g.MaxSize == 0 {
+		return "∞"
+	}
+	return fmt.Sprint(g.MaxSize)
+}
+
+// randRange returns a random number in [from, to]
+func randRange(rng *rand.Rand, from, to int) int {
+	return from + rng.Intn(to-from+1)
+}
+
+func randomItem[T any](rng *rand.Rand, items []T) T {
+	return items[rng.Intn(len(items))]
+}
+
+func shuffle[T any](rng *rand.Rand, items []T) {
+	rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+}
+
+// uniqueNames returns count distinct random names of the given length, which are built of the
+// alphabet chars and accepted by isAllowed
+func uniqueNames(
+	rng *rand.Rand,
+	count uint,
+	alphabet string,
+	length uint,
+	isAllowed func(string) bool,
+) []string {
+	names := make([]string, 0, count)
+	taken := util.NewSet[string]()
+
+	for uint(len(names)) < count {
+		var b strings.Builder
+		for i := uint(0); i < length; i++ {
+			b.WriteByte(alphabet[rng.Intn(len(alphabet))])
+		}
+
+		name := b.String()
+		if !taken.Contains(name) && isAllowed(name) {
+			taken.Add(name)
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func anyName(string) bool {
+	return true
+}
+
+// primesBetween returns all primes in [from, to]
+func primesBetween(from, to uint) []uint {
+	var primes []uint
+	for n := max(from, 2); n <= to; n++ {
+		if isPrime(n) {
+			primes = append(primes, n)
+		}
+	}
+	return primes
+}
+
+func isPrime(n uint) bool {
+	if n < 2 {
+		return false
+	}
+	for d := uint(2); d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// randomGrid returns side x side grid filled with chars picked by pickChar
+func randomGrid(side uint, pickChar func() byte) [][]byte {
+	grid := make([][]byte, side)
+	for rowIdx := range grid {
+		grid[rowIdx] = make([]byte, side)
+		for colIdx := range grid[rowIdx] {
+			grid[rowIdx][colIdx] = pickChar()
+		}
+	}
+	return grid
+}
+
+func formatGrid(grid [][]byte) string {
+	rows := make([]string, 0, len(grid))
+	for _, row := range grid {
+		rows = append(rows, string(row))
+	}
+	return strings.Join(rows, "\n")
+}
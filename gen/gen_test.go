@@ -0,0 +1,146 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	for _, day := range Days() {
+		g, _ := Get(day)
+
+		input1, err := g.Generate(g.DefaultSize, 42)
+		if err != nil {
+			t.Fatalf("Day %d: %s", day, err.Error())
+		}
+		input2, _ := g.Generate(g.DefaultSize, 42)
+		if input1 != input2 {
+			t.Errorf("Day %d: Inputs generated with the same seed differ", day)
+		}
+
+		if len(input1) == 0 {
+			t.Errorf("Day %d: Input is empty", day)
+		}
+		if strings.HasSuffix(input1, "\n") {
+			t.Errorf("Day %d: Input ends with a new line", day)
+		}
+	}
+}
+
+func TestGenerateMinSize(t *testing.T) {
+	for _, day := range Days() {
+		g, _ := Get(day)
+		for seed := int64(1); seed <= 10; seed++ {
+			if _, err := g.Generate(g.MinSize, seed); err != nil {
+				t.Fatalf("Day %d: %s", day, err.Error())
+			}
+		}
+	}
+}
+
+func TestGenerateRejectsSizeOutOfRange(t *testing.T) {
+	for _, day := range Days() {
+		g, _ := Get(day)
+
+		if g.MinSize > 0 {
+			if _, err := g.Generate(g.MinSize-1, 1); err == nil {
+				t.Errorf("Day %d: No error for size below the min one", day)
+			}
+		}
+		if g.MaxSize > 0 {
+			if _, err := g.Generate(g.MaxSize+1, 1); err == nil {
+				t.Errorf("Day %d: No error for size above the max one", day)
+			}
+		}
+	}
+}
+
+func TestGetUnknownDay(t *testing.T) {
+	if _, err := Get(26); err == nil {
+		t.Error("No error for unknown day")
+	}
+}
+
+// slowSolvers take too long on any input to be run by tests. Their inputs are still checked by the
+// other part of the day.
+var slowSolvers = map[string]string{
+	"14/part2": "spins the platform a billion times whatever its size",
+}
+
+// TestSolversAcceptGeneratedInputs runs the solvers of every day on generated inputs, so an input
+// their parsers reject fails the test. Part 2 isn't run on inputs of the default size, as some
+// solvers take minutes on them.
+func TestSolversAcceptGeneratedInputs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Solvers are built and run only in the full mode")
+	}
+
+	tests := []struct {
+		name  string
+		size  func(g Generator) uint
+		seeds []int64
+		parts []uint
+	}{
+		{"min size", func(g Generator) uint { return g.MinSize }, []int64{1, 2, 3}, []uint{1, 2}},
+		{"default size", func(g Generator) uint { return g.DefaultSize }, []int64{1}, []uint{1}},
+	}
+
+	binDir := t.TempDir()
+	for _, day := range Days() {
+		day := day
+		g, _ := Get(day)
+
+		t.Run(fmt.Sprintf("day %d", day), func(t *testing.T) {
+			t.Parallel()
+
+			binPathByPart := map[uint]string{}
+			for part := uint(1); part <= 2; part++ {
+				dir := fmt.Sprintf("%02d/part%d", day, part)
+				if _, err := os.Stat(filepath.Join("..", dir, "main.go")); err != nil {
+					continue
+				}
+				if reason, found := slowSolvers[dir]; found {
+					t.Logf("%s is skipped as it %s", dir, reason)
+					continue
+				}
+
+				binPathByPart[part] = buildSolver(t, binDir, dir)
+			}
+
+			for _, test := range tests {
+				for _, seed := range test.seeds {
+					input, err := g.Generate(test.size(g), seed)
+					if err != nil {
+						t.Fatalf("%s, seed %d: %s", test.name, seed, err.Error())
+					}
+					inputPath := filepath.Join(t.TempDir(), "input.txt")
+					if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+						t.Fatal(err)
+					}
+
+					for _, part := range test.parts {
+						binPath, found := binPathByPart[part]
+						if !found {
+							continue
+						}
+						output, err := exec.Command(binPath, inputPath).CombinedOutput()
+						if err != nil {
+							t.Errorf("Part %d, %s, seed %d: %s\n%s", part, test.name, seed,
+								err.Error(), lastLines(string(output), 5))
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// lastLines returns up to n last lines of the output, where solvers report what went wrong
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	return strings.Join(lines[max(0, len(lines)-n):], "\n")
+}
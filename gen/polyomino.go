@@ -0,0 +1,121 @@
+package gen
+
+import "math/rand"
+
+type point struct {
+	row, col int
+}
+
+// neighbourDiffs lists the 8 neighbours of a cell clockwise, starting from the north-west one
+var neighbourDiffs = []point{{-1, -1}, {-1, 0}, {-1, 1}, {0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}}
+
+// randomPolyomino grows a random polyomino of up to cellsCount cells within rows x cols grid. The
+// polyomino has no holes and no cells touching by corners only, so its boundary is a simple loop.
+func randomPolyomino(rng *rand.Rand, rows, cols, cellsCount int) [][]bool {
+	shape := make([][]bool, rows)
+	for rowIdx := range shape {
+		shape[rowIdx] = make([]bool, cols)
+	}
+	isFilled := func(p point) bool {
+		return p.row >= 0 && p.row < rows && p.col >= 0 && p.col < cols && shape[p.row][p.col]
+	}
+
+	candidates := []point{{rng.Intn(rows), rng.Intn(cols)}}
+	for filled := 0; filled < cellsCount && len(candidates) > 0; {
+		candidateIdx := rng.Intn(len(candidates))
+		cell := candidates[candidateIdx]
+		candidates[candidateIdx] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+
+		if isFilled(cell) || (filled > 0 && !canExtendPolyomino(cell, isFilled)) {
+			continue
+		}
+		shape[cell.row][cell.col] = true
+		filled++
+
+		// diagonal neighbours are skipped; they can only be added after an adjacent cell
+		for i := 1; i < len(neighbourDiffs); i += 2 {
+			next := point{cell.row + neighbourDiffs[i].row, cell.col + neighbourDiffs[i].col}
+			if next.row >= 0 && next.row < rows && next.col >= 0 && next.col < cols && !isFilled(next) {
+				candidates = append(candidates, next)
+			}
+		}
+	}
+
+	return shape
+}
+
+// canExtendPolyomino checks that filled neighbours of the cell form a single run around it, which
+// contains an adjacent cell. Otherwise the cell would close a hole or touch a cell by a corner only.
+func canExtendPolyomino(cell point, isFilled func(point) bool) bool {
+	var runsCount, filledCount int
+	for i, diff := range neighbourDiffs {
+		prevDiff := neighbourDiffs[(i+len(neighbourDiffs)-1)%len(neighbourDiffs)]
+		isCurrentFilled := isFilled(point{cell.row + diff.row, cell.col + diff.col})
+		isPrevFilled := isFilled(point{cell.row + prevDiff.row, cell.col + prevDiff.col})
+
+		if isCurrentFilled {
+			filledCount++
+			if !isPrevFilled {
+				runsCount++
+			}
+		}
+	}
+	if runsCount != 1 || filledCount == len(neighbourDiffs) {
+		return false
+	}
+
+	// a filled corner neighbour needs a filled side neighbour next to it
+	for i := 0; i < len(neighbourDiffs); i += 2 {
+		corner := neighbourDiffs[i]
+		prevSide := neighbourDiffs[(i+len(neighbourDiffs)-1)%len(neighbourDiffs)]
+		nextSide := neighbourDiffs[i+1]
+		if isFilled(point{cell.row + corner.row, cell.col + corner.col}) &&
+			!isFilled(point{cell.row + prevSide.row, cell.col + prevSide.col}) &&
+			!isFilled(point{cell.row + nextSide.row, cell.col + nextSide.col}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// polyominoBoundary returns the corners of the polyomino cells lying on its boundary in clockwise
+// order. Corner {r, c} is the top left corner of cell {r, c}.
+func polyominoBoundary(shape [][]bool) []point {
+	isFilled := func(row, col int) bool {
+		return row >= 0 && row < len(shape) && col >= 0 && col < len(shape[row]) && shape[row][col]
+	}
+
+	nextCorner := map[point]point{}
+	var start point
+	for rowIdx, row := range shape {
+		for colIdx, filled := range row {
+			if !filled {
+				continue
+			}
+
+			topLeft, topRight := point{rowIdx, colIdx}, point{rowIdx, colIdx + 1}
+			bottomLeft, bottomRight := point{rowIdx + 1, colIdx}, point{rowIdx + 1, colIdx + 1}
+			if !isFilled(rowIdx-1, colIdx) {
+				nextCorner[topLeft] = topRight
+				start = topLeft
+			}
+			if !isFilled(rowIdx, colIdx+1) {
+				nextCorner[topRight] = bottomRight
+			}
+			if !isFilled(rowIdx+1, colIdx) {
+				nextCorner[bottomRight] = bottomLeft
+			}
+			if !isFilled(rowIdx, colIdx-1) {
+				nextCorner[bottomLeft] = topLeft
+			}
+		}
+	}
+
+	boundary := []point{start}
+	for corner := nextCorner[start]; corner != start; corner = nextCorner[corner] {
+		boundary = append(boundary, corner)
+	}
+	return boundary
+}
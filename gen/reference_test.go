@@ -0,0 +1,422 @@
+package gen
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/efulmo/advent-of-code-2023/util"
+)
+
+// referenceSolvers answer a part the slowest obvious way, so they are likely right where the
+// solvers are clever. They take tiny inputs only; filter drops records too big for them.
+var referenceSolvers = []struct {
+	day, part uint
+	sizes     []uint
+	filter    func(line string) bool
+	solve     func(lines []string) string
+}{
+	{6, 1, []uint{1, 2, 3, 4}, nil, solveDay06Part1},
+	{6, 2, []uint{1, 2}, nil, solveDay06Part2},
+	{9, 1, []uint{1, 10}, nil, solveDay09Part1},
+	{9, 2, []uint{1, 10}, nil, solveDay09Part2},
+	{11, 1, []uint{2, 5, 20}, nil, solveDay11Part1},
+	{11, 2, []uint{2, 5, 20}, nil, solveDay11Part2},
+	{12, 1, []uint{1, 20}, nil, solveDay12Part1},
+	{12, 2, []uint{20, 100}, hasFewUnknownSprings, solveDay12Part2},
+}
+
+func TestSolversMatchReferenceSolvers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Solvers are built and run only in the full mode")
+	}
+
+	binDir := t.TempDir()
+	for _, ref := range referenceSolvers {
+		ref := ref
+		g, _ := Get(ref.day)
+
+		t.Run(fmt.Sprintf("day %d part %d", ref.day, ref.part), func(t *testing.T) {
+			t.Parallel()
+
+			binPath := buildSolver(t, binDir, fmt.Sprintf("%02d/part%d", ref.day, ref.part))
+			for _, size := range ref.sizes {
+				for seed := int64(1); seed <= 5; seed++ {
+					input, err := g.Generate(size, seed)
+					if err != nil {
+						t.Fatal(err)
+					}
+					lines := strings.Split(input, "\n")
+					if ref.filter != nil {
+						var kept []string
+						for _, line := range lines {
+							if ref.filter(line) {
+								kept = append(kept, line)
+							}
+						}
+						if len(kept) == 0 {
+							continue
+						}
+						lines = kept
+					}
+
+					expected := ref.solve(lines)
+					answer := runSolver(t, binPath, strings.Join(lines, "\n"))
+					if answer != expected {
+						t.Errorf("Size %d, seed %d: Expected answer %s, got %s", size, seed,
+							expected, answer)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestDay12RecordsHaveArrangements checks the generator promise the solvers rely on: a record
+// without arrangements would make part 1 and part 2 answers meaningless
+func TestDay12RecordsHaveArrangements(t *testing.T) {
+	g, _ := Get(12)
+	for seed := int64(1); seed <= 10; seed++ {
+		input, _ := g.Generate(g.DefaultSize, seed)
+		for lineIdx, line := range strings.Split(input, "\n") {
+			row, groups := parseDay12Record(line)
+			if !hasArrangement(row, groups) {
+				t.Errorf("Seed %d, line %d: Record %s has no arrangements", seed, lineIdx+1,
+					line)
+			}
+		}
+	}
+}
+
+// TestDay19WorkflowsAreAcyclic checks the generator promise that every part is accepted or
+// rejected in the end
+func TestDay19WorkflowsAreAcyclic(t *testing.T) {
+	g, _ := Get(19)
+	for seed := int64(1); seed <= 10; seed++ {
+		input, _ := g.Generate(g.DefaultSize, seed)
+		workflowsInput, _, _ := strings.Cut(input, "\n\n")
+
+		targetsByName := map[string][]string{}
+		for _, line := range strings.Split(workflowsInput, "\n") {
+			name, rules, _ := strings.Cut(strings.TrimSuffix(line, "}"), "{")
+			for _, rule := range strings.Split(rules, ",") {
+				_, target, found := strings.Cut(rule, ":")
+				if !found {
+					target = rule
+				}
+				targetsByName[name] = append(targetsByName[name], target)
+			}
+		}
+
+		// workflows on the way to the current one are in progress; reaching one again is a cycle
+		inProgress, done := util.NewSet[string](), util.NewSet[string]()
+		var visit func(name string) error
+		visit = func(name string) error {
+			if name == "A" || name == "R" || done.Contains(name) {
+				return nil
+			}
+			if inProgress.Contains(name) {
+				return fmt.Errorf("Workflow %s is in a cycle", name)
+			}
+			targets, found := targetsByName[name]
+			if !found {
+				return fmt.Errorf("Workflow %s isn't defined", name)
+			}
+
+			inProgress.Add(name)
+			for _, target := range targets {
+				if err := visit(target); err != nil {
+					return err
+				}
+			}
+			inProgress.Remove(name)
+			done.Add(name)
+			return nil
+		}
+		if err := visit("in"); err != nil {
+			t.Errorf("Seed %d: %s", seed, err.Error())
+		}
+	}
+}
+
+// buildSolver builds the solver of the directory relative to the module root into binDir
+func buildSolver(t *testing.T, binDir, dir string) string {
+	t.Helper()
+
+	binPath := filepath.Join(binDir, strings.ReplaceAll(dir, "/", "-"))
+	cmd := exec.Command("go", "build", "-o", binPath, "./"+dir)
+	cmd.Dir = ".."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build %s: %s\n%s", dir, err.Error(), output)
+	}
+	return binPath
+}
+
+var lastIntegerRegexp = regexp.MustCompile(`(-?\d+)\D*$`)
+
+// runSolver returns the answer of the solver on the input: the last integer of the last non-empty
+// line of its stdout
+func runSolver(t *testing.T, binPath, input string) string {
+	t.Helper()
+
+	inputPath := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command(binPath, inputPath).Output()
+	if err != nil {
+		t.Fatalf("%s: %s\n%s", filepath.Base(binPath), err.Error(),
+			lastLines(string(output), 5))
+	}
+
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	match := lastIntegerRegexp.FindStringSubmatch(lines[len(lines)-1])
+	if match == nil {
+		t.Fatalf("%s: No answer in the output\n%s", filepath.Base(binPath),
+			lastLines(string(output), 5))
+	}
+	return match[1]
+}
+
+// solveDay06Part1 tries every hold time of every race
+func solveDay06Part1(lines []string) string {
+	times := util.StringsToUints(strings.Fields(lines[0])[1:])
+	distances := util.StringsToUints(strings.Fields(lines[1])[1:])
+
+	product := uint(1)
+	for raceIdx, time := range times {
+		product *= countWinningHoldTimes(time, distances[raceIdx])
+	}
+	return fmt.Sprint(product)
+}
+
+// solveDay06Part2 tries every hold time of the race with spaces between digits dropped
+func solveDay06Part2(lines []string) string {
+	time := util.ParseUintOrPanic(strings.Join(strings.Fields(lines[0])[1:], ""))
+	distance := util.ParseUintOrPanic(strings.Join(strings.Fields(lines[1])[1:], ""))
+	return fmt.Sprint(countWinningHoldTimes(time, distance))
+}
+
+func countWinningHoldTimes(time, record uint) uint {
+	var count uint
+	for hold := uint(0); hold <= time; hold++ {
+		if hold*(time-hold) > record {
+			count++
+		}
+	}
+	return count
+}
+
+// solveDay09Part1 sums values of the histories at the step after the last one. A history of n
+// values is the polynomial of degree below n through them, so Lagrange interpolation gives it.
+func solveDay09Part1(lines []string) string {
+	return sumInterpolated(lines, func(valuesCount int) int { return valuesCount })
+}
+
+// solveDay09Part2 sums values of the histories at the step before the first one
+func solveDay09Part2(lines []string) string {
+	return sumInterpolated(lines, func(int) int { return -1 })
+}
+
+func sumInterpolated(lines []string, step func(valuesCount int) int) string {
+	sum := new(big.Rat)
+	for _, line := range lines {
+		values := util.StringsToInts(strings.Fields(line))
+		x := step(len(values))
+		for i, value := range values {
+			term := big.NewRat(int64(value), 1)
+			for j := range values {
+				if j != i {
+					term.Mul(term, big.NewRat(int64(x-j), int64(i-j)))
+				}
+			}
+			sum.Add(sum, term)
+		}
+	}
+	return sum.RatString()
+}
+
+// solveDay11Part1 inserts a copy of every empty row and column into the image and sums distances
+// between galaxies of the expanded image
+func solveDay11Part1(lines []string) string {
+	var rows []string
+	for _, line := range lines {
+		rows = append(rows, line)
+		if !strings.ContainsRune(line, galaxyChar) {
+			rows = append(rows, line)
+		}
+	}
+
+	expanded := make([][]byte, len(rows))
+	for colIdx := range lines[0] {
+		isEmpty := true
+		for _, row := range rows {
+			isEmpty = isEmpty && row[colIdx] != galaxyChar
+		}
+		for rowIdx, row := range rows {
+			expanded[rowIdx] = append(expanded[rowIdx], row[colIdx])
+			if isEmpty {
+				expanded[rowIdx] = append(expanded[rowIdx], row[colIdx])
+			}
+		}
+	}
+
+	var galaxies [][2]int
+	for rowIdx, row := range expanded {
+		for colIdx, char := range row {
+			if char == galaxyChar {
+				galaxies = append(galaxies, [2]int{rowIdx, colIdx})
+			}
+		}
+	}
+
+	var sum int
+	for i, g1 := range galaxies {
+		for _, g2 := range galaxies[i+1:] {
+			sum += abs(g1[0]-g2[0]) + abs(g1[1]-g2[1])
+		}
+	}
+	return fmt.Sprint(sum)
+}
+
+// solveDay11Part2 counts empty rows and columns between every pair of galaxies, as every empty
+// one is a million wide
+func solveDay11Part2(lines []string) string {
+	const expansionRate = 1_000_000
+
+	isRowEmpty := func(rowIdx int) bool {
+		return !strings.ContainsRune(lines[rowIdx], galaxyChar)
+	}
+	isColEmpty := func(colIdx int) bool {
+		for _, line := range lines {
+			if line[colIdx] == galaxyChar {
+				return false
+			}
+		}
+		return true
+	}
+	distance := func(from, to int, isEmpty func(int) bool) int {
+		from, to = min(from, to), max(from, to)
+		distance := to - from
+		for idx := from + 1; idx < to; idx++ {
+			if isEmpty(idx) {
+				distance += expansionRate - 1
+			}
+		}
+		return distance
+	}
+
+	var galaxies [][2]int
+	for rowIdx, line := range lines {
+		for colIdx, char := range line {
+			if char == galaxyChar {
+				galaxies = append(galaxies, [2]int{rowIdx, colIdx})
+			}
+		}
+	}
+
+	var sum int
+	for i, g1 := range galaxies {
+		for _, g2 := range galaxies[i+1:] {
+			sum += distance(g1[0], g2[0], isRowEmpty) + distance(g1[1], g2[1], isColEmpty)
+		}
+	}
+	return fmt.Sprint(sum)
+}
+
+// solveDay12Part1 tries every way to replace unknown springs
+func solveDay12Part1(lines []string) string {
+	var sum uint
+	for _, line := range lines {
+		row, groups := parseDay12Record(line)
+		sum += countArrangements(row, groups)
+	}
+	return fmt.Sprint(sum)
+}
+
+// solveDay12Part2 tries every way to replace unknown springs of records unfolded 5 times. Records
+// must have few unknown springs, see hasFewUnknownSprings.
+func solveDay12Part2(lines []string) string {
+	var sum uint
+	for _, line := range lines {
+		row, groups := parseDay12Record(line)
+		unfoldedRow := strings.Repeat(row+string(springUnknown), 4) + row
+		var unfoldedGroups []uint
+		for i := 0; i < 5; i++ {
+			unfoldedGroups = append(unfoldedGroups, groups...)
+		}
+		sum += countArrangements(unfoldedRow, unfoldedGroups)
+	}
+	return fmt.Sprint(sum)
+}
+
+// hasFewUnknownSprings accepts records having at most 14 unknown springs when unfolded
+func hasFewUnknownSprings(line string) bool {
+	return strings.Count(line, string(springUnknown)) <= 2
+}
+
+func parseDay12Record(line string) (string, []uint) {
+	row, groups, _ := strings.Cut(line, " ")
+	return row, util.StringsToUints(strings.Split(groups, ","))
+}
+
+func countArrangements(row string, groups []uint) uint {
+	var unknownIdxs []int
+	for i := range row {
+		if row[i] == springUnknown {
+			unknownIdxs = append(unknownIdxs, i)
+		}
+	}
+
+	var count uint
+	arrangement := []byte(row)
+	for mask := 0; mask < 1<<len(unknownIdxs); mask++ {
+		for bit, idx := range unknownIdxs {
+			arrangement[idx] = springOperational
+			if mask&(1<<bit) != 0 {
+				arrangement[idx] = springDamaged
+			}
+		}
+		if matchesGroups(arrangement, groups) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchesGroups(arrangement []byte, groups []uint) bool {
+	var found []uint
+	for _, group := range strings.FieldsFunc(string(arrangement), func(r rune) bool {
+		return r == springOperational
+	}) {
+		found = append(found, uint(len(group)))
+	}
+	return fmt.Sprint(found) == fmt.Sprint(groups)
+}
+
+// hasArrangement looks for a single arrangement of the record placing groups one by one as early
+// as possible and backtracking
+func hasArrangement(row string, groups []uint) bool {
+	if len(groups) == 0 {
+		return !strings.ContainsRune(row, springDamaged)
+	}
+
+	groupLength := int(groups[0])
+	for start := 0; start+groupLength <= len(row); start++ {
+		fits := !strings.ContainsRune(row[start:start+groupLength], springOperational) &&
+			(start+groupLength == len(row) || row[start+groupLength] != springDamaged)
+		if fits && hasArrangement(row[min(start+groupLength+1, len(row)):], groups[1:]) {
+			return true
+		}
+		// a damaged spring can't be skipped
+		if row[start] == springDamaged {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package util
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+const wordBits = 64
+
+// Bitset is a set of non-negative ints backed by a slice of words, so it's much more compact and
+// faster than a map for dense indexes like tile or node numbers. It grows when a bit beyond its
+// size is set. The zero value is an empty bitset ready to use.
+type Bitset struct {
+	words []uint64
+}
+
+// NewBitset returns an empty bitset with room for size bits without growing
+func NewBitset(size int) *Bitset {
+	return &Bitset{words: make([]uint64, 0, (size+wordBits-1)/wordBits)}
+}
+
+func (b *Bitset) Set(i int) {
+	wordIdx := i / wordBits
+	if wordIdx >= len(b.words) {
+		b.words = append(b.words, make([]uint64, wordIdx-len(b.words)+1)...)
+	}
+	b.words[wordIdx] |= 1 << (i % wordBits)
+}
+
+func (b *Bitset) Clear(i int) {
+	if wordIdx := i / wordBits; wordIdx < len(b.words) {
+		b.words[wordIdx] &^= 1 << (i % wordBits)
+	}
+}
+
+func (b *Bitset) Test(i int) bool {
+	wordIdx := i / wordBits
+	return wordIdx < len(b.words) && b.words[wordIdx]&(1<<(i%wordBits)) != 0
+}
+
+// Count returns the number of set bits
+func (b *Bitset) Count() int {
+	count := 0
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Next returns the smallest set bit not less than i. It returns false if there is none, so all
+// bits are iterated with
+//
+//	for i, ok := b.Next(0); ok; i, ok = b.Next(i + 1) {
+func (b *Bitset) Next(i int) (int, bool) {
+	wordIdx := i / wordBits
+	if wordIdx >= len(b.words) {
+		return 0, false
+	}
+
+	// bits lower than i in the first word are skipped
+	word := b.words[wordIdx] >> (i % wordBits)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word), true
+	}
+	for wordIdx++; wordIdx < len(b.words); wordIdx++ {
+		if b.words[wordIdx] != 0 {
+			return wordIdx*wordBits + bits.TrailingZeros64(b.words[wordIdx]), true
+		}
+	}
+	return 0, false
+}
+
+// Ints returns set bits in ascending order
+func (b *Bitset) Ints() []int {
+	ints := make([]int, 0, b.Count())
+	for i, ok := b.Next(0); ok; i, ok = b.Next(i + 1) {
+		ints = append(ints, i)
+	}
+	return ints
+}
+
+func (b *Bitset) Clone() *Bitset {
+	return &Bitset{words: slices.Clone(b.words)}
+}
+
+// Equal tells if both bitsets have the same bits set regardless of their sizes
+func (b *Bitset) Equal(other *Bitset) bool {
+	return slices.Equal(b.trimmedWords(), other.trimmedWords())
+}
+
+// Key returns a string which is equal for equal bitsets, so it can be used as a map key
+func (b *Bitset) Key() string {
+	words := b.trimmedWords()
+
+	var key strings.Builder
+	key.Grow(len(words) * 8)
+	buf := make([]byte, 8)
+	for _, word := range words {
+		binary.LittleEndian.PutUint64(buf, word)
+		key.Write(buf)
+	}
+	return key.String()
+}
+
+// Hash returns a hash which is equal for equal bitsets
+func (b *Bitset) Hash() uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(b.Key()))
+	return hash.Sum64()
+}
+
+// trimmedWords drops trailing zero words, which don't change the set but the size only
+func (b *Bitset) trimmedWords() []uint64 {
+	end := len(b.words)
+	for end > 0 && b.words[end-1] == 0 {
+		end--
+	}
+	return b.words[:end]
+}
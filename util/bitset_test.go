@@ -0,0 +1,76 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitsetSetClearTest(t *testing.T) {
+	var b Bitset
+	for _, i := range []int{0, 5, 63, 64, 200} {
+		b.Set(i)
+	}
+	b.Clear(5)
+	b.Clear(1000)
+
+	for i := 0; i < 300; i++ {
+		expected := i == 0 || i == 63 || i == 64 || i == 200
+		if b.Test(i) != expected {
+			t.Errorf("Bit %d is %t. Expected %t", i, b.Test(i), expected)
+		}
+	}
+	if b.Count() != 4 {
+		t.Errorf("Count is %d. Expected 4", b.Count())
+	}
+	if ints := b.Ints(); !slices.Equal(ints, []int{0, 63, 64, 200}) {
+		t.Errorf("Unexpected bits: %v", ints)
+	}
+}
+
+func TestBitsetNext(t *testing.T) {
+	b := NewBitset(10)
+	b.Set(3)
+	b.Set(130)
+
+	tests := []struct {
+		from     int
+		expected int
+		found    bool
+	}{
+		{0, 3, true},
+		{3, 3, true},
+		{4, 130, true},
+		{131, 0, false},
+		{1000, 0, false},
+	}
+	for _, test := range tests {
+		next, found := b.Next(test.from)
+		if next != test.expected || found != test.found {
+			t.Errorf("Next from %d is %d, %t. Expected %d, %t", test.from, next, found,
+				test.expected, test.found)
+		}
+	}
+}
+
+func TestBitsetKeyIgnoresSize(t *testing.T) {
+	small := NewBitset(1)
+	small.Set(7)
+	big := NewBitset(1000)
+	big.Set(7)
+	big.Set(900)
+	big.Clear(900)
+
+	if !small.Equal(big) || small.Key() != big.Key() || small.Hash() != big.Hash() {
+		t.Error("Bitsets with the same bits differ")
+	}
+
+	clone := small.Clone()
+	clone.Set(8)
+	if small.Test(8) || clone.Equal(small) || clone.Key() == small.Key() {
+		t.Error("Clone isn't independent of the original")
+	}
+
+	if !NewSet(small.Key()).Contains(big.Key()) {
+		t.Error("Equal bitsets have different map keys")
+	}
+}
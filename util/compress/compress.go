@@ -0,0 +1,117 @@
+// Package compress maps sparse coordinates to dense indexes, so grids spanning millions of cells
+// with only a few interesting coordinates can be flood filled or measured cell by cell.
+package compress
+
+import (
+	"slices"
+	"sort"
+)
+
+// Axis compresses coordinates of one axis. Every interesting coordinate gets a cell of width 1 and
+// every run of coordinates between two interesting ones is squashed into a single gap cell
+// remembering its original width. Cells are ordered like the coordinates they cover.
+type Axis struct {
+	// starts are first coordinates of cells followed by the end of the last cell
+	starts []int
+	// gapWidthsBefore[i] is the total width of gap cells before cell i
+	gapWidthsBefore []int
+}
+
+// NewAxis compresses the coordinates. They may be unsorted and repeat. The axis covers coordinates
+// from the smallest one to the biggest one; add padding coordinates to have cells around them.
+func NewAxis(coords ...int) *Axis {
+	points := slices.Clone(coords)
+	slices.Sort(points)
+	points = slices.Compact(points)
+
+	a := &Axis{}
+	var gaps []bool
+	for i, point := range points {
+		a.starts = append(a.starts, point)
+		gaps = append(gaps, false)
+		if i+1 < len(points) && points[i+1]-point > 1 {
+			a.starts = append(a.starts, point+1)
+			gaps = append(gaps, true)
+		}
+	}
+	if len(points) > 0 {
+		a.starts = append(a.starts, points[len(points)-1]+1)
+	}
+
+	a.gapWidthsBefore = make([]int, len(gaps)+1)
+	for i, gap := range gaps {
+		a.gapWidthsBefore[i+1] = a.gapWidthsBefore[i]
+		if gap {
+			a.gapWidthsBefore[i+1] += a.Width(i)
+		}
+	}
+	return a
+}
+
+// Len returns the number of cells
+func (a *Axis) Len() int {
+	return max(len(a.starts)-1, 0)
+}
+
+// Index returns the cell the coordinate falls into. It returns false for coordinates outside the
+// axis.
+func (a *Axis) Index(coord int) (int, bool) {
+	if a.Len() == 0 || coord < a.starts[0] || coord >= a.starts[len(a.starts)-1] {
+		return 0, false
+	}
+	return sort.SearchInts(a.starts, coord+1) - 1, true
+}
+
+// Start returns the first original coordinate of the cell
+func (a *Axis) Start(idx int) int {
+	return a.starts[idx]
+}
+
+// Width returns the number of original coordinates the cell covers
+func (a *Axis) Width(idx int) int {
+	return a.starts[idx+1] - a.starts[idx]
+}
+
+// IsGap tells if the cell covers coordinates between interesting ones
+func (a *Axis) IsGap(idx int) bool {
+	return a.gapWidthsBefore[idx+1] != a.gapWidthsBefore[idx]
+}
+
+// Span returns the original distance from the start of cell fromIdx to the start of cell toIdx.
+// It's negative if toIdx is before fromIdx. Len is a valid toIdx to reach the end of the axis.
+func (a *Axis) Span(fromIdx, toIdx int) int {
+	return a.starts[toIdx] - a.starts[fromIdx]
+}
+
+// GapsWidth returns the total width of gap cells in [fromIdx, toIdx)
+func (a *Axis) GapsWidth(fromIdx, toIdx int) int {
+	return a.gapWidthsBefore[toIdx] - a.gapWidthsBefore[fromIdx]
+}
+
+// Grid is a 2D grid compressed along both axes. Cell {col, row} stands for a rectangle of
+// X.Width(col) by Y.Width(row) original cells.
+type Grid struct {
+	X, Y *Axis
+}
+
+func NewGrid(xs, ys []int) Grid {
+	return Grid{NewAxis(xs...), NewAxis(ys...)}
+}
+
+// Size returns the number of compressed columns and rows
+func (g Grid) Size() (int, int) {
+	return g.X.Len(), g.Y.Len()
+}
+
+// Index returns the compressed cell of the original coordinates. It returns false for coordinates
+// outside the grid.
+func (g Grid) Index(x, y int) (int, int, bool) {
+	col, colFound := g.X.Index(x)
+	row, rowFound := g.Y.Index(y)
+	return col, row, colFound && rowFound
+}
+
+// Area returns the number of original cells the compressed cell covers
+func (g Grid) Area(col, row int) int {
+	return g.X.Width(col) * g.Y.Width(row)
+}
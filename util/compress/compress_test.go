@@ -0,0 +1,109 @@
+package compress
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAxis(t *testing.T) {
+	// cells: [2] [3] [4,9] [10] [11,99] [100]
+	a := NewAxis(100, 3, 10, 2, 10)
+	if a.Len() != 6 {
+		t.Fatalf("Unexpected length %d", a.Len())
+	}
+
+	var starts, widths []int
+	var gaps []bool
+	for i := 0; i < a.Len(); i++ {
+		starts = append(starts, a.Start(i))
+		widths = append(widths, a.Width(i))
+		gaps = append(gaps, a.IsGap(i))
+	}
+	if !slices.Equal(starts, []int{2, 3, 4, 10, 11, 100}) {
+		t.Errorf("Unexpected starts %v", starts)
+	}
+	if !slices.Equal(widths, []int{1, 1, 6, 1, 89, 1}) {
+		t.Errorf("Unexpected widths %v", widths)
+	}
+	if !slices.Equal(gaps, []bool{false, false, true, false, true, false}) {
+		t.Errorf("Unexpected gaps %v", gaps)
+	}
+
+	if span := a.Span(1, 5); span != 97 {
+		t.Errorf("Unexpected span %d", span)
+	}
+	if span := a.Span(0, a.Len()); span != 99 {
+		t.Errorf("Unexpected span of the whole axis %d", span)
+	}
+	if width := a.GapsWidth(1, 5); width != 95 {
+		t.Errorf("Unexpected gaps width %d", width)
+	}
+	if width := a.GapsWidth(3, 4); width != 0 {
+		t.Errorf("Unexpected gaps width %d", width)
+	}
+}
+
+func TestAxisIndex(t *testing.T) {
+	a := NewAxis(2, 3, 10, 100)
+	inputs := []struct {
+		coord int
+		idx   int
+		found bool
+	}{
+		{1, 0, false},
+		{2, 0, true},
+		{3, 1, true},
+		{4, 2, true},
+		{9, 2, true},
+		{10, 3, true},
+		{50, 4, true},
+		{100, 5, true},
+		{101, 0, false},
+	}
+
+	for _, input := range inputs {
+		idx, found := a.Index(input.coord)
+		if idx != input.idx || found != input.found {
+			t.Errorf("coord %d. wanted %d %t, got %d %t", input.coord, input.idx, input.found, idx,
+				found)
+		}
+		if found && (input.coord < a.Start(idx) || input.coord >= a.Start(idx)+a.Width(idx)) {
+			t.Errorf("coord %d is outside of its cell %d", input.coord, idx)
+		}
+	}
+}
+
+func TestEmptyAxis(t *testing.T) {
+	a := NewAxis()
+	if a.Len() != 0 {
+		t.Errorf("Unexpected length %d", a.Len())
+	}
+	if _, found := a.Index(0); found {
+		t.Error("Coordinate found on an empty axis")
+	}
+}
+
+func TestGridArea(t *testing.T) {
+	g := NewGrid([]int{0, 1_000_000}, []int{-5, 5})
+	cols, rows := g.Size()
+	if cols != 3 || rows != 3 {
+		t.Fatalf("Unexpected size %dx%d", cols, rows)
+	}
+
+	total := 0
+	for col := 0; col < cols; col++ {
+		for row := 0; row < rows; row++ {
+			total += g.Area(col, row)
+		}
+	}
+	if total != 1_000_001*11 {
+		t.Errorf("Cell areas don't add up to the original area: %d", total)
+	}
+
+	if col, row, found := g.Index(500, 0); !found || col != 1 || row != 1 {
+		t.Errorf("Unexpected index %d:%d %t", col, row, found)
+	}
+	if _, _, found := g.Index(500, 6); found {
+		t.Error("Coordinate outside of the grid found")
+	}
+}
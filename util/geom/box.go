@@ -0,0 +1,97 @@
+package geom
+
+// Plane is a coordinate plane a box is projected onto
+type Plane uint8
+
+const (
+	PlaneXY Plane = iota
+	PlaneXZ
+	PlaneYZ
+)
+
+// Box2 is an axis-aligned rectangle. Both corners are inclusive, so a single cell has Min == Max.
+type Box2 struct {
+	Min, Max Vec2
+}
+
+// Overlaps tells if boxes have at least one cell in common
+func (b Box2) Overlaps(other Box2) bool {
+	_, overlap := b.Intersection(other)
+	return overlap
+}
+
+// Intersection returns the cells boxes have in common. It returns false if there are none.
+func (b Box2) Intersection(other Box2) (Box2, bool) {
+	intersection := Box2{
+		Min: Vec2{max(b.Min.X, other.Min.X), max(b.Min.Y, other.Min.Y)},
+		Max: Vec2{min(b.Max.X, other.Max.X), min(b.Max.Y, other.Max.Y)},
+	}
+	if intersection.Min.X > intersection.Max.X || intersection.Min.Y > intersection.Max.Y {
+		return Box2{}, false
+	}
+	return intersection, true
+}
+
+// Box3 is an axis-aligned box. Both corners are inclusive, so a single cube has Min == Max.
+type Box3 struct {
+	Min, Max Vec3
+}
+
+// NewBox3 returns the box with the given opposite corners in any order
+func NewBox3(corner1, corner2 Vec3) Box3 {
+	return Box3{
+		Min: Vec3{min(corner1.X, corner2.X), min(corner1.Y, corner2.Y), min(corner1.Z, corner2.Z)},
+		Max: Vec3{max(corner1.X, corner2.X), max(corner1.Y, corner2.Y), max(corner1.Z, corner2.Z)},
+	}
+}
+
+// Size returns the number of cubes along every axis
+func (b Box3) Size() Vec3 {
+	return b.Max.Sub(b.Min).Add(Vec3{1, 1, 1})
+}
+
+func (b Box3) Contains(v Vec3) bool {
+	return v.X >= b.Min.X && v.X <= b.Max.X &&
+		v.Y >= b.Min.Y && v.Y <= b.Max.Y &&
+		v.Z >= b.Min.Z && v.Z <= b.Max.Z
+}
+
+// Overlaps tells if boxes have at least one cube in common
+func (b Box3) Overlaps(other Box3) bool {
+	_, overlap := b.Intersection(other)
+	return overlap
+}
+
+// Intersection returns the cubes boxes have in common. It returns false if there are none.
+func (b Box3) Intersection(other Box3) (Box3, bool) {
+	intersection := Box3{
+		Min: Vec3{max(b.Min.X, other.Min.X), max(b.Min.Y, other.Min.Y), max(b.Min.Z, other.Min.Z)},
+		Max: Vec3{min(b.Max.X, other.Max.X), min(b.Max.Y, other.Max.Y), min(b.Max.Z, other.Max.Z)},
+	}
+	if intersection.Min.X > intersection.Max.X || intersection.Min.Y > intersection.Max.Y ||
+		intersection.Min.Z > intersection.Max.Z {
+		return Box3{}, false
+	}
+	return intersection, true
+}
+
+// Translate returns the box moved by offset
+func (b Box3) Translate(offset Vec3) Box3 {
+	return Box3{b.Min.Add(offset), b.Max.Add(offset)}
+}
+
+// Project returns the shadow of the box on the plane, e.g. the XY projection drops Z
+func (b Box3) Project(plane Plane) Box2 {
+	return Box2{project(b.Min, plane), project(b.Max, plane)}
+}
+
+func project(v Vec3, plane Plane) Vec2 {
+	switch plane {
+	case PlaneXZ:
+		return Vec2{v.X, v.Z}
+	case PlaneYZ:
+		return Vec2{v.Y, v.Z}
+	default:
+		return Vec2{v.X, v.Y}
+	}
+}
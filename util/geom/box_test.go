@@ -0,0 +1,87 @@
+package geom
+
+import "testing"
+
+func TestBox2Overlaps(t *testing.T) {
+	base := Box2{Vec2{1, 1}, Vec2{1, 1}}
+	inputs := []struct {
+		other Box2
+		want  bool
+	}{
+		{Box2{Vec2{2, 1}, Vec2{2, 1}}, false},
+		{Box2{Vec2{1, 2}, Vec2{1, 2}}, false},
+		{Box2{Vec2{2, 2}, Vec2{2, 2}}, false},
+		{Box2{Vec2{1, 1}, Vec2{2, 2}}, true},
+		{Box2{Vec2{0, 1}, Vec2{5, 1}}, true},
+	}
+
+	for i, input := range inputs {
+		if got := base.Overlaps(input.other); got != input.want {
+			t.Errorf("box #%d. wanted %t, got %t", i, input.want, got)
+		}
+		if got := input.other.Overlaps(base); got != input.want {
+			t.Errorf("box #%d reversed. wanted %t, got %t", i, input.want, got)
+		}
+	}
+}
+
+func TestNewBox3(t *testing.T) {
+	b := NewBox3(Vec3{2, 0, 5}, Vec3{0, 3, 5})
+	want := Box3{Vec3{0, 0, 5}, Vec3{2, 3, 5}}
+	if b != want {
+		t.Errorf("wanted %v, got %v", want, b)
+	}
+	if size := b.Size(); size != (Vec3{3, 4, 1}) {
+		t.Errorf("Unexpected size %v", size)
+	}
+	if !b.Contains(Vec3{1, 3, 5}) || b.Contains(Vec3{1, 3, 6}) {
+		t.Error("Unexpected Contains result")
+	}
+}
+
+func TestBox3Intersection(t *testing.T) {
+	base := Box3{Vec3{0, 0, 0}, Vec3{2, 2, 2}}
+	inputs := []struct {
+		other   Box3
+		want    Box3
+		overlap bool
+	}{
+		{Box3{Vec3{1, 1, 1}, Vec3{5, 5, 5}}, Box3{Vec3{1, 1, 1}, Vec3{2, 2, 2}}, true},
+		{Box3{Vec3{2, 2, 2}, Vec3{3, 3, 3}}, Box3{Vec3{2, 2, 2}, Vec3{2, 2, 2}}, true},
+		{Box3{Vec3{-1, 1, -1}, Vec3{3, 1, 3}}, Box3{Vec3{0, 1, 0}, Vec3{2, 1, 2}}, true},
+		{Box3{Vec3{0, 0, 3}, Vec3{2, 2, 3}}, Box3{}, false},
+		// overlapping projections on all planes don't make boxes overlap
+		{Box3{Vec3{3, 0, 0}, Vec3{3, 2, 2}}, Box3{}, false},
+	}
+
+	for i, input := range inputs {
+		got, overlap := base.Intersection(input.other)
+		if got != input.want || overlap != input.overlap {
+			t.Errorf("box #%d. wanted %v %t, got %v %t", i, input.want, input.overlap, got, overlap)
+		}
+		if base.Overlaps(input.other) != input.overlap {
+			t.Errorf("box #%d. Overlaps disagrees with Intersection", i)
+		}
+	}
+}
+
+func TestBox3TranslateAndProject(t *testing.T) {
+	b := Box3{Vec3{1, 0, 5}, Vec3{1, 2, 5}}.Translate(Vec3{0, 1, -4})
+	if b != (Box3{Vec3{1, 1, 1}, Vec3{1, 3, 1}}) {
+		t.Fatalf("Unexpected translated box %v", b)
+	}
+
+	inputs := []struct {
+		plane Plane
+		want  Box2
+	}{
+		{PlaneXY, Box2{Vec2{1, 1}, Vec2{1, 3}}},
+		{PlaneXZ, Box2{Vec2{1, 1}, Vec2{1, 1}}},
+		{PlaneYZ, Box2{Vec2{1, 1}, Vec2{3, 1}}},
+	}
+	for _, input := range inputs {
+		if got := b.Project(input.plane); got != input.want {
+			t.Errorf("plane %d. wanted %v, got %v", input.plane, input.want, got)
+		}
+	}
+}
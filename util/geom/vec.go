@@ -0,0 +1,55 @@
+// Package geom has integer vectors and axis-aligned boxes for puzzles set in 2D and 3D space.
+package geom
+
+import "fmt"
+
+type Vec2 struct {
+	X, Y int
+}
+
+type Vec3 struct {
+	X, Y, Z int
+}
+
+func (v Vec3) Add(other Vec3) Vec3 {
+	return Vec3{v.X + other.X, v.Y + other.Y, v.Z + other.Z}
+}
+
+func (v Vec3) Sub(other Vec3) Vec3 {
+	return Vec3{v.X - other.X, v.Y - other.Y, v.Z - other.Z}
+}
+
+func (v Vec3) Scale(factor int) Vec3 {
+	return Vec3{v.X * factor, v.Y * factor, v.Z * factor}
+}
+
+func (v Vec3) Dot(other Vec3) int {
+	return v.X*other.X + v.Y*other.Y + v.Z*other.Z
+}
+
+// Cross returns the vector perpendicular to both vectors. For vectors lying in the XY plane only
+// its Z is non-zero, and its sign tells if other is turned counterclockwise from v.
+func (v Vec3) Cross(other Vec3) Vec3 {
+	return Vec3{
+		v.Y*other.Z - v.Z*other.Y,
+		v.Z*other.X - v.X*other.Z,
+		v.X*other.Y - v.Y*other.X,
+	}
+}
+
+func (v Vec3) ManhattanDistance(other Vec3) int {
+	diff := v.Sub(other)
+	return abs(diff.X) + abs(diff.Y) + abs(diff.Z)
+}
+
+// String formats the vector the way puzzle inputs do: 1,2,3
+func (v Vec3) String() string {
+	return fmt.Sprintf("%d,%d,%d", v.X, v.Y, v.Z)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
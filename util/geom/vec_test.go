@@ -0,0 +1,49 @@
+package geom
+
+import "testing"
+
+func TestVec3Arithmetic(t *testing.T) {
+	a, b := Vec3{1, 2, 3}, Vec3{-4, 5, 6}
+
+	if got := a.Add(b); got != (Vec3{-3, 7, 9}) {
+		t.Errorf("Add: %v", got)
+	}
+	if got := a.Sub(b); got != (Vec3{5, -3, -3}) {
+		t.Errorf("Sub: %v", got)
+	}
+	if got := a.Scale(-2); got != (Vec3{-2, -4, -6}) {
+		t.Errorf("Scale: %v", got)
+	}
+	if got := a.Dot(b); got != 24 {
+		t.Errorf("Dot: %d", got)
+	}
+	if got := a.ManhattanDistance(b); got != 11 {
+		t.Errorf("ManhattanDistance: %d", got)
+	}
+	if got := a.String(); got != "1,2,3" {
+		t.Errorf("String: %s", got)
+	}
+}
+
+func TestVec3Cross(t *testing.T) {
+	inputs := []struct {
+		a, b, want Vec3
+	}{
+		{Vec3{1, 0, 0}, Vec3{0, 1, 0}, Vec3{0, 0, 1}},
+		{Vec3{0, 1, 0}, Vec3{1, 0, 0}, Vec3{0, 0, -1}},
+		{Vec3{0, 1, 0}, Vec3{0, 0, 1}, Vec3{1, 0, 0}},
+		{Vec3{2, 3, 4}, Vec3{2, 3, 4}, Vec3{}},
+		{Vec3{1, 2, 3}, Vec3{-4, 5, 6}, Vec3{-3, -18, 13}},
+	}
+
+	for _, input := range inputs {
+		got := input.a.Cross(input.b)
+		if got != input.want {
+			t.Errorf("%v x %v: wanted %v, got %v", input.a, input.b, input.want, got)
+		}
+		// the cross product is perpendicular to both vectors
+		if got.Dot(input.a) != 0 || got.Dot(input.b) != 0 {
+			t.Errorf("%v x %v = %v isn't perpendicular to the vectors", input.a, input.b, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package grid
+
+import "fmt"
+
+// Dense is a bounded grid with a value for every cell
+type Dense[T any] struct {
+	rows, cols int
+	cells      []T
+}
+
+// NewDense returns a grid of zero values
+func NewDense[T any](rows, cols int) *Dense[T] {
+	return &Dense[T]{rows, cols, make([]T, rows*cols)}
+}
+
+// FromLines returns a grid of bytes of the lines. All lines must have the same length.
+func FromLines(lines []string) (*Dense[byte], error) {
+	if len(lines) == 0 {
+		return NewDense[byte](0, 0), nil
+	}
+
+	g := NewDense[byte](len(lines), len(lines[0]))
+	for rowIdx, line := range lines {
+		if len(line) != g.cols {
+			return nil, fmt.Errorf("Row %d: Unexpected length %d. Expected %d", rowIdx+1, len(line),
+				g.cols)
+		}
+		copy(g.cells[rowIdx*g.cols:], line)
+	}
+	return g, nil
+}
+
+func (g *Dense[T]) Rows() int {
+	return g.rows
+}
+
+func (g *Dense[T]) Cols() int {
+	return g.cols
+}
+
+func (g *Dense[T]) At(p Point) T {
+	return g.cells[g.idx(p)]
+}
+
+func (g *Dense[T]) Set(p Point, value T) {
+	g.cells[g.idx(p)] = value
+}
+
+func (g *Dense[T]) Contains(p Point) bool {
+	return p.Row >= 0 && p.Row < g.rows && p.Col >= 0 && p.Col < g.cols
+}
+
+func (g *Dense[T]) Bounds() Rect {
+	return Rect{Max: Point{g.rows, g.cols}}
+}
+
+func (g *Dense[T]) idx(p Point) int {
+	// a column out of bounds would silently point into a neighbouring row otherwise
+	if !g.Contains(p) {
+		panic(fmt.Errorf("Point %d:%d is outside of the %dx%d grid", p.Row, p.Col, g.rows, g.cols))
+	}
+	return p.Row*g.cols + p.Col
+}
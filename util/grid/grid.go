@@ -0,0 +1,70 @@
+// Package grid has 2D grids of cells: a dense one backed by a slice, a tiled one repeating a dense
+// tile infinitely in all directions and a sparse one storing only cells differing from a default
+// value. They share the Grid interface, so neighbours and rendering work the same on all of them.
+package grid
+
+import "strings"
+
+// Point is a position of a cell. Rows grow down and columns grow right.
+type Point struct {
+	Row, Col int
+}
+
+func (p Point) Add(other Point) Point {
+	return Point{p.Row + other.Row, p.Col + other.Col}
+}
+
+// Directions are offsets of the neighbours of a cell: right, down, left and up
+var Directions = [4]Point{
+	{0, 1},
+	{1, 0},
+	{0, -1},
+	{-1, 0},
+}
+
+// Rect is a rectangle of cells. Min is inclusive and Max is exclusive, so an empty rectangle has
+// Min == Max.
+type Rect struct {
+	Min, Max Point
+}
+
+func (r Rect) Contains(p Point) bool {
+	return p.Row >= r.Min.Row && p.Row < r.Max.Row && p.Col >= r.Min.Col && p.Col < r.Max.Col
+}
+
+type Grid[T any] interface {
+	// At returns the value of the cell. Dense grids panic for points they don't contain.
+	At(p Point) T
+	Set(p Point, value T)
+	// Contains tells if the point is on the grid. Tiled and sparse grids are unbounded.
+	Contains(p Point) bool
+	// Bounds returns the rectangle worth looking at: the whole dense grid, a single tile of a tiled
+	// grid or the smallest rectangle with all the cells set on a sparse grid
+	Bounds() Rect
+}
+
+// Neighbours returns the neighbours of the point which are on the grid in the order of Directions
+func Neighbours[T any](g Grid[T], p Point) []Point {
+	neighbours := make([]Point, 0, len(Directions))
+	for _, d := range Directions {
+		if n := p.Add(d); g.Contains(n) {
+			neighbours = append(neighbours, n)
+		}
+	}
+	return neighbours
+}
+
+// Format draws the bounds of the grid row by row
+func Format(g Grid[byte]) string {
+	bounds := g.Bounds()
+
+	rows := make([]string, 0, bounds.Max.Row-bounds.Min.Row)
+	row := make([]byte, bounds.Max.Col-bounds.Min.Col)
+	for rowIdx := bounds.Min.Row; rowIdx < bounds.Max.Row; rowIdx++ {
+		for colIdx := bounds.Min.Col; colIdx < bounds.Max.Col; colIdx++ {
+			row[colIdx-bounds.Min.Col] = g.At(Point{rowIdx, colIdx})
+		}
+		rows = append(rows, string(row))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,112 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDense(t *testing.T) {
+	g, err := FromLines([]string{"#..", ".#."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Rows() != 2 || g.Cols() != 3 {
+		t.Fatalf("Unexpected size %dx%d", g.Rows(), g.Cols())
+	}
+
+	g.Set(Point{1, 2}, '#')
+	if got := Format(g); got != "#..\n.##" {
+		t.Errorf("Unexpected grid:\n%s", got)
+	}
+
+	neighbours := Neighbours[byte](g, Point{0, 0})
+	if !slices.Equal(neighbours, []Point{{0, 1}, {1, 0}}) {
+		t.Errorf("Unexpected neighbours of a corner %v", neighbours)
+	}
+
+	if _, err := FromLines([]string{"#..", ".#"}); err == nil {
+		t.Error("Ragged lines are accepted")
+	}
+}
+
+func TestDenseAtOutOfBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Point outside of the grid didn't panic")
+		}
+	}()
+
+	// it would be cell {1, 0} if columns weren't checked
+	NewDense[int](2, 2).At(Point{0, 2})
+}
+
+func TestTiled(t *testing.T) {
+	tile, err := FromLines([]string{"ab", "cd", "ef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewTiled(tile)
+
+	inputs := []struct {
+		p     Point
+		value byte
+		tile  Point
+	}{
+		{Point{0, 0}, 'a', Point{0, 0}},
+		{Point{2, 1}, 'f', Point{0, 0}},
+		{Point{3, 2}, 'a', Point{1, 1}},
+		{Point{-1, -1}, 'f', Point{-1, -1}},
+		{Point{-3, -2}, 'a', Point{-1, -1}},
+		{Point{-4, 5}, 'f', Point{-2, 2}},
+	}
+	for _, input := range inputs {
+		if value := g.At(input.p); value != input.value {
+			t.Errorf("%v. wanted %c, got %c", input.p, input.value, value)
+		}
+		if tile := g.TileOf(input.p); tile != input.tile {
+			t.Errorf("%v. wanted tile %v, got %v", input.p, input.tile, tile)
+		}
+	}
+
+	if neighbours := Neighbours[byte](g, Point{0, 0}); len(neighbours) != 4 {
+		t.Errorf("Unexpected neighbours on an infinite grid %v", neighbours)
+	}
+
+	g.Set(Point{-3, 4}, 'x')
+	if tile.At(Point{0, 0}) != 'x' || g.At(Point{3, 2}) != 'x' {
+		t.Error("Setting a cell doesn't change all the copies of the tile")
+	}
+	if got := Format(g); got != "xb\ncd\nef" {
+		t.Errorf("Unexpected tile:\n%s", got)
+	}
+}
+
+func TestSparse(t *testing.T) {
+	g := NewSparse[byte]('.')
+	if g.Bounds() != (Rect{}) {
+		t.Errorf("Unexpected bounds of an empty grid %v", g.Bounds())
+	}
+
+	g.Set(Point{-1_000_000, 3}, '#')
+	g.Set(Point{-999_998, 1}, '#')
+	g.Set(Point{-999_999, 2}, '#')
+	g.Set(Point{-999_999, 2}, '.')
+
+	if g.Len() != 2 || g.At(Point{-999_999, 2}) != '.' {
+		t.Errorf("Setting the default value didn't remove the cell: %v", g.Points())
+	}
+	if points := g.Points(); !slices.Equal(points, []Point{{-1_000_000, 3}, {-999_998, 1}}) {
+		t.Errorf("Unexpected points %v", points)
+	}
+
+	bounds := g.Bounds()
+	if bounds != (Rect{Point{-1_000_000, 1}, Point{-999_997, 4}}) {
+		t.Errorf("Unexpected bounds %v", bounds)
+	}
+	if !bounds.Contains(Point{-999_998, 1}) || bounds.Contains(Point{-999_997, 1}) {
+		t.Error("Max of the bounds isn't exclusive")
+	}
+	if got := Format(g); got != "..#\n...\n#.." {
+		t.Errorf("Unexpected grid:\n%s", got)
+	}
+}
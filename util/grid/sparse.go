@@ -0,0 +1,78 @@
+package grid
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Sparse is an unbounded grid storing only cells differing from the default value, so its memory
+// depends on the number of such cells rather than on the area they span
+type Sparse[T comparable] struct {
+	defaultValue T
+	cells        map[Point]T
+}
+
+func NewSparse[T comparable](defaultValue T) *Sparse[T] {
+	return &Sparse[T]{defaultValue, make(map[Point]T)}
+}
+
+// At returns the default value for cells which weren't set
+func (g *Sparse[T]) At(p Point) T {
+	if value, found := g.cells[p]; found {
+		return value
+	}
+	return g.defaultValue
+}
+
+// Set stores the value. Setting the default value removes the cell.
+func (g *Sparse[T]) Set(p Point, value T) {
+	if value == g.defaultValue {
+		delete(g.cells, p)
+		return
+	}
+	g.cells[p] = value
+}
+
+func (g *Sparse[T]) Contains(Point) bool {
+	return true
+}
+
+// Bounds returns an empty rectangle at {0, 0} if no cells are set
+func (g *Sparse[T]) Bounds() Rect {
+	if len(g.cells) == 0 {
+		return Rect{}
+	}
+
+	var bounds Rect
+	first := true
+	for p := range g.cells {
+		if first {
+			bounds = Rect{p, p}
+			first = false
+		}
+		bounds.Min = Point{min(bounds.Min.Row, p.Row), min(bounds.Min.Col, p.Col)}
+		bounds.Max = Point{max(bounds.Max.Row, p.Row), max(bounds.Max.Col, p.Col)}
+	}
+	bounds.Max = bounds.Max.Add(Point{1, 1})
+	return bounds
+}
+
+// Len returns the number of cells set
+func (g *Sparse[T]) Len() int {
+	return len(g.cells)
+}
+
+// Points returns the cells set row by row
+func (g *Sparse[T]) Points() []Point {
+	points := make([]Point, 0, len(g.cells))
+	for p := range g.cells {
+		points = append(points, p)
+	}
+	slices.SortFunc(points, func(p1, p2 Point) int {
+		if p1.Row != p2.Row {
+			return cmp.Compare(p1.Row, p2.Row)
+		}
+		return cmp.Compare(p1.Col, p2.Col)
+	})
+	return points
+}
@@ -0,0 +1,52 @@
+package grid
+
+// Tiled repeats a dense tile infinitely in all directions. All the copies share cells, so setting
+// a cell changes it in every copy.
+type Tiled[T any] struct {
+	tile *Dense[T]
+}
+
+// NewTiled returns a grid repeating the tile. The tile must not be empty.
+func NewTiled[T any](tile *Dense[T]) *Tiled[T] {
+	if tile.rows == 0 || tile.cols == 0 {
+		panic("Tile is empty")
+	}
+	return &Tiled[T]{tile}
+}
+
+// Wrap returns the point of the tile the point is a copy of
+func (g *Tiled[T]) Wrap(p Point) Point {
+	return Point{mod(p.Row, g.tile.rows), mod(p.Col, g.tile.cols)}
+}
+
+// TileOf returns the position of the copy of the tile the point is on. The original tile is at
+// {0, 0}.
+func (g *Tiled[T]) TileOf(p Point) Point {
+	return Point{floorDiv(p.Row, g.tile.rows), floorDiv(p.Col, g.tile.cols)}
+}
+
+func (g *Tiled[T]) At(p Point) T {
+	return g.tile.At(g.Wrap(p))
+}
+
+func (g *Tiled[T]) Set(p Point, value T) {
+	g.tile.Set(g.Wrap(p), value)
+}
+
+func (g *Tiled[T]) Contains(Point) bool {
+	return true
+}
+
+func (g *Tiled[T]) Bounds() Rect {
+	return g.tile.Bounds()
+}
+
+// mod returns the non-negative remainder unlike %
+func mod(a, b int) int {
+	return (a%b + b) % b
+}
+
+// floorDiv rounds the quotient down unlike /, which rounds it towards zero
+func floorDiv(a, b int) int {
+	return (a - mod(a, b)) / b
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// WorkerPanic is raised by Pool.Wait when a task panics. It keeps the stack of the worker, which
+// is lost otherwise.
+type WorkerPanic struct {
+	Value any
+	Stack []byte
+}
+
+func (p *WorkerPanic) Error() string {
+	return fmt.Sprintf("Worker panicked: %v\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the panic value if it's an error
+func (p *WorkerPanic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// Pool runs tasks on a bounded number of goroutines. Tasks are submitted with Go; Wait must be
+// called once all of them are submitted.
+type Pool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+
+	mu         sync.Mutex
+	firstPanic *WorkerPanic
+}
+
+// WorkersCount is the default pool size. Work is CPU-bound, so there is no point in having more
+// workers than threads running Go code at once.
+func WorkersCount() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// NewPool starts a pool of the given number of workers. Zero or a negative number means
+// WorkersCount.
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		workers = WorkersCount()
+	}
+
+	p := &Pool{tasks: make(chan func())}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Go submits the task. It blocks till a worker is free to take it.
+func (p *Pool) Go(task func()) {
+	p.tasks <- task
+}
+
+// Wait waits for all submitted tasks and stops the workers. If any task panicked, Wait panics with
+// *WorkerPanic of the first one; tasks that haven't started by then are skipped.
+func (p *Pool) Wait() {
+	close(p.tasks)
+	p.wg.Wait()
+
+	if p.firstPanic != nil {
+		panic(p.firstPanic)
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		if !p.hasPanicked() {
+			p.run(task)
+		}
+	}
+}
+
+func (p *Pool) run(task func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if p.firstPanic == nil {
+				p.firstPanic = &WorkerPanic{v, debug.Stack()}
+			}
+		}
+	}()
+	task()
+}
+
+func (p *Pool) hasPanicked() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.firstPanic != nil
+}
+
+// ParallelMap applies fn to every item on WorkersCount goroutines. Results are in the order of
+// the items. A panic in fn is raised again in the caller as *WorkerPanic.
+func ParallelMap[T, R any](items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+
+	pool := NewPool(min(WorkersCount(), max(len(items), 1)))
+	for i := range items {
+		idx := i
+		pool.Go(func() {
+			results[idx] = fn(items[idx])
+		})
+	}
+	pool.Wait()
+
+	return results
+}
+
+// ParallelReduce maps items in parallel like ParallelMap and folds the results in the order of
+// the items starting with initial, so reduce doesn't have to be commutative.
+func ParallelReduce[T, R, A any](items []T, mapFn func(T) R, initial A, reduce func(A, R) A) A {
+	acc := initial
+	for _, result := range ParallelMap(items, mapFn) {
+		acc = reduce(acc, result)
+	}
+	return acc
+}
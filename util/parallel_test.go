@@ -0,0 +1,91 @@
+package util
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelMapKeepsOrder(t *testing.T) {
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	squares := ParallelMap(items, func(i int) int {
+		return i * i
+	})
+	for i, square := range squares {
+		if square != i*i {
+			t.Fatalf("Result %d is %d. Expected %d", i, square, i*i)
+		}
+	}
+
+	if results := ParallelMap([]int{}, func(i int) int { return i }); len(results) != 0 {
+		t.Errorf("Results %v for no items", results)
+	}
+}
+
+func TestParallelReduceFoldsInOrder(t *testing.T) {
+	words := []string{"a", "b", "c", "d", "e", "f"}
+	joined := ParallelReduce(words, func(w string) string {
+		return w + w
+	}, "", func(acc, w string) string {
+		return acc + w
+	})
+	if joined != "aabbccddeeff" {
+		t.Errorf("Reduced to %s", joined)
+	}
+}
+
+func TestPoolBoundsWorkers(t *testing.T) {
+	const workers = 3
+	var running, maxRunning atomic.Int32
+
+	pool := NewPool(workers)
+	for i := 0; i < 50; i++ {
+		pool.Go(func() {
+			n := running.Add(1)
+			for {
+				prevMax := maxRunning.Load()
+				if n <= prevMax || maxRunning.CompareAndSwap(prevMax, n) {
+					break
+				}
+			}
+			running.Add(-1)
+		})
+	}
+	pool.Wait()
+
+	if maxRunning.Load() > workers {
+		t.Errorf("%d tasks ran at once. Expected at most %d", maxRunning.Load(), workers)
+	}
+}
+
+func TestParallelMapPropagatesPanic(t *testing.T) {
+	errBoom := errors.New("Boom")
+	defer func() {
+		v := recover()
+		workerPanic, ok := v.(*WorkerPanic)
+		if !ok {
+			t.Fatalf("Unexpected panic value %v", v)
+		}
+		if !errors.Is(workerPanic, errBoom) || len(workerPanic.Stack) == 0 {
+			t.Errorf("Unexpected worker panic: %v", workerPanic)
+		}
+	}()
+
+	ParallelMap([]int{1, 2, 3}, func(i int) int {
+		if i == 2 {
+			panic(errBoom)
+		}
+		return i
+	})
+	t.Error("No panic")
+}
+
+func TestWorkersCount(t *testing.T) {
+	if WorkersCount() < 1 {
+		t.Errorf("Workers count is %d", WorkersCount())
+	}
+}
@@ -0,0 +1,135 @@
+package util
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Set is an unordered collection of distinct items. It's a map, so len and range work on it and
+// the zero value is a read-only empty set like a nil map. Use NewSet to get a writable one.
+type Set[T comparable] map[T]struct{}
+
+func NewSet[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+	return s
+}
+
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+func (s Set[T]) Contains(item T) bool {
+	_, found := s[item]
+	return found
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+func (s Set[T]) Clone() Set[T] {
+	clone := make(Set[T], len(s))
+	for item := range s {
+		clone[item] = struct{}{}
+	}
+	return clone
+}
+
+// Union returns a new set with items of both sets
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	union := s.Clone()
+	for item := range other {
+		union[item] = struct{}{}
+	}
+	return union
+}
+
+// Intersection returns a new set with items present in both sets
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	smaller, bigger := s, other
+	if len(smaller) > len(bigger) {
+		smaller, bigger = bigger, smaller
+	}
+
+	intersection := make(Set[T])
+	for item := range smaller {
+		if bigger.Contains(item) {
+			intersection[item] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+// Difference returns a new set with items of s absent in other
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	difference := make(Set[T])
+	for item := range s {
+		if !other.Contains(item) {
+			difference[item] = struct{}{}
+		}
+	}
+	return difference
+}
+
+func (s Set[T]) IsSubsetOf(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for item := range s {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Set[T]) IsSupersetOf(other Set[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubsetOf(other)
+}
+
+// Any returns an arbitrary item. It fails if the set is empty.
+func (s Set[T]) Any() (T, error) {
+	return GetAnyMapKey(s)
+}
+
+// Slice returns items in no particular order
+func (s Set[T]) Slice() []T {
+	return MapKeysToSlice(s)
+}
+
+// SortedFunc returns items sorted with cmp, so the iteration order doesn't depend on the map
+func (s Set[T]) SortedFunc(cmp func(T, T) int) []T {
+	items := s.Slice()
+	slices.SortFunc(items, cmp)
+	return items
+}
+
+// String formats items sorted by their formatted values, so printing a set is deterministic
+func (s Set[T]) String() string {
+	strs := make([]string, 0, len(s))
+	for item := range s {
+		strs = append(strs, fmt.Sprint(item))
+	}
+	slices.Sort(strs)
+	return "{" + strings.Join(strs, " ") + "}"
+}
+
+// SortedSetItems returns items of a set of ordered values in ascending order
+func SortedSetItems[T cmp.Ordered](s Set[T]) []T {
+	return MapKeysToSortedSlice(s)
+}
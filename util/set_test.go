@@ -0,0 +1,82 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	s1 := NewSet(1, 2, 3, 4)
+	s2 := NewSet(3, 4, 5)
+
+	tests := []struct {
+		name     string
+		set      Set[int]
+		expected []int
+	}{
+		{"union", s1.Union(s2), []int{1, 2, 3, 4, 5}},
+		{"intersection", s1.Intersection(s2), []int{3, 4}},
+		{"difference", s1.Difference(s2), []int{1, 2}},
+		{"reverse difference", s2.Difference(s1), []int{5}},
+	}
+	for _, test := range tests {
+		if items := SortedSetItems(test.set); !slices.Equal(items, test.expected) {
+			t.Errorf("%s: %v. Expected %v", test.name, items, test.expected)
+		}
+	}
+
+	// operations don't modify their operands
+	if !s1.Equal(NewSet(4, 3, 2, 1)) || !s2.Equal(NewSet(5, 4, 3)) {
+		t.Errorf("Operands are modified: %v, %v", s1, s2)
+	}
+}
+
+func TestSetSubsets(t *testing.T) {
+	small, big := NewSet("a"), NewSet("a", "b")
+
+	if !small.IsSubsetOf(big) || big.IsSubsetOf(small) {
+		t.Error("Unexpected subset check")
+	}
+	if !big.IsSupersetOf(small) || small.IsSupersetOf(big) {
+		t.Error("Unexpected superset check")
+	}
+	if !NewSet[string]().IsSubsetOf(small) || !small.IsSubsetOf(small) {
+		t.Error("Empty set and set itself must be subsets")
+	}
+}
+
+func TestSetCloneAndModify(t *testing.T) {
+	s := NewSet("a", "b")
+	clone := s.Clone()
+	clone.Add("c")
+	clone.Remove("a")
+
+	if !s.Equal(NewSet("a", "b")) {
+		t.Errorf("Original set is modified: %v", s)
+	}
+	if !clone.Contains("c") || clone.Contains("a") || clone.Len() != 2 {
+		t.Errorf("Unexpected clone: %v", clone)
+	}
+}
+
+func TestSetDeterministicOrder(t *testing.T) {
+	type coord struct{ row, col int }
+	s := NewSet(coord{2, 1}, coord{1, 2}, coord{1, 1})
+
+	sorted := s.SortedFunc(func(c1, c2 coord) int {
+		if c1.row != c2.row {
+			return c1.row - c2.row
+		}
+		return c1.col - c2.col
+	})
+	if !slices.Equal(sorted, []coord{{1, 1}, {1, 2}, {2, 1}}) {
+		t.Errorf("Unexpected order: %v", sorted)
+	}
+	if str := s.String(); str != "{{1 1} {1 2} {2 1}}" {
+		t.Errorf("Unexpected string: %s", str)
+	}
+
+	if _, err := NewSet[int]().Any(); err == nil {
+		t.Error("No error for an item of empty set")
+	}
+}
@@ -0,0 +1,88 @@
+package strmatch
+
+// Matcher is an Aho–Corasick automaton. It's a trie of the patterns where every node also links
+// to the node of its longest proper suffix present in the trie, so a mismatch continues from
+// there instead of restarting the search at the next position of the text.
+type Matcher struct {
+	trie *Trie
+	// failLinks lead to the node of the longest proper suffix of the node's string
+	failLinks []int
+	// outputLinks lead to the closest node on the fail link chain ending a pattern or are -1
+	outputLinks []int
+}
+
+// NewMatcher builds the automaton of the patterns. It panics if any of them is empty.
+func NewMatcher(patterns ...string) *Matcher {
+	trie := NewTrie(patterns...)
+	m := &Matcher{
+		trie:        trie,
+		failLinks:   make([]int, len(trie.nodes)),
+		outputLinks: make([]int, len(trie.nodes)),
+	}
+	m.outputLinks[0] = -1
+
+	// links of a node depend on links of shorter strings, so nodes are linked breadth first
+	queue := []int{0}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for b, child := range trie.nodes[node].children {
+			queue = append(queue, child)
+
+			failLink := 0
+			if node != 0 {
+				failLink = m.next(m.failLinks[node], b)
+			}
+			m.failLinks[child] = failLink
+
+			if trie.nodes[failLink].pattern != noPattern {
+				m.outputLinks[child] = failLink
+			} else {
+				m.outputLinks[child] = m.outputLinks[failLink]
+			}
+		}
+	}
+
+	return m
+}
+
+// Len returns the number of patterns
+func (m *Matcher) Len() int {
+	return m.trie.Len()
+}
+
+func (m *Matcher) Pattern(idx int) string {
+	return m.trie.Pattern(idx)
+}
+
+// FindAll returns all occurrences of the patterns in the text ordered by their ends. Matches ending
+// at the same position go from the longest one.
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	node := 0
+	for i := 0; i < len(text); i++ {
+		node = m.next(node, text[i])
+
+		for output := node; output > 0; output = m.outputLinks[output] {
+			if pattern := m.trie.nodes[output].pattern; pattern != noPattern {
+				end := i + 1
+				matches = append(matches, Match{pattern, end - len(m.trie.patterns[pattern]), end})
+			}
+		}
+	}
+	return matches
+}
+
+// next returns the node the automaton moves to from the node on the byte
+func (m *Matcher) next(node int, b byte) int {
+	for {
+		if child, found := m.trie.nodes[node].children[b]; found {
+			return child
+		}
+		if node == 0 {
+			return 0
+		}
+		node = m.failLinks[node]
+	}
+}
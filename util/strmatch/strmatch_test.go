@@ -0,0 +1,109 @@
+package strmatch
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTrie(t *testing.T) {
+	trie := NewTrie("he", "she", "his", "hers")
+	if idx := trie.Add("she"); idx != 1 {
+		t.Errorf("Added again pattern got index %d", idx)
+	}
+	if trie.Len() != 4 || trie.Pattern(3) != "hers" {
+		t.Errorf("Unexpected patterns: %d", trie.Len())
+	}
+
+	if idx, found := trie.Find("his"); !found || idx != 2 {
+		t.Errorf("Pattern his isn't found: %d %t", idx, found)
+	}
+	if _, found := trie.Find("her"); found {
+		t.Error("Prefix of a pattern is found as a pattern")
+	}
+	if !trie.HasPrefix("her") || trie.HasPrefix("hex") {
+		t.Error("Unexpected HasPrefix result")
+	}
+
+	expected := []Match{{0, 0, 2}, {3, 0, 4}}
+	if matches := trie.MatchPrefixes("hersheys"); !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Prefixes %v. Expected %v", matches, expected)
+	}
+}
+
+func TestMatcherOverlapping(t *testing.T) {
+	m := NewMatcher("one", "two", "eight", "nine")
+
+	inputs := []struct {
+		text    string
+		matches []Match
+	}{
+		{"twone", []Match{{1, 0, 3}, {0, 2, 5}}},
+		{"eightwo", []Match{{2, 0, 5}, {1, 4, 7}}},
+		{"nineight", []Match{{3, 0, 4}, {2, 3, 8}}},
+		{"oneightwoneight", []Match{{0, 0, 3}, {2, 2, 7}, {1, 6, 9}, {0, 8, 11}, {2, 10, 15}}},
+		{"xyz", nil},
+	}
+
+	for _, input := range inputs {
+		if matches := m.FindAll(input.text); !reflect.DeepEqual(matches, input.matches) {
+			t.Errorf("%s: %v. Expected %v", input.text, matches, input.matches)
+		}
+	}
+}
+
+func TestMatcherSuffixPatterns(t *testing.T) {
+	m := NewMatcher("he", "she", "his", "hers", "e")
+
+	expected := []Match{{1, 1, 4}, {0, 2, 4}, {4, 3, 4}, {3, 2, 6}}
+	if matches := m.FindAll("ushers"); !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Matches %v. Expected %v", matches, expected)
+	}
+}
+
+// random texts over a small alphabet have lots of overlapping matches
+func TestMatcherMatchesNaiveSearch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomString := func(maxLen int) string {
+		var sb strings.Builder
+		for i := rnd.Intn(maxLen) + 1; i > 0; i-- {
+			sb.WriteByte(byte('a' + rnd.Intn(3)))
+		}
+		return sb.String()
+	}
+
+	for round := 0; round < 100; round++ {
+		var patterns []string
+		for i := rnd.Intn(8) + 1; i > 0; i-- {
+			patterns = append(patterns, randomString(4))
+		}
+		m := NewMatcher(patterns...)
+		text := randomString(50)
+
+		var expected []Match
+		for end := 1; end <= len(text); end++ {
+			for start := 0; start < end; start++ {
+				if idx, found := m.trie.Find(text[start:end]); found {
+					expected = append(expected, Match{idx, start, end})
+				}
+			}
+		}
+
+		matches := m.FindAll(text)
+		if !slices.Equal(matches, expected) {
+			t.Fatalf("Patterns %v, text %s: %v. Expected %v", patterns, text, matches, expected)
+		}
+	}
+}
+
+func TestEmptyPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Empty pattern is accepted")
+		}
+	}()
+
+	NewMatcher("a", "")
+}
@@ -0,0 +1,119 @@
+// Package strmatch finds many patterns in a text at once: Trie looks patterns up by prefixes and
+// Matcher, an Aho–Corasick automaton built on top of it, finds all occurrences of all patterns,
+// including overlapping ones, in a single pass over the text.
+package strmatch
+
+// Match is an occurrence of a pattern in a text. Start is inclusive and End is exclusive, so the
+// matched text is text[Start:End].
+type Match struct {
+	Pattern    int
+	Start, End int
+}
+
+const noPattern = -1
+
+type trieNode struct {
+	children map[byte]int
+	// pattern is the index of the pattern ending at the node or noPattern
+	pattern int
+}
+
+// Trie is a prefix tree of patterns. Patterns are identified by the order they are added in and
+// are compared byte by byte.
+type Trie struct {
+	nodes    []trieNode
+	patterns []string
+}
+
+// NewTrie returns a trie of the patterns. It panics if any of them is empty.
+func NewTrie(patterns ...string) *Trie {
+	t := &Trie{nodes: []trieNode{newTrieNode()}}
+	for _, pattern := range patterns {
+		t.Add(pattern)
+	}
+	return t
+}
+
+func newTrieNode() trieNode {
+	return trieNode{children: make(map[byte]int), pattern: noPattern}
+}
+
+// Add adds the pattern and returns its index. Adding a pattern again returns the index it got the
+// first time. It panics if the pattern is empty, as it would match everywhere.
+func (t *Trie) Add(pattern string) int {
+	if len(pattern) == 0 {
+		panic("Empty pattern")
+	}
+
+	node := 0
+	for i := 0; i < len(pattern); i++ {
+		child, found := t.nodes[node].children[pattern[i]]
+		if !found {
+			child = len(t.nodes)
+			t.nodes = append(t.nodes, newTrieNode())
+			t.nodes[node].children[pattern[i]] = child
+		}
+		node = child
+	}
+
+	if t.nodes[node].pattern == noPattern {
+		t.nodes[node].pattern = len(t.patterns)
+		t.patterns = append(t.patterns, pattern)
+	}
+	return t.nodes[node].pattern
+}
+
+// Len returns the number of patterns
+func (t *Trie) Len() int {
+	return len(t.patterns)
+}
+
+func (t *Trie) Pattern(idx int) string {
+	return t.patterns[idx]
+}
+
+// Find returns the index of the pattern equal to s
+func (t *Trie) Find(s string) (int, bool) {
+	node, found := t.walk(s)
+	if !found || t.nodes[node].pattern == noPattern {
+		return 0, false
+	}
+	return t.nodes[node].pattern, true
+}
+
+// HasPrefix tells if any pattern starts with the prefix
+func (t *Trie) HasPrefix(prefix string) bool {
+	_, found := t.walk(prefix)
+	return found
+}
+
+// MatchPrefixes returns patterns text starts with from the shortest one
+func (t *Trie) MatchPrefixes(text string) []Match {
+	var matches []Match
+	node := 0
+	for i := 0; i < len(text); i++ {
+		child, found := t.nodes[node].children[text[i]]
+		if !found {
+			break
+		}
+		node = child
+
+		if pattern := t.nodes[node].pattern; pattern != noPattern {
+			matches = append(matches, Match{pattern, 0, i + 1})
+		}
+	}
+	return matches
+}
+
+// walk returns the node s leads to from the root
+func (t *Trie) walk(s string) (int, bool) {
+	node := 0
+	for i := 0; i < len(s); i++ {
+		child, found := t.nodes[node].children[s[i]]
+		if !found {
+			return 0, false
+		}
+		node = child
+	}
+	return node, true
+}
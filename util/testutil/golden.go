@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "overwrite golden files with the actual output")
+
+// AssertGolden compares the output with the content of the golden file. When tests are run with
+// the -update flag, the golden file is overwritten with the output instead. Paths are resolved
+// relatively to the directory of the tested package.
+func AssertGolden(t *testing.T, goldenPath string, output string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for golden file <%s>: %s", goldenPath, err.Error())
+		}
+		if err := os.WriteFile(goldenPath, []byte(output), 0644); err != nil {
+			t.Fatalf("Failed to update golden file <%s>: %s", goldenPath, err.Error())
+		}
+		return
+	}
+
+	data, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("Failed to read golden file <%s>: %s. Run the test with -update to create it",
+			goldenPath, err.Error())
+	}
+
+	if expected := string(data); output != expected {
+		t.Errorf("Output differs from golden file <%s>; run the test with -update if the change "+
+			"is intended\n%s", goldenPath, describeFirstDifference(expected, output))
+	}
+}
+
+// ReadSampleLines reads the sample file and splits it into lines the way util.ReadInputFile does
+func ReadSampleLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read sample <%s>: %s", path, err.Error())
+	}
+	return strings.Split(string(data), "\n")
+}
+
+func describeFirstDifference(expected, actual string) string {
+	expectedLines, actualLines := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var expectedLine, actualLine string
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+
+		if expectedLine != actualLine || i >= len(expectedLines) || i >= len(actualLines) {
+			return fmt.Sprintf("First difference at line %d of %d:\nexpected: %q\nactual:   %q", i+1,
+				len(expectedLines), expectedLine, actualLine)
+		}
+	}
+	return "Outputs differ"
+}
@@ -0,0 +1,60 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// AddSampleSeeds adds the content of every file matching the glob patterns to the seed corpus of
+// the fuzz target. Patterns are resolved relatively to the directory of the tested package.
+func AddSampleSeeds(f *testing.F, patterns ...string) {
+	f.Helper()
+
+	var seedsAdded uint
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			f.Fatalf("Invalid sample pattern <%s>: %s", pattern, err.Error())
+		}
+
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				f.Fatalf("Failed to read sample <%s>: %s", path, err.Error())
+			}
+
+			f.Add(string(data))
+			seedsAdded++
+		}
+	}
+
+	if seedsAdded == 0 {
+		f.Fatalf("No samples found by patterns %v", patterns)
+	}
+}
+
+// FuzzLines fuzzes a parser of whole inputs split into lines the way util.ReadInputFile does.
+// Samples matching the patterns are the seed corpus. fuzz may check properties of what's parsed;
+// a panic fails the target anyway.
+func FuzzLines(f *testing.F, patterns []string, fuzz func(t *testing.T, lines []string)) {
+	f.Helper()
+
+	AddSampleSeeds(f, patterns...)
+	f.Fuzz(func(t *testing.T, input string) {
+		fuzz(t, strings.Split(input, "\n"))
+	})
+}
+
+// FuzzEachLine is FuzzLines for parsers of single lines
+func FuzzEachLine(f *testing.F, patterns []string,
+	fuzz func(t *testing.T, lineIdx int, line string)) {
+	f.Helper()
+
+	FuzzLines(f, patterns, func(t *testing.T, lines []string) {
+		for lineIdx, line := range lines {
+			fuzz(t, lineIdx, line)
+		}
+	})
+}
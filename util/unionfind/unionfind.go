@@ -0,0 +1,179 @@
+// Package unionfind implements disjoint sets with path compression and union by size, so finding
+// the set of an element and merging sets take nearly constant time.
+package unionfind
+
+// Ints are disjoint sets of ints in [0, n)
+type Ints struct {
+	parents []int
+	// sizes are valid for roots only
+	sizes     []int
+	setsTotal int
+}
+
+// NewInts returns n sets of one element each
+func NewInts(n int) *Ints {
+	u := &Ints{}
+	u.grow(n)
+	return u
+}
+
+func (u *Ints) grow(n int) {
+	for x := len(u.parents); x < n; x++ {
+		u.parents = append(u.parents, x)
+		u.sizes = append(u.sizes, 1)
+		u.setsTotal++
+	}
+}
+
+// Len returns the number of elements
+func (u *Ints) Len() int {
+	return len(u.parents)
+}
+
+// Find returns the root element of the set x belongs to
+func (u *Ints) Find(x int) int {
+	root := x
+	for u.parents[root] != root {
+		root = u.parents[root]
+	}
+
+	// path compression: all elements on the way point to the root directly
+	for u.parents[x] != root {
+		x, u.parents[x] = u.parents[x], root
+	}
+	return root
+}
+
+// Union merges sets of x and y. It returns false if they are in the same set already.
+func (u *Ints) Union(x, y int) bool {
+	xRoot, yRoot := u.Find(x), u.Find(y)
+	if xRoot == yRoot {
+		return false
+	}
+
+	// the smaller tree is attached to the bigger one, so trees stay shallow
+	if u.sizes[xRoot] < u.sizes[yRoot] {
+		xRoot, yRoot = yRoot, xRoot
+	}
+	u.parents[yRoot] = xRoot
+	u.sizes[xRoot] += u.sizes[yRoot]
+	u.setsTotal--
+	return true
+}
+
+func (u *Ints) Connected(x, y int) bool {
+	return u.Find(x) == u.Find(y)
+}
+
+// Size returns the size of the set x belongs to
+func (u *Ints) Size(x int) int {
+	return u.sizes[u.Find(x)]
+}
+
+// SetsTotal returns the number of disjoint sets
+func (u *Ints) SetsTotal() int {
+	return u.setsTotal
+}
+
+// Components returns elements of every set. Sets are ordered by their smallest elements and
+// elements of a set are ascending.
+func (u *Ints) Components() [][]int {
+	components := make([][]int, 0, u.setsTotal)
+	componentIdxByRoot := make(map[int]int, u.setsTotal)
+	for x := range u.parents {
+		root := u.Find(x)
+		componentIdx, found := componentIdxByRoot[root]
+		if !found {
+			componentIdx = len(components)
+			componentIdxByRoot[root] = componentIdx
+			components = append(components, make([]int, 0, u.sizes[root]))
+		}
+		components[componentIdx] = append(components[componentIdx], x)
+	}
+	return components
+}
+
+// ComponentSizes returns sizes of the sets in the order of Components
+func (u *Ints) ComponentSizes() []int {
+	components := u.Components()
+	sizes := make([]int, len(components))
+	for i, component := range components {
+		sizes[i] = len(component)
+	}
+	return sizes
+}
+
+// Keyed are disjoint sets of arbitrary comparable keys. Keys are added on first use.
+type Keyed[K comparable] struct {
+	ints     Ints
+	keys     []K
+	idxByKey map[K]int
+}
+
+func NewKeyed[K comparable]() *Keyed[K] {
+	return &Keyed[K]{idxByKey: map[K]int{}}
+}
+
+// Add adds the key as a set of its own if it's new
+func (u *Keyed[K]) Add(key K) {
+	u.idx(key)
+}
+
+func (u *Keyed[K]) idx(key K) int {
+	idx, found := u.idxByKey[key]
+	if !found {
+		idx = len(u.keys)
+		u.keys = append(u.keys, key)
+		u.idxByKey[key] = idx
+		u.ints.grow(idx + 1)
+	}
+	return idx
+}
+
+// Len returns the number of keys
+func (u *Keyed[K]) Len() int {
+	return len(u.keys)
+}
+
+// Find returns the representative key of the set the key belongs to
+func (u *Keyed[K]) Find(key K) K {
+	return u.keys[u.ints.Find(u.idx(key))]
+}
+
+// Union merges sets of the keys. It returns false if they are in the same set already.
+func (u *Keyed[K]) Union(key1, key2 K) bool {
+	return u.ints.Union(u.idx(key1), u.idx(key2))
+}
+
+func (u *Keyed[K]) Connected(key1, key2 K) bool {
+	return u.ints.Connected(u.idx(key1), u.idx(key2))
+}
+
+// Size returns the size of the set the key belongs to
+func (u *Keyed[K]) Size(key K) int {
+	return u.ints.Size(u.idx(key))
+}
+
+// SetsTotal returns the number of disjoint sets
+func (u *Keyed[K]) SetsTotal() int {
+	return u.ints.SetsTotal()
+}
+
+// Components returns keys of every set. Sets and keys in them are ordered by the time keys were
+// added.
+func (u *Keyed[K]) Components() [][]K {
+	idxComponents := u.ints.Components()
+	components := make([][]K, len(idxComponents))
+	for i, idxComponent := range idxComponents {
+		components[i] = make([]K, len(idxComponent))
+		for j, idx := range idxComponent {
+			components[i][j] = u.keys[idx]
+		}
+	}
+	return components
+}
+
+// ComponentSizes returns sizes of the sets in the order of Components
+func (u *Keyed[K]) ComponentSizes() []int {
+	return u.ints.ComponentSizes()
+}
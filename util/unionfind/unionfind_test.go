@@ -0,0 +1,66 @@
+package unionfind
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	u := NewInts(7)
+	u.Union(0, 3)
+	u.Union(4, 3)
+	u.Union(5, 6)
+	if u.Union(0, 4) {
+		t.Error("Union of elements of the same set returned true")
+	}
+
+	if !u.Connected(0, 4) || u.Connected(0, 5) || u.Connected(1, 2) {
+		t.Error("Unexpected connectivity")
+	}
+	if u.SetsTotal() != 4 || u.Size(4) != 3 || u.Size(1) != 1 {
+		t.Errorf("Unexpected sizes: %d sets, %d, %d", u.SetsTotal(), u.Size(4), u.Size(1))
+	}
+
+	expected := [][]int{{0, 3, 4}, {1}, {2}, {5, 6}}
+	if components := u.Components(); !reflect.DeepEqual(components, expected) {
+		t.Errorf("Components %v. Expected %v", components, expected)
+	}
+	if sizes := u.ComponentSizes(); !reflect.DeepEqual(sizes, []int{3, 1, 1, 2}) {
+		t.Errorf("Unexpected component sizes: %v", sizes)
+	}
+}
+
+func TestIntsLongChain(t *testing.T) {
+	const n = 100_000
+	u := NewInts(n)
+	for x := 1; x < n; x++ {
+		u.Union(x-1, x)
+	}
+	if u.SetsTotal() != 1 || u.Size(0) != n || !u.Connected(0, n-1) {
+		t.Errorf("Chain isn't merged into a single set: %d sets", u.SetsTotal())
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	type coord struct{ row, col int }
+	u := NewKeyed[coord]()
+	u.Union(coord{0, 0}, coord{0, 1})
+	u.Union(coord{5, 5}, coord{5, 6})
+	u.Add(coord{9, 9})
+	u.Union(coord{0, 1}, coord{1, 1})
+
+	if u.Len() != 6 || u.SetsTotal() != 3 {
+		t.Errorf("%d keys in %d sets. Expected 6 in 3", u.Len(), u.SetsTotal())
+	}
+	if u.Find(coord{1, 1}) != u.Find(coord{0, 0}) || u.Connected(coord{0, 0}, coord{5, 5}) {
+		t.Error("Unexpected connectivity")
+	}
+
+	expected := [][]coord{{{0, 0}, {0, 1}, {1, 1}}, {{5, 5}, {5, 6}}, {{9, 9}}}
+	if components := u.Components(); !reflect.DeepEqual(components, expected) {
+		t.Errorf("Components %v. Expected %v", components, expected)
+	}
+	if sizes := u.ComponentSizes(); !reflect.DeepEqual(sizes, []int{3, 2, 1}) {
+		t.Errorf("Unexpected component sizes: %v", sizes)
+	}
+}
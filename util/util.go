@@ -4,72 +4,137 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 )
 
+// ReadInputFile reads lines of the file passed as the only command line argument
 func ReadInputFile() ([]string, error) {
 	if len(os.Args) != 2 {
 		return nil, fmt.Errorf("Usage: %s <input-file-path>", os.Args[0])
 	}
 
-	inputeFilePath := os.Args[1]
-	fmt.Printf("Reading input from file <%s>\n", inputeFilePath)
+	return ReadFileLines(os.Args[1])
+}
+
+// ReadFileLines reads lines of the file. Solvers having flags read the input file left after
+// parsing them with it.
+func ReadFileLines(path string) ([]string, error) {
+	return ReadFileLinesLoggingTo(os.Stdout, path)
+}
+
+// ReadFileLinesLoggingTo is ReadFileLines writing its progress to w, so stdout can be kept for
+// machine-readable output
+func ReadFileLinesLoggingTo(w io.Writer, path string) ([]string, error) {
+	fmt.Fprintf(w, "Reading input from file <%s>\n", path)
 
-	data, err := os.ReadFile(inputeFilePath)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading file <%s>: %s", inputeFilePath, err.Error())
+		return nil, fmt.Errorf("Error reading file <%s>: %s", path, err.Error())
 	}
 
-	fmt.Printf("Read %d bytes\n", len(data))
+	fmt.Fprintf(w, "Read %d bytes\n", len(data))
 
 	lines := strings.Split(string(data), "\n")
-	fmt.Printf("Read %d lines\n", len(lines))
+	fmt.Fprintf(w, "Read %d lines\n", len(lines))
 
 	return lines, nil
 }
 
+// AllocsReportEnvVar makes ReportAllocs print allocations of the program if it's set. aoc sets it
+// to record allocations of solvers.
+const AllocsReportEnvVar = "AOC_REPORT_ALLOCS"
+
+// AllocsReportPrefix starts the stderr line of ReportAllocs, which has the format of
+// AllocsReportFormat
+const AllocsReportPrefix = "aoc-allocs:"
+
+// AllocsReportFormat is the format of the line with bytes and objects allocated by the program
+const AllocsReportFormat = AllocsReportPrefix + " bytes=%d objects=%d\n"
+
+// ReportAllocs prints bytes and objects allocated by the program so far to stderr if
+// AllocsReportEnvVar is set. Solvers defer it first thing in main.
+func ReportAllocs() {
+	if os.Getenv(AllocsReportEnvVar) == "" {
+		return
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	fmt.Fprintf(os.Stderr, AllocsReportFormat, stats.TotalAlloc, stats.Mallocs)
+}
+
 func PanicOnError(err error) {
 	if err != nil {
 		panic(err)
 	}
 }
 
-func ParseUintOrPanic(s string) uint {
+func ParseUint(s string) (uint, error) {
 	u, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
-		panic(errors.Join(fmt.Errorf("Failed to parse <%s> as uint", s), err))
+		return 0, errors.Join(fmt.Errorf("Failed to parse <%s> as uint", s), err)
 	}
-	return uint(u)
+	return uint(u), nil
 }
 
-func ParseIntOrPanic(s string) int {
+func ParseInt(s string) (int, error) {
 	i, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		panic(errors.Join(fmt.Errorf("Failed to parse <%s> as int", s), err))
+		return 0, errors.Join(fmt.Errorf("Failed to parse <%s> as int", s), err)
 	}
-	return int(i)
+	return int(i), nil
 }
 
-func StringsToUints(strs []string) []uint {
+func ParseUintOrPanic(s string) uint {
+	u, err := ParseUint(s)
+	PanicOnError(err)
+	return u
+}
+
+func ParseIntOrPanic(s string) int {
+	i, err := ParseInt(s)
+	PanicOnError(err)
+	return i
+}
+
+func ParseUints(strs []string) ([]uint, error) {
 	var res []uint
 	for _, s := range strs {
-		res = append(res, ParseUintOrPanic(s))
+		u, err := ParseUint(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, u)
 	}
-	return res
+	return res, nil
 }
 
-func StringsToInts(strs []string) []int {
+func ParseInts(strs []string) ([]int, error) {
 	var res []int
 	for _, s := range strs {
-		i, err := strconv.Atoi(s)
+		i, err := ParseInt(s)
 		if err != nil {
-			panic(errors.Join(fmt.Errorf("Failed to parse <%s> as int", s), err))
+			return nil, err
 		}
 		res = append(res, i)
 	}
+	return res, nil
+}
+
+func StringsToUints(strs []string) []uint {
+	res, err := ParseUints(strs)
+	PanicOnError(err)
+	return res
+}
+
+func StringsToInts(strs []string) []int {
+	res, err := ParseInts(strs)
+	PanicOnError(err)
 	return res
 }
 